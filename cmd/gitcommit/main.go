@@ -13,6 +13,22 @@ import (
 // version is set via ldflags during build.
 var version = "dev"
 
+// subcommands maps the name of each subcommand to its entry point, which
+// receives the arguments following the name.
+var subcommands = map[string]func(args []string) error{
+	"edit-dates":   cli.RunEditDates,
+	"log":          cli.RunLog,
+	"merge":        cli.RunMerge,
+	"pick":         cli.RunPick,
+	"provenance":   cli.RunProvenance,
+	"redate":       cli.RunRedate,
+	"shift":        cli.RunShift,
+	"spread":       cli.RunSpread,
+	"tag":          cli.RunTag,
+	"tz-normalize": cli.RunTZNormalize,
+	"undo":         cli.RunUndo,
+}
+
 func main() {
 	// Setup structured logging with slog
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
@@ -20,6 +36,18 @@ func main() {
 	}))
 	slog.SetDefault(logger)
 
+	// Dispatch subcommands, which parse their own flags
+	cli.ToolVersion = version
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(cli.ExitError)
+			}
+			os.Exit(cli.ExitSuccess)
+		}
+	}
+
 	// Create configuration
 	config := cli.NewConfig(version)
 
@@ -28,7 +56,38 @@ func main() {
 	flag.BoolVar(&config.ShowHelp, "h", false, "Show help message (shorthand)")
 	flag.BoolVar(&config.ShowVersion, "version", false, "Show version information")
 	flag.BoolVar(&config.ShowVersion, "v", false, "Show version information (shorthand)")
-	flag.Parse()
+	flag.StringVar(&config.ChronologyBasis, "chronology-basis", config.ChronologyBasis,
+		"Date of the last commit to check against: author, committer or max")
+	flag.IntVar(&config.ChronologyDepth, "chronology-depth", config.ChronologyDepth,
+		"Generations of parents of HEAD and MERGE_HEAD to check against")
+	flag.Var(&config.Against, "against", "Also require dates after the tip of this ref (repeatable)")
+	flag.BoolVar(&config.AgainstAllRefs, "against-all-refs", false,
+		"Require dates after the tips of all branches, remote-tracking branches and tags")
+	flag.BoolVar(&config.AllowEmpty, "allow-empty", false, "Allow a commit that records no changes")
+	flag.BoolVar(&config.All, "all", false, "Stage all modified and deleted tracked files")
+	flag.BoolVar(&config.All, "a", false, "Stage all modified and deleted tracked files (shorthand)")
+	flag.BoolVar(&config.ShowGitOutput, "show-git-output", false, "Echo git's own output, including on failure")
+	flag.BoolVar(&config.Amend, "amend", false, "Re-date the last commit instead of creating a new one")
+	flag.BoolVar(&config.KeepContent, "keep-content", false,
+		"With --amend, keep the last commit's content and ignore staged changes")
+	flag.BoolVar(&config.Force, "force", false, "Amend even if the last commit is on a remote-tracking branch")
+	flag.Var(&config.Messages, "m", "Commit message paragraph (repeatable)")
+	flag.StringVar(&config.MessageFile, "F", "", "Read the commit message from a file, or - for standard input")
+	flag.BoolVar(&config.Edit, "edit", false, "Write or adjust the commit message in the editor")
+	flag.BoolVar(&config.Edit, "e", false, "Write or adjust the commit message in the editor (shorthand)")
+	flag.StringVar(&config.Author, "author", "", "Override the commit author (\"Name <email>\")")
+	flag.StringVar(&config.Committer, "committer", "", "Override the committer (\"Name <email>\")")
+	flag.BoolVar(&config.Signoff, "signoff", false, "Add a Signed-off-by trailer for the committer")
+	flag.BoolVar(&config.Signoff, "s", false, "Add a Signed-off-by trailer for the committer (shorthand)")
+	flag.Var(&config.CoAuthors, "co-author", "Add a Co-authored-by trailer (\"Name <email>\", repeatable)")
+	flag.Var(&config.Trailers, "trailer", "Add a trailer (key=value, repeatable)")
+	flag.StringVar(&config.Provenance, "provenance", "",
+		"Record the real creation time: note or trailer (default: gitcommit.provenance)")
+	flag.Var(&config.Sign, "S", "Sign the commit, optionally with the given key (-S<keyid>)")
+	flag.Var(&config.Sign, "gpg-sign", "Sign the commit, optionally with the given key (--gpg-sign=<keyid>)")
+	flag.BoolVar(&config.NoSign, "no-gpg-sign", false, "Do not sign the commit, even if commit.gpgsign is set")
+	// git accepts the key attached to -S, which the flag package does not
+	_ = flag.CommandLine.Parse(cli.NormalizeSignFlag(os.Args[1:]))
 
 	// Collect positional arguments
 	config.Args = flag.Args()
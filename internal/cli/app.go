@@ -2,6 +2,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -31,7 +32,12 @@ func (a *App) Run() error {
 		"date", request.InputDate,
 		"message", request.CommitMessage)
 
-	// Step 1: Validate Git repository
+	// Step 1: Validate Git repository, and that a message was given unless
+	// git has prepared one for the merge, cherry-pick or revert it concludes
+	if a.config.lacksMessage() && !concludesOperation() {
+		positional, _ := a.config.splitArgs()
+		return NewMissingArgumentsError(RequiredArguments, len(positional))
+	}
 	if !git.IsGitRepository() {
 		slog.Error("Not in a Git repository")
 		return NewNoRepositoryError()
@@ -39,79 +45,381 @@ func (a *App) Run() error {
 
 	slog.Debug("Git repository detected")
 
-	// Step 2: Get last commit date (if any)
-	lastCommitDate := a.getLastCommitDate()
-
-	// Step 3: Parse and validate the date
-	parsedDate, err := a.parseAndValidateDate(request.InputDate, lastCommitDate)
+	// Step 2: Parse the date, which only depends on user input
+	parsedDate, err := a.parseDate(request.InputDate)
 	if err != nil {
 		return err
 	}
 	request.ParsedDate = parsedDate
 
-	// Step 4: Format the date for Git
+	// Step 2b: Detect a merge, cherry-pick or revert waiting to be committed
+	if request.Operation, err = a.checkOperationInProgress(); err != nil {
+		return err
+	}
+
+	// Step 3: Stage any selected paths and make sure there is something to commit
+	var index *git.TemporaryIndex
+	if pathspecs := a.config.GetPathspecs(); len(pathspecs) > 0 {
+		index, err = preparePathspecIndex(pathspecs, request.Operation)
+		if err != nil {
+			return err
+		}
+		defer index.Remove()
+	}
+
+	if a.config.Amend {
+		if err := a.checkAmendable(); err != nil {
+			return err
+		}
+	} else if err := a.checkStagedChanges(index, request.Operation); err != nil {
+		return err
+	}
+
+	// Step 3b: Resolve who is recorded as author and committer
+	if err := a.resolveIdentities(request); err != nil {
+		return err
+	}
+	if err := a.resolveSigning(request); err != nil {
+		return err
+	}
+	if request.ProvenanceMode, err = a.resolveProvenanceMode(); err != nil {
+		return err
+	}
+
+	// Step 4: Validate the date against the commit it must follow (if any)
+	floor, err := a.findChronologyFloor()
+	if err != nil {
+		return err
+	}
+	if err := a.validateChronology(parsedDate, floor); err != nil {
+		return err
+	}
+	slog.Debug("Chronology validation passed")
+
+	// Step 5: Format the date for Git
 	gitFormattedDate := datetime.FormatForGit(parsedDate)
 	request.GitFormattedDate = gitFormattedDate
 	slog.Debug("Date formatted for Git", "formatted", gitFormattedDate)
 
-	// Step 5: Execute the commit
-	if err := git.ExecuteCommit(gitFormattedDate, request.CommitMessage); err != nil {
+	// Step 6: Resolve the commit message, opening the editor if asked
+	message, err := a.resolveMessage(request)
+	if err != nil {
+		return err
+	}
+	request.CommitMessage = message
+	if err := a.applyTrailers(request); err != nil {
+		return err
+	}
+	slog.Debug("Commit message resolved", "message", request.CommitMessage)
+
+	// Step 6b: Save the refs the commit moves, for gitcommit undo
+	detached := headDetached()
+	if detached {
+		slog.Warn("Committing on a detached HEAD: no branch will point to the commit")
+	}
+	backup, err := a.backupCommitRefs(request)
+	if err != nil {
+		return err
+	}
+
+	// Step 7: Execute the commit
+	options := git.CommitOptions{
+		AllowEmpty:  a.config.AllowEmpty || request.Operation != git.OperationNone,
+		All:         a.config.All,
+		Amend:       a.config.Amend,
+		KeepContent: a.config.KeepContent,
+		CherryPick:  request.Operation == git.OperationCherryPick,
+		ShowOutput:  a.config.ShowGitOutput,
+	}
+	if a.config.Author != "" {
+		options.Author = &request.Author
+	}
+	if a.config.Committer != "" {
+		options.Committer = &request.Committer
+	}
+	if index != nil {
+		options.IndexFile = index.Path
+	}
+	if a.config.Sign.Enabled {
+		options.Sign = true
+		options.SignKey = a.config.Sign.KeyID
+	}
+	options.NoSign = a.config.NoSign
+	if err := git.ExecuteCommit(gitFormattedDate, request.CommitMessage, options); err != nil {
 		slog.Error("Git commit failed", "error", err)
-		return NewGitCommandError(err.Error())
+		discardUnusedBackup(backup)
+		return commitFailureError(err, request.SigningFormat)
 	}
 
-	// Step 6: Display success message
-	fmt.Println(FormatSuccessMessage(gitFormattedDate))
-	slog.Info("Commit created successfully")
+	// Record the commit in the journal before any check that can still fail
+	a.journalCommit(request, backup)
+
+	// Bring the committed paths up to date in the real index, leaving the rest intact
+	if index != nil {
+		if err := git.StagePaths(a.config.GetPathspecs()); err != nil {
+			slog.Warn("Could not update the index for committed paths", "error", err)
+		}
+	}
+
+	// Step 8: Verify the signature of a signed commit
+	var signature git.Signature
+	if request.Sign {
+		if signature, err = verifySignature(request.SigningFormat); err != nil {
+			return err
+		}
+	}
+
+	// Step 9: Record when the commit was really made, if asked
+	if request.ProvenanceMode == ProvenanceNote {
+		if err := recordProvenanceNote(a.provenance(request)); err != nil {
+			return err
+		}
+	}
+
+	// Step 10: Display success message
+	if a.config.Amend {
+		fmt.Println(FormatAmendSuccessMessage(gitFormattedDate))
+	} else {
+		fmt.Println(FormatSuccessMessage(gitFormattedDate))
+	}
+	fmt.Println(FormatIdentityMessage(request.Author, request.Committer))
+	for _, warning := range request.IdentityWarnings {
+		fmt.Println(FormatWarningMessage(warning))
+	}
+	if detached {
+		fmt.Println(FormatDetachedHeadMessage())
+	}
+	if request.Sign {
+		fmt.Println(FormatSignatureMessage(signature, request.SigningFormat))
+	}
+	if request.ProvenanceMode != "" {
+		fmt.Println(FormatProvenanceRecordedMessage(request.ProvenanceMode))
+	}
+	if request.Operation != git.OperationNone {
+		fmt.Println(FormatOperationMessage(request.Operation, git.HasSequencer()))
+	}
+	if a.config.Amend {
+		slog.Info("Commit amended successfully")
+	} else {
+		slog.Info("Commit created successfully")
+	}
 	return nil
 }
 
-// getLastCommitDate retrieves the last commit date from the repository.
-func (a *App) getLastCommitDate() *time.Time {
+// backupCommitRefs saves the current branch, or HEAD when detached, and the
+// provenance notes ref when a note is recorded, before the commit moves them.
+func (a *App) backupCommitRefs(request *CommitRequest) (git.Backup, error) {
+	head, err := headRef()
+	if err != nil {
+		return git.Backup{}, err
+	}
+	refs := []string{head}
+	if request.ProvenanceMode == ProvenanceNote {
+		refs = append(refs, ProvenanceNotesRef)
+	}
+
+	reason := "gitcommit: " + request.InputDate
+	if a.config.Amend {
+		reason = "gitcommit --amend: " + request.InputDate
+	}
+	return backupRefs(reason, refs...)
+}
+
+// journalCommit records the commit made by request in the journal.
+func (a *App) journalCommit(request *CommitRequest, backup git.Backup) {
+	entry := git.JournalEntry{
+		Operation: "commit",
+		InputDate: request.InputDate,
+		Date:      request.ParsedDate,
+		Identity:  request.Committer.String(),
+		Author:    request.Author.String(),
+	}
+	if a.config.Amend {
+		entry.Operation = "amend"
+	}
+	if commit, ok := journalCommitOf("HEAD", ""); ok {
+		if a.config.Amend && len(backup.Refs) > 0 {
+			commit.Old = backup.Refs[0].Hash
+		}
+		entry.Commits = []git.JournalCommit{commit}
+	}
+	a.recordJournal(entry, backup)
+}
+
+// preparePathspecIndex builds a temporary index holding HEAD plus the paths
+// given after "--", so that only those paths are committed. Like git, this is
+// refused while concluding a merge, cherry-pick or revert.
+func preparePathspecIndex(pathspecs []string, operation git.Operation) (*git.TemporaryIndex, error) {
+	if operation != git.OperationNone {
+		return nil, NewPartialCommitDuringOperationError(operation)
+	}
+
+	index, err := git.NewPathspecIndex(pathspecs)
+	if err != nil {
+		slog.Error("Staging paths failed", "pathspecs", pathspecs, "error", err)
+		return nil, NewPathspecError(pathspecs, err.Error())
+	}
+
+	slog.Debug("Paths staged in temporary index", "pathspecs", pathspecs, "index", index.Path)
+	return index, nil
+}
+
+// checkOperationInProgress returns the merge, cherry-pick or revert that the
+// commit concludes, if any. Rebases and git am are refused: they commit on
+// their own when continued, so a commit made here would be out of sequence.
+func (a *App) checkOperationInProgress() (git.Operation, error) {
+	operation, err := git.GetOperationInProgress()
+	if err != nil {
+		return git.OperationNone, NewGitCommandError(err.Error())
+	}
+
+	switch operation {
+	case git.OperationNone:
+		return operation, nil
+	case git.OperationRebase, git.OperationApplyMailbox:
+		slog.Error("Refusing to commit during a rebase", "operation", operation)
+		return operation, NewRebaseInProgressError(operation)
+	case git.OperationMerge, git.OperationCherryPick, git.OperationRevert:
+		if a.config.Amend {
+			return operation, NewConflictingOptionsError("--amend", "a "+string(operation)+" in progress")
+		}
+		slog.Info("Concluding operation in progress", "operation", operation)
+		return operation, nil
+	default:
+		return operation, nil
+	}
+}
+
+// concludesOperation reports whether a merge, cherry-pick or revert is waiting
+// to be committed, in which case git has prepared the message.
+func concludesOperation() bool {
+	operation, err := git.GetOperationInProgress()
+	if err != nil {
+		return false
+	}
+	return operation.Head() != ""
+}
+
+// checkAmendable makes sure there is a commit to amend and, unless --force is
+// given, that it has not been pushed to a remote-tracking branch.
+func (a *App) checkAmendable() error {
 	if !git.HasCommits() {
-		slog.Debug("No previous commits in repository")
+		return NewNothingToAmendError()
+	}
+
+	if a.config.Force {
+		return nil
+	}
+
+	remoteBranches, err := git.RemoteBranchesContaining("HEAD")
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+	if len(remoteBranches) > 0 {
+		slog.Error("Refusing to amend a published commit", "remoteBranches", remoteBranches)
+		return NewAmendPublishedError(remoteBranches)
+	}
+
+	return nil
+}
+
+// checkStagedChanges returns a NothingStaged error when the commit would record
+// no changes: the index (or the temporary pathspec index) matches HEAD and,
+// with --all, no tracked file is modified either.
+// The check is skipped with --allow-empty and while concluding a merge,
+// cherry-pick or revert, which may legitimately record no changes of its own.
+func (a *App) checkStagedChanges(index *git.TemporaryIndex, operation git.Operation) error {
+	if a.config.AllowEmpty {
 		return nil
 	}
 
-	lastDate, err := git.GetLastCommitDate()
+	if operation != git.OperationNone {
+		slog.Debug("Operation in progress, skipping staged changes check", "operation", operation)
+		return nil
+	}
+
+	indexFile := ""
+	if index != nil {
+		indexFile = index.Path
+	}
+
+	staged, err := git.HasIndexChanges(indexFile)
 	if err != nil {
-		slog.Warn("Could not retrieve last commit date", "error", err)
+		return NewGitCommandError(err.Error())
+	}
+	if staged {
+		return nil
+	}
+
+	status, err := git.GetWorkingTreeStatus()
+	if err != nil {
+		slog.Warn("Could not retrieve working tree status", "error", err)
+	}
+	if a.config.All && status.Unstaged > 0 {
 		return nil
 	}
 
-	slog.Debug("Last commit date retrieved", "date", lastDate)
-	return &lastDate
+	slog.Error("Nothing staged to commit",
+		"unstaged", status.Unstaged,
+		"untracked", status.Untracked)
+	return NewNothingStagedError(status.Unstaged, status.Untracked, headDetached())
 }
 
-// parseAndValidateDate parses and validates the commit date.
-func (a *App) parseAndValidateDate(dateStr string, lastCommitDate *time.Time) (time.Time, error) {
-	// Parse the date
-	parsedDate, err := datetime.ParseDate(dateStr)
+// headDetached reports whether HEAD is detached rather than on a branch.
+func headDetached() bool {
+	branch, err := git.GetCurrentBranch()
 	if err != nil {
-		slog.Error("Date parsing failed", "error", err)
-		return time.Time{}, NewInvalidDateFormatError(dateStr)
+		slog.Warn("Could not read the current branch", "error", err)
+		return false
 	}
+	return branch == ""
+}
 
-	slog.Debug("Date parsed successfully", "parsed", parsedDate)
+// commitFailureError converts a failed git commit into a UserError with a
+// hint tailored to the classified failure.
+func commitFailureError(err error, signingFormat string) *UserError {
+	var commitErr *git.CommitError
+	if !errors.As(err, &commitErr) {
+		return NewGitCommandError(err.Error())
+	}
 
-	// Validate chronology
-	if lastCommitDate != nil {
-		valid, errorType := datetime.ValidateChronology(parsedDate, lastCommitDate)
-		if !valid {
-			slog.Error("Chronology validation failed",
-				"provided", parsedDate,
-				"lastCommit", lastCommitDate,
-				"errorType", errorType)
-
-			equal := errorType == "chronology_violation_equal"
-			return time.Time{}, NewChronologyViolationError(
-				datetime.FormatForGit(parsedDate),
-				datetime.FormatForGit(*lastCommitDate),
-				equal,
-			)
+	gitError := git.GetGitError([]byte(commitErr.Output))
+	slog.Debug("Git commit failure classified", "kind", commitErr.Kind, "exitCode", commitErr.ExitCode)
+
+	switch commitErr.Kind {
+	case git.FailureNothingToCommit:
+		status, statusErr := git.GetWorkingTreeStatus()
+		if statusErr != nil {
+			slog.Warn("Could not retrieve working tree status", "error", statusErr)
 		}
+		return NewNothingStagedError(status.Unstaged, status.Untracked, headDetached())
+	case git.FailureUnknownIdentity:
+		return NewUnknownIdentityError(gitError)
+	case git.FailureHookRejected:
+		return NewHookRejectedError(commitErr.Hooks, commitErr.Output)
+	case git.FailureIndexLocked:
+		return NewIndexLockedError(commitErr.LockFile)
+	case git.FailureDetachedHead:
+		return NewDetachedHeadError(gitError)
+	case git.FailureUnmergedPaths:
+		return NewUnmergedPathsError(gitError)
+	case git.FailureSigningFailed:
+		return NewSigningFailedError(signingFormat, gitError)
+	case git.FailureUnknown:
+		fallthrough
+	default:
+		return NewGitCommandError(gitError)
+	}
+}
+
+// parseDate parses the commit date provided by the user.
+func (a *App) parseDate(dateStr string) (time.Time, error) {
+	parsedDate, err := datetime.ParseDate(dateStr)
+	if err != nil {
+		slog.Error("Date parsing failed", "error", err)
+		return time.Time{}, NewInvalidDateFormatError(dateStr)
 	}
 
-	slog.Debug("Chronology validation passed")
+	slog.Debug("Date parsed successfully", "parsed", parsedDate)
 	return parsedDate, nil
 }
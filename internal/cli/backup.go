@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+const (
+	// BackupMaxAgeConfigKey is the git config key holding how long backups
+	// are kept, such as "90d" or "36h"; "0" keeps them whatever their age.
+	BackupMaxAgeConfigKey = "gitcommit.backupMaxAge"
+
+	// BackupMaxCountConfigKey is the git config key holding how many backups
+	// are kept; 0 keeps them all.
+	BackupMaxCountConfigKey = "gitcommit.backupMaxCount"
+
+	// DefaultBackupMaxAge is the age past which backups are pruned by default.
+	DefaultBackupMaxAge = 90 * 24 * time.Hour
+
+	// DefaultBackupMaxCount is the number of backups kept by default.
+	DefaultBackupMaxCount = 100
+
+	// dayUnit is the suffix of backup ages given in days, which Go durations lack.
+	dayUnit = "d"
+)
+
+// backupRefs saves the current value of refs under refs/gitcommit/backup
+// before reason moves them, then prunes stale backups. A failure to prune
+// is only logged: it must not stop the operation.
+func backupRefs(reason string, refs ...string) (git.Backup, error) {
+	backup, err := git.CreateBackup(reason, refs)
+	if err != nil {
+		slog.Error("Backing up refs failed", "refs", refs, "error", err)
+		return backup, NewBackupError(err.Error())
+	}
+	slog.Debug("Refs backed up", "id", backup.ID, "refs", refs)
+
+	if _, err := pruneBackups(backup.ID); err != nil {
+		slog.Warn("Could not prune old backups", "error", err)
+	}
+	return backup, nil
+}
+
+// discardUnusedBackup deletes backup when the operation it was made for
+// failed without moving any of the refs it saved, so that gitcommit undo
+// does not take it for the latest operation. It reports whether the backup
+// was deleted; a failure to delete it is only logged.
+func discardUnusedBackup(backup git.Backup) bool {
+	if backup.ID == "" {
+		return false
+	}
+	for _, saved := range backup.Refs {
+		if git.ResolveRef(saved.Name) != saved.Hash {
+			return false
+		}
+	}
+
+	if err := git.DeleteBackup(backup); err != nil {
+		slog.Warn("Could not delete the backup of a failed operation", "id", backup.ID, "error", err)
+		return false
+	}
+	slog.Debug("Backup of a failed operation deleted", "id", backup.ID)
+	return true
+}
+
+// headRef returns the full name of the current branch, or HEAD when detached.
+func headRef() (string, error) {
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		return "", NewGitCommandError(err.Error())
+	}
+	if branch == "" {
+		return "HEAD", nil
+	}
+	return branch, nil
+}
+
+// pruneBackups deletes the backups older than the configured age or beyond
+// the configured count, newest kept first, and returns how many it deleted.
+// The backup named keep, the one just made, is never deleted.
+func pruneBackups(keep string) (int, error) {
+	maxAge, maxCount, err := backupPolicy()
+	if err != nil {
+		return 0, err
+	}
+
+	backups, err := git.ListBackups()
+	if err != nil {
+		return 0, NewGitCommandError(err.Error())
+	}
+
+	pruned := 0
+	now := time.Now()
+	for i, backup := range backups {
+		tooOld := maxAge > 0 && now.Sub(backup.Created) > maxAge
+		tooMany := maxCount > 0 && i >= maxCount
+		if backup.ID == keep || (!tooOld && !tooMany) {
+			continue
+		}
+		if err := git.DeleteBackup(backup); err != nil {
+			return pruned, NewGitCommandError(err.Error())
+		}
+		slog.Debug("Backup pruned", "id", backup.ID, "created", backup.Created)
+		pruned++
+	}
+	return pruned, nil
+}
+
+// backupPolicy reads how long and how many backups are kept from git config.
+func backupPolicy() (time.Duration, int, error) {
+	maxAge, maxCount := DefaultBackupMaxAge, DefaultBackupMaxCount
+
+	age, err := git.GetConfig(BackupMaxAgeConfigKey)
+	if err != nil {
+		return 0, 0, NewGitCommandError(err.Error())
+	}
+	if age != "" {
+		if maxAge, err = parseBackupAge(age); err != nil {
+			return 0, 0, NewInvalidConfigError(BackupMaxAgeConfigKey, age, "an age such as 90d or 36h, or 0 to keep every backup")
+		}
+	}
+
+	count, err := git.GetConfig(BackupMaxCountConfigKey)
+	if err != nil {
+		return 0, 0, NewGitCommandError(err.Error())
+	}
+	if count != "" {
+		if maxCount, err = strconv.Atoi(count); err != nil || maxCount < 0 {
+			return 0, 0, NewInvalidConfigError(BackupMaxCountConfigKey, count, "a number of backups, or 0 to keep them all")
+		}
+	}
+	return maxAge, maxCount, nil
+}
+
+// parseBackupAge parses a number of days such as "90d", or a Go duration.
+func parseBackupAge(age string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(age, dayUnit); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, strconv.ErrSyntax
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(age)
+	if err != nil || duration < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return duration, nil
+}
@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// ChronologyFloor is the commit whose date a new commit must follow.
+type ChronologyFloor struct {
+	// Ref is the ref (or "HEAD") through which the commit was found.
+	Ref string
+
+	// Commit holds the hash and both dates of the commit.
+	Commit git.CommitDates
+
+	// Date is the date selected from Commit by the chronology basis.
+	Date time.Time
+}
+
+// floorCandidate is a ref to consider when computing the chronology floor.
+type floorCandidate struct {
+	ref      string // revision to resolve
+	label    string // name shown to the user; defaults to ref
+	required bool   // whether an unresolvable ref is an error
+	depth    int    // generations of parents to consider below the ref
+}
+
+// labelledCommit is a commit hash together with the name it was reached by.
+type labelledCommit struct {
+	label string
+	hash  string
+}
+
+// floorSearch accumulates the newest commit seen while walking candidates.
+type floorSearch struct {
+	basis datetime.ChronologyBasis
+	seen  map[string]bool
+	floor *ChronologyFloor
+}
+
+// findChronologyFloor returns the newest commit, by the configured basis, among
+// HEAD and its parents, any commits being merged, and any refs selected with
+// --against or --against-all-refs.
+// Returns nil if none of the candidates resolve to a commit.
+func (a *App) findChronologyFloor() (*ChronologyFloor, error) {
+	candidates, err := a.floorCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	search := &floorSearch{
+		basis: a.config.GetChronologyBasis(),
+		seen:  make(map[string]bool),
+	}
+
+	for _, candidate := range candidates {
+		hash, err := git.ResolveCommit(candidate.ref)
+		if err != nil {
+			if candidate.required {
+				return nil, NewUnknownRefError(candidate.ref)
+			}
+			slog.Debug("Skipping ref without a commit", "ref", candidate.ref)
+			continue
+		}
+
+		label := candidate.label
+		if label == "" {
+			label = candidate.ref
+		}
+
+		if err := search.walk(label, hash, candidate.depth); err != nil {
+			slog.Warn("Could not retrieve commit dates", "ref", label, "error", err)
+		}
+	}
+
+	if search.floor != nil {
+		slog.Debug("Chronology floor found",
+			"ref", search.floor.Ref,
+			"commit", search.floor.Commit.Hash,
+			"date", search.floor.Date)
+	}
+
+	return search.floor, nil
+}
+
+// floorCandidates lists the refs to consider, HEAD first so that it wins ties.
+func (a *App) floorCandidates() ([]floorCandidate, error) {
+	depth := a.config.ChronologyDepth
+	candidates := []floorCandidate{{ref: "HEAD", depth: depth}}
+
+	// An amended commit replaces HEAD, so it must follow HEAD's parents instead
+	if a.config.Amend {
+		var err error
+		if candidates, err = amendFloorCandidates(depth); err != nil {
+			return nil, err
+		}
+	}
+
+	// When finishing a merge, the new commit must also follow every side being merged
+	mergeHeads, err := git.GetMergeHeads()
+	if err != nil {
+		return nil, NewGitCommandError(err.Error())
+	}
+	for _, hash := range mergeHeads {
+		candidates = append(candidates, floorCandidate{ref: hash, label: "MERGE_HEAD", required: true, depth: depth})
+	}
+
+	for _, ref := range a.config.Against {
+		candidates = append(candidates, floorCandidate{ref: ref, required: true})
+	}
+
+	if a.config.AgainstAllRefs {
+		refs, err := git.ListRefs()
+		if err != nil {
+			return nil, NewGitCommandError(err.Error())
+		}
+		for _, ref := range refs {
+			candidates = append(candidates, floorCandidate{ref: ref})
+		}
+	}
+
+	return candidates, nil
+}
+
+// amendFloorCandidates lists HEAD's parents, which become the parents of the
+// amended commit and so take HEAD's place as the roots of the walk.
+func amendFloorCandidates(depth int) ([]floorCandidate, error) {
+	head, err := git.GetLastCommitDates()
+	if err != nil {
+		return nil, NewNothingToAmendError()
+	}
+
+	candidates := make([]floorCandidate, 0, len(head.Parents))
+	for i, parent := range head.Parents {
+		candidates = append(candidates, floorCandidate{
+			ref:      parent,
+			label:    fmt.Sprintf("HEAD^%d", i+1),
+			required: true,
+			depth:    depth,
+		})
+	}
+
+	return candidates, nil
+}
+
+// walk considers the commit at hash and its ancestors up to depth generations,
+// fetching each generation with a single git invocation.
+// Ancestors are labelled with git's parent suffix syntax, e.g. HEAD^2^1.
+func (s *floorSearch) walk(label, hash string, depth int) error {
+	level := []labelledCommit{{label: label, hash: hash}}
+
+	for generation := 0; len(level) > 0; generation++ {
+		var pending []labelledCommit
+		var hashes []string
+		for _, commit := range level {
+			if s.seen[commit.hash] {
+				continue
+			}
+			s.seen[commit.hash] = true
+			pending = append(pending, commit)
+			hashes = append(hashes, commit.hash)
+		}
+
+		commits, err := git.GetCommitsDates(hashes)
+		if err != nil {
+			return err
+		}
+
+		var next []labelledCommit
+		for i, commit := range commits {
+			s.consider(pending[i].label, commit)
+
+			if generation < depth {
+				for n, parent := range commit.Parents {
+					next = append(next, labelledCommit{
+						label: fmt.Sprintf("%s^%d", pending[i].label, n+1),
+						hash:  parent,
+					})
+				}
+			}
+		}
+		level = next
+	}
+
+	return nil
+}
+
+// consider records commit as the floor if it is newer than the current floor.
+func (s *floorSearch) consider(label string, commit git.CommitDates) {
+	date := s.basis.Select(commit.AuthorDate, commit.CommitterDate)
+	if s.floor == nil || date.After(s.floor.Date) {
+		s.floor = &ChronologyFloor{Ref: label, Commit: commit, Date: date}
+	}
+}
+
+// validateChronology checks a parsed date against the chronology floor.
+func (a *App) validateChronology(parsedDate time.Time, floor *ChronologyFloor) error {
+	if floor == nil {
+		return nil
+	}
+
+	valid, errorType := datetime.ValidateChronology(parsedDate, &floor.Date)
+	if valid {
+		return nil
+	}
+
+	slog.Error("Chronology validation failed",
+		"provided", parsedDate,
+		"floor", floor.Date,
+		"ref", floor.Ref,
+		"commit", floor.Commit.Hash,
+		"errorType", errorType)
+
+	equal := errorType == "chronology_violation_equal"
+	return NewChronologyViolationError(datetime.FormatForGit(parsedDate), floor, a.config.GetChronologyBasis(), equal)
+}
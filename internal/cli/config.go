@@ -1,8 +1,20 @@
 package cli
 
+import (
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
 const (
 	// RequiredArguments is the number of arguments required for normal operation.
 	RequiredArguments = 2
+
+	// PathspecSeparator separates the date and message from the paths to commit.
+	PathspecSeparator = "--"
+
+	// DefaultChronologyDepth includes the direct parents of HEAD in the chronology
+	// floor, so that every side of a merge is taken into account.
+	DefaultChronologyDepth = 1
 )
 
 // Config holds the configuration for the CLI application.
@@ -16,48 +28,280 @@ type Config struct {
 	// ShowVersion indicates whether the --version flag was provided.
 	ShowVersion bool
 
+	// ChronologyBasis selects which date of the last commit is checked against.
+	// One of "author", "committer" or "max".
+	ChronologyBasis string
+
+	// ChronologyDepth is how many generations of parents of HEAD (and of any
+	// commit being merged) are included in the chronology floor.
+	ChronologyDepth int
+
+	// Against lists additional refs whose tips new commits must follow (--against, repeatable).
+	Against StringList
+
+	// AgainstAllRefs checks against every branch, remote-tracking branch and tag.
+	AgainstAllRefs bool
+
+	// AllowEmpty skips the staged changes check and permits commits without changes.
+	AllowEmpty bool
+
+	// All stages every modified and deleted tracked file before committing.
+	All bool
+
+	// Amend re-dates HEAD instead of creating a new commit.
+	Amend bool
+
+	// KeepContent, with Amend, keeps HEAD's content and ignores staged changes.
+	KeepContent bool
+
+	// Force allows amending a commit that is already on a remote-tracking branch.
+	Force bool
+
+	// Messages holds the -m values, each becoming a paragraph of the message.
+	Messages StringList
+
+	// MessageFile is the -F value: a file to read the message from, or "-" for standard input.
+	MessageFile string
+
+	// Edit opens the editor to write or adjust the commit message.
+	Edit bool
+
+	// Author overrides the commit author, in "Name <email>" form.
+	Author string
+
+	// Committer overrides the committer, in "Name <email>" form.
+	Committer string
+
+	// Signoff adds a Signed-off-by trailer for the committer.
+	Signoff bool
+
+	// CoAuthors holds the --co-author identities, each added as a Co-authored-by trailer.
+	CoAuthors StringList
+
+	// Trailers holds the --trailer values, in "key=value" or "key: value" form.
+	Trailers StringList
+
+	// Sign requests a signed commit (-S[<keyid>] or --gpg-sign[=<keyid>]).
+	Sign SignFlag
+
+	// NoSign disables signing even when commit.gpgsign is set.
+	NoSign bool
+
+	// Provenance records the real creation time alongside the commit: "note",
+	// "trailer", or empty to use the gitcommit.provenance config key.
+	Provenance string
+
+	// ShowGitOutput echoes git's own output as it runs, including on failure.
+	ShowGitOutput bool
+
 	// Args contains positional arguments after flag parsing.
 	Args []string
 }
 
+// ToolVersion is the version of gitcommit used when NewConfig is given
+// none, as subcommands do. main sets it from its ldflags.
+var ToolVersion = "dev"
+
 // NewConfig creates a new Config with the specified version.
 func NewConfig(version string) *Config {
 	// If version is empty, use a default
 	if version == "" {
-		version = "dev"
+		version = ToolVersion
 	}
 	return &Config{
-		Version: version,
+		Version:         version,
+		ChronologyBasis: string(datetime.DefaultChronologyBasis),
+		ChronologyDepth: DefaultChronologyDepth,
 	}
 }
 
 // Validate checks if the configuration is valid for normal operation.
+//
+// The accepted argument grammar is:
+//
+//	<date> <message> [-- <pathspec>...]
+//	<date> (-m <message>... | -F <file> | -F - | --edit) [-- <pathspec>...]
+//	--amend <date> [<message>] [-- <pathspec>...]
+//	<date> [<message>]  (while a merge, cherry-pick or revert is in progress)
+//
+// Only one source of message may be given, though --edit may be combined
+// with any of them to adjust the message before committing.
 func (c *Config) Validate() error {
 	// If showing help or version, no validation needed
 	if c.ShowHelp || c.ShowVersion {
 		return nil
 	}
 
-	// Normal operation requires exactly 2 arguments: date and message
-	if len(c.Args) != RequiredArguments {
-		return NewMissingArgumentsError(RequiredArguments, len(c.Args))
+	// Normal operation requires exactly 2 arguments before any pathspecs: date and message.
+	// The message argument may be replaced by -m, -F or --edit, and is optional when
+	// amending, where it defaults to HEAD's message, and when concluding a merge,
+	// cherry-pick or revert, where it defaults to the message git prepared. That
+	// last case depends on the repository, so App.Run checks it.
+	positional, pathspecs := c.splitArgs()
+	if len(positional) < 1 || len(positional) > RequiredArguments {
+		return NewMissingArgumentsError(RequiredArguments, len(positional))
+	}
+
+	if err := c.validateMessageSources(); err != nil {
+		return err
+	}
+
+	if err := c.validateStagingOptions(pathspecs); err != nil {
+		return err
+	}
+
+	if err := c.validateIdentities(); err != nil {
+		return err
+	}
+
+	if err := c.validateTrailers(); err != nil {
+		return err
+	}
+
+	if !isProvenanceMode(c.Provenance) {
+		return NewInvalidProvenanceModeError(c.Provenance)
+	}
+
+	if c.Sign.Enabled && c.NoSign {
+		return NewConflictingOptionsError("-S", "--no-gpg-sign")
+	}
+
+	if _, err := datetime.ParseChronologyBasis(c.ChronologyBasis); err != nil {
+		return NewInvalidChronologyBasisError(c.ChronologyBasis)
+	}
+
+	if c.ChronologyDepth < 0 {
+		return NewInvalidChronologyDepthError(c.ChronologyDepth)
+	}
+
+	return nil
+}
+
+// lacksMessage reports whether no source of commit message was given, which
+// is only allowed when git has prepared one.
+func (c *Config) lacksMessage() bool {
+	return !c.Amend && !c.Edit && !c.hasMessageFlag() && !c.HasMessageArgument()
+}
+
+// hasMessageFlag reports whether -m or -F was given.
+func (c *Config) hasMessageFlag() bool {
+	return len(c.Messages) > 0 || c.MessageFile != ""
+}
+
+// validateMessageSources rejects more than one source of commit message.
+func (c *Config) validateMessageSources() error {
+	var sources []string
+	if c.HasMessageArgument() {
+		sources = append(sources, "<message> argument")
+	}
+	if len(c.Messages) > 0 {
+		sources = append(sources, "-m")
+	}
+	if c.MessageFile != "" {
+		sources = append(sources, "-F")
+	}
+
+	if len(sources) > 1 {
+		return NewConflictingOptionsError(sources[0], sources[1])
+	}
+	return nil
+}
+
+// validateIdentities checks that --author and --committer are in "Name <email>" form.
+func (c *Config) validateIdentities() error {
+	for _, identity := range []struct{ flag, value string }{
+		{"--author", c.Author},
+		{"--committer", c.Committer},
+	} {
+		if identity.value == "" {
+			continue
+		}
+		if _, err := git.ParseIdentity(identity.value); err != nil {
+			return NewInvalidIdentityError(identity.flag, identity.value)
+		}
+	}
+	return nil
+}
+
+// validateTrailers checks the --co-author identities and --trailer values.
+func (c *Config) validateTrailers() error {
+	for _, coAuthor := range c.CoAuthors {
+		if _, err := git.ParseIdentity(coAuthor); err != nil {
+			return NewInvalidIdentityError("--co-author", coAuthor)
+		}
+	}
+	for _, trailer := range c.Trailers {
+		if _, err := git.ParseTrailer(trailer); err != nil {
+			return NewInvalidTrailerError(trailer)
+		}
+	}
+	return nil
+}
+
+// validateStagingOptions rejects combinations of options that select content differently.
+func (c *Config) validateStagingOptions(pathspecs []string) error {
+	if c.All && len(pathspecs) > 0 {
+		return NewConflictingOptionsError("--all", "paths after --")
+	}
+
+	if c.KeepContent {
+		switch {
+		case !c.Amend:
+			return NewConflictingOptionsError("--keep-content", "a new commit (it requires --amend)")
+		case c.All:
+			return NewConflictingOptionsError("--keep-content", "--all")
+		case len(pathspecs) > 0:
+			return NewConflictingOptionsError("--keep-content", "paths after --")
+		}
 	}
 
 	return nil
 }
 
+// splitArgs separates the positional arguments from the pathspecs following "--".
+func (c *Config) splitArgs() ([]string, []string) {
+	for i, arg := range c.Args {
+		if arg == PathspecSeparator {
+			return c.Args[:i], c.Args[i+1:]
+		}
+	}
+	return c.Args, nil
+}
+
 // GetDate returns the date argument.
 func (c *Config) GetDate() string {
-	if len(c.Args) >= 1 {
-		return c.Args[0]
+	if positional, _ := c.splitArgs(); len(positional) >= 1 {
+		return positional[0]
 	}
 	return ""
 }
 
 // GetMessage returns the commit message argument.
 func (c *Config) GetMessage() string {
-	if len(c.Args) >= RequiredArguments {
-		return c.Args[1]
+	if positional, _ := c.splitArgs(); len(positional) >= RequiredArguments {
+		return positional[1]
 	}
 	return ""
 }
+
+// HasMessageArgument reports whether the message was given as a positional argument.
+func (c *Config) HasMessageArgument() bool {
+	positional, _ := c.splitArgs()
+	return len(positional) >= RequiredArguments
+}
+
+// GetPathspecs returns the paths to commit, given after "--".
+func (c *Config) GetPathspecs() []string {
+	_, pathspecs := c.splitArgs()
+	return pathspecs
+}
+
+// GetChronologyBasis returns the chronology basis, falling back to the default
+// when the configured value is not recognized.
+func (c *Config) GetChronologyBasis() datetime.ChronologyBasis {
+	basis, err := datetime.ParseChronologyBasis(c.ChronologyBasis)
+	if err != nil {
+		return datetime.DefaultChronologyBasis
+	}
+	return basis
+}
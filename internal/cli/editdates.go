@@ -0,0 +1,289 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+const (
+	// editDatesUsage is the argument synopsis of the edit-dates command.
+	editDatesUsage = "[flags] <range>"
+
+	// editDatesFile is the todo list, inside the git directory, opened in the editor.
+	editDatesFile = "GITCOMMIT_DATES"
+
+	// TodoDateLayout is the format of dates in the todo list: strict ISO 8601
+	// with the zone offset, as git log --format=%aI shows them.
+	TodoDateLayout = "2006-01-02T15:04:05-07:00"
+
+	// todoFields is the number of fields of a todo line before the subject.
+	todoFields = 3
+)
+
+// editDatesHelp explains the todo list, below its lines.
+const editDatesHelp = `Each line is: <commit> <author-date> <committer-date> <subject>
+Dates are ISO 8601 with their zone offset, e.g. 2025-01-16T09:30:00+01:00.
+Change the dates only: the subject is a reminder, and lines may not be
+added, removed or reordered. Every commit must stay after its parents.
+
+If you remove everything, nothing is rewritten.`
+
+// todoLine is a parsed line of the todo list.
+type todoLine struct {
+	// number is the line number in the edited file.
+	number int
+
+	commit        git.CommitObject
+	authorDate    time.Time
+	committerDate time.Time
+}
+
+// RunEditDates implements "gitcommit edit-dates <range>".
+func RunEditDates(args []string) error {
+	config := NewConfig("")
+	options := RewriteOptions{Dates: DatesBoth}
+
+	flags := flag.NewFlagSet("edit-dates", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, EditDatesHelpText()) }
+	flags.BoolVar(&options.DryRun, "dry-run", false, "Show the old and new dates without rewriting")
+	flags.BoolVar(&options.Force, "force", false, "Rewrite commits that are on a remote-tracking branch")
+	flags.BoolVar(&options.UpdateBranches, "update-branches", false,
+		"Also rewrite the other local branches that contain rewritten commits")
+	flags.BoolVar(&options.Resign, "resign", false, "Sign the rewritten commits with the configured key")
+	flags.StringVar(&config.ChronologyBasis, "chronology-basis", config.ChronologyBasis,
+		"Date of the parents to check against: author, committer or max")
+	positional := ParseInterspersed(flags, args)
+
+	if len(positional) != 1 {
+		return NewCommandArgumentsError("edit-dates", editDatesUsage, len(positional))
+	}
+	if _, err := datetime.ParseChronologyBasis(config.ChronologyBasis); err != nil {
+		return NewInvalidChronologyBasisError(config.ChronologyBasis)
+	}
+
+	return NewApp(config).EditDates(positional[0], options)
+}
+
+// EditDates opens the editor on a todo list of the commits in rangeArg and
+// their dates, and rewrites the commits with the dates edited. Lines that
+// cannot be parsed send the user back to the editor, like git rebase -i;
+// the whole list is checked for chronology before anything is rewritten.
+func (a *App) EditDates(rangeArg string, options RewriteOptions) error {
+	slog.Info("Processing edit-dates request", "range", rangeArg)
+
+	if !git.IsGitRepository() {
+		slog.Error("Not in a Git repository")
+		return NewNoRepositoryError()
+	}
+
+	target, err := resolveRewriteTarget(rangeArg)
+	if err != nil {
+		return err
+	}
+
+	lines, err := editTodoList(target)
+	if err != nil {
+		return err
+	}
+	if lines == nil {
+		fmt.Println(FormatEditDatesAbortedMessage())
+		return nil
+	}
+
+	steps := make([]rewriteStep, len(lines))
+	for i, line := range lines {
+		steps[i] = rewriteStep{commit: line.commit, authorDate: line.authorDate, committerDate: line.committerDate}
+	}
+
+	violations, err := a.chronologyViolations(steps)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		slog.Error("Edited dates break the chronology", "violations", len(violations))
+		return NewTodoChronologyError(todoViolations(lines, violations), a.config.GetChronologyBasis())
+	}
+
+	return a.applyRewrite("edit-dates", target, steps, options)
+}
+
+// editTodoList writes the todo list of target, opens the editor on it until
+// every line parses, and returns the parsed lines, or nil when the user
+// removed them all. An editor that leaves a faulty list untouched aborts.
+func editTodoList(target *rewriteTarget) ([]todoLine, error) {
+	path, err := git.GetGitPath(editDatesFile)
+	if err != nil {
+		return nil, NewGitCommandError(err.Error())
+	}
+	editor, err := git.GetEditor()
+	if err != nil {
+		return nil, NewEditorError("", err.Error())
+	}
+
+	body := todoList(target.commits)
+	var problems []string
+	for {
+		content := todoTemplate(body, target, problems)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			return nil, NewEditorError("", err.Error())
+		}
+
+		slog.Debug("Opening editor", "editor", editor, "file", path)
+		if err := git.RunEditor(editor, path); err != nil {
+			return nil, NewEditorError(editor, err.Error())
+		}
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			return nil, NewEditorError(editor, err.Error())
+		}
+		if len(problems) > 0 && bytes.Equal(edited, []byte(content)) {
+			return nil, NewTodoListError(path, problems)
+		}
+
+		var lines []todoLine
+		lines, problems = parseTodoList(string(edited), target.commits)
+		if len(problems) == 0 {
+			return lines, nil
+		}
+
+		slog.Warn("Todo list has errors, reopening the editor", "errors", len(problems))
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, "error: "+problem)
+		}
+		body = stripTodoComments(string(edited))
+	}
+}
+
+// todoList returns the lines of the todo list for commits.
+func todoList(commits []git.CommitObject) string {
+	var b strings.Builder
+	for _, commit := range commits {
+		fmt.Fprintf(&b, "%s %s %s %s\n", git.ShortHash(commit.Hash),
+			commit.AuthorDate.Format(TodoDateLayout), commit.CommitterDate.Format(TodoDateLayout), commit.Subject())
+	}
+	return b.String()
+}
+
+// todoTemplate builds the file presented in the editor: the problems of the
+// previous attempt, if any, the lines, and the help.
+func todoTemplate(body string, target *rewriteTarget, problems []string) string {
+	var b strings.Builder
+	if len(problems) > 0 {
+		b.WriteString("# The todo list could not be read. Fix these lines and save again:\n")
+		for _, problem := range problems {
+			b.WriteString("#   " + problem + "\n")
+		}
+		b.WriteString("#\n")
+	}
+
+	b.WriteString(body)
+	fmt.Fprintf(&b, "\n# Edit the dates of %d commit(s) of %s on %s.\n#\n",
+		len(target.commits), target.rangeArg, shortRefName(target.ref))
+	for _, line := range strings.Split(editDatesHelp, "\n") {
+		b.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+	return b.String()
+}
+
+// stripTodoComments returns the lines of content that are not comments,
+// keeping the user's edits when the editor is opened again.
+func stripTodoComments(content string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), planComment) {
+			b.WriteString(line + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// parseTodoList parses the edited todo list against the commits it was made
+// from. It returns the lines, or nil when none is left, and a description
+// of every line that cannot be used, prefixed with its line number.
+func parseTodoList(content string, commits []git.CommitObject) ([]todoLine, []string) {
+	var lines []todoLine
+	var problems []string
+	for number, text := range strings.Split(content, "\n") {
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, planComment) {
+			continue
+		}
+
+		line, problem := parseTodoLine(number+1, text, commits, len(lines))
+		if problem != "" {
+			problems = append(problems, problem)
+		}
+		lines = append(lines, line)
+	}
+
+	switch {
+	case len(lines) == 0:
+		return nil, nil
+	case len(problems) > 0:
+		return nil, problems
+	case len(lines) < len(commits):
+		return nil, []string{fmt.Sprintf("%d line(s) missing: %s and the commits after it have no line",
+			len(commits)-len(lines), git.ShortHash(commits[len(lines)].Hash))}
+	case len(lines) > len(commits):
+		return nil, []string{fmt.Sprintf("line %d: there are only %d commit(s) in the range", lines[len(commits)].number, len(commits))}
+	}
+	return lines, nil
+}
+
+// parseTodoLine parses line number of the todo list, expected to be the
+// index-th commit, and returns it or a description of why it cannot be used.
+func parseTodoLine(number int, text string, commits []git.CommitObject, index int) (todoLine, string) {
+	line := todoLine{number: number}
+	fields := strings.Fields(text)
+	if len(fields) < todoFields {
+		return line, fmt.Sprintf("line %d: expected <commit> <author-date> <committer-date> <subject>, got %q", number, text)
+	}
+
+	if index >= len(commits) {
+		return line, fmt.Sprintf("line %d: there are only %d commit(s) in the range", number, len(commits))
+	}
+	if !strings.HasPrefix(commits[index].Hash, fields[0]) || len(fields[0]) < git.ShortHashLength {
+		for _, commit := range commits {
+			if len(fields[0]) >= git.ShortHashLength && strings.HasPrefix(commit.Hash, fields[0]) {
+				return line, fmt.Sprintf("line %d: %s is out of order, %s was expected (lines may not be reordered)",
+					number, fields[0], git.ShortHash(commits[index].Hash))
+			}
+		}
+		return line, fmt.Sprintf("line %d: %s is not a commit of the range, %s was expected",
+			number, fields[0], git.ShortHash(commits[index].Hash))
+	}
+	line.commit = commits[index]
+
+	var err error
+	if line.authorDate, err = time.Parse(time.RFC3339, fields[1]); err != nil {
+		return line, fmt.Sprintf("line %d: invalid author date %q (expected e.g. 2025-01-16T09:30:00+01:00)", number, fields[1])
+	}
+	if line.committerDate, err = time.Parse(time.RFC3339, fields[2]); err != nil {
+		return line, fmt.Sprintf("line %d: invalid committer date %q (expected e.g. 2025-01-16T09:30:00+01:00)", number, fields[2])
+	}
+	return line, ""
+}
+
+// todoViolations describes each chronology violation by the lines involved.
+func todoViolations(lines []todoLine, violations []chronologyViolation) []string {
+	descriptions := make([]string, len(violations))
+	for i, violation := range violations {
+		line := lines[violation.position-1]
+		parent := git.ShortHash(violation.parent) + " (outside the range)"
+		if violation.parentPosition > 0 {
+			parent = fmt.Sprintf("%s at line %d", git.ShortHash(violation.parent), lines[violation.parentPosition-1].number)
+		}
+		descriptions[i] = fmt.Sprintf("line %d: %s would be dated %s, not after its parent %s dated %s",
+			line.number, git.ShortHash(line.commit.Hash), violation.date.Format(TodoDateLayout),
+			parent, violation.parentDate.Format(TodoDateLayout))
+	}
+	return descriptions
+}
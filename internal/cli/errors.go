@@ -1,6 +1,13 @@
 package cli
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
 
 // ErrorType represents different categories of errors that can occur.
 type ErrorType int
@@ -53,9 +60,24 @@ func NewNoRepositoryError() *UserError {
 	}
 }
 
-// NewChronologyViolationError creates an error when date is before last commit.
-func NewChronologyViolationError(providedDate, lastCommitDate string, equal bool) *UserError {
-	details := fmt.Sprintf("Your date:        %s\nLast commit date: %s", providedDate, lastCommitDate)
+// NewChronologyViolationError creates an error when date is not after the chronology floor.
+// The ref and commit that set the floor are named, and both of its dates are shown.
+func NewChronologyViolationError(
+	providedDate string,
+	floor *ChronologyFloor,
+	basis datetime.ChronologyBasis,
+	equal bool,
+) *UserError {
+	details := fmt.Sprintf(
+		"Your date:           %s\nFloor set by:        %s (commit %s)\n"+
+			"Last author date:    %s\nLast committer date: %s\n"+
+			"Checked against:     %s (--chronology-basis=%s)",
+		providedDate,
+		floor.Ref, git.ShortHash(floor.Commit.Hash),
+		datetime.FormatForGit(floor.Commit.AuthorDate),
+		datetime.FormatForGit(floor.Commit.CommitterDate),
+		basis.Describe(), basis,
+	)
 	hint := "Commits must be dated after the last commit to maintain chronological order."
 
 	if equal {
@@ -71,6 +93,790 @@ func NewChronologyViolationError(providedDate, lastCommitDate string, equal bool
 	}
 }
 
+// NewInvalidChronologyBasisError creates an error for an unknown --chronology-basis value.
+func NewInvalidChronologyBasisError(provided string) *UserError {
+	return &UserError{
+		Type:    "InvalidChronologyBasis",
+		Message: "Invalid chronology basis",
+		Details: fmt.Sprintf("Expected one of: author, committer, max\n\nYou provided:     %s", provided),
+		Hint:    "Use --chronology-basis=max (the default) to check against the later of both dates.",
+	}
+}
+
+// NewInvalidChronologyDepthError creates an error for a negative --chronology-depth value.
+func NewInvalidChronologyDepthError(provided int) *UserError {
+	return &UserError{
+		Type:    "InvalidChronologyDepth",
+		Message: "Invalid chronology depth",
+		Details: fmt.Sprintf("The depth must be zero or more.\n\nYou provided:     %d", provided),
+		Hint: "Use --chronology-depth=0 to check HEAD only, or 1 (the default) to\n" +
+			"also check its parents.",
+	}
+}
+
+// NewUnknownRefError creates an error when a ref given with --against does not name a commit.
+func NewUnknownRefError(ref string) *UserError {
+	return &UserError{
+		Type:    "UnknownRef",
+		Message: "Unknown ref",
+		Details: fmt.Sprintf("The ref \"%s\" does not point to a commit.", ref),
+		Hint: "To fix this:\n  - List available branches: git branch -a\n" +
+			"  - Fetch remote branches first: git fetch",
+	}
+}
+
+// NewNothingStagedError creates an error when no changes are staged for commit.
+// detached reports whether HEAD is detached, which a new branch may explain.
+func NewNothingStagedError(unstaged, untracked int, detached bool) *UserError {
+	hint := "To fix this:\n  - Stage your changes: git add <files>\n" +
+		"  - Or record a commit without changes: gitcommit --allow-empty <date> <message>"
+	if detached {
+		hint += "\n  - HEAD is detached: if your changes are on a branch, switch to it first: git switch <branch>"
+	}
+
+	return &UserError{
+		Type:    "NothingStaged",
+		Message: "Nothing staged to commit",
+		Details: fmt.Sprintf(
+			"The index matches the last commit, so the commit would record no changes.\n\n"+
+				"Unstaged changes: %d file(s)\nUntracked files:  %d file(s)",
+			unstaged,
+			untracked,
+		),
+		Hint: hint,
+	}
+}
+
+// NewPathspecError creates an error when the paths to commit cannot be staged.
+func NewPathspecError(pathspecs []string, gitError string) *UserError {
+	return &UserError{
+		Type:    "InvalidPathspec",
+		Message: "Cannot stage the given paths",
+		Details: fmt.Sprintf("Paths:     %s\nGit error: %s", strings.Join(pathspecs, " "), gitError),
+		Hint: "To fix this:\n  - Check that the paths exist: git status\n" +
+			"  - Paths are relative to the current directory",
+	}
+}
+
+// NewPartialCommitDuringOperationError creates an error when paths are given
+// while a merge, cherry-pick or revert is in progress.
+func NewPartialCommitDuringOperationError(operation git.Operation) *UserError {
+	return &UserError{
+		Type:    "PartialCommitDuringOperation",
+		Message: "Cannot commit selected paths during a " + string(operation),
+		Details: fmt.Sprintf("A %s is in progress (%s exists), and its commit must record the whole index.",
+			operation, operation.Head()),
+		Hint: "To fix this:\n  - Stage the resolved files: git add <files>\n" +
+			"  - Then run gitcommit without paths after --",
+	}
+}
+
+// NewMergeConflictError creates an error when a merge stops on conflicts.
+func NewMergeConflictError(branches, conflicts []string, date string, squash bool) *UserError {
+	conclude := fmt.Sprintf("  - Conclude the merge with the same date: gitcommit %q", date)
+	if squash {
+		conclude = fmt.Sprintf("  - Commit the squashed changes with the same date: gitcommit %q \"<message>\"", date)
+	}
+
+	return &UserError{
+		Type:    "MergeConflict",
+		Message: "Merge stopped on conflicts",
+		Details: fmt.Sprintf("Merging %s stopped on conflicts in:\n  %s",
+			strings.Join(branches, ", "), strings.Join(conflicts, "\n  ")),
+		Hint: "To fix this:\n  - Resolve the conflicts and stage them: git add <files>\n" +
+			conclude + "\n" +
+			"  - Or give up on the merge: git merge --abort",
+	}
+}
+
+// NewMergeFailedError creates an error when git merge fails without conflicts.
+func NewMergeFailedError(gitError string) *UserError {
+	return &UserError{
+		Type:    "MergeFailed",
+		Message: "Git merge failed",
+		Details: "Git error: " + gitError,
+		Hint: "Possible solutions:\n  - Commit or stash local changes first: git stash\n" +
+			"  - Without --ff-only, a merge commit is created when fast-forward is not possible",
+	}
+}
+
+// NewInvalidIntervalError creates an error for a non-positive or malformed --interval.
+func NewInvalidIntervalError(provided string) *UserError {
+	return &UserError{
+		Type:    "InvalidInterval",
+		Message: "Invalid interval",
+		Details: "The interval must be a positive duration.\n\nYou provided: " + provided,
+		Hint:    "Use Go duration syntax, e.g. --interval=90s, --interval=15m or --interval=2h30m.",
+	}
+}
+
+// NewInvalidPlanError creates an error when the requested dates cannot be assigned.
+func NewInvalidPlanError(command, reason string) *UserError {
+	return &UserError{
+		Type:    "InvalidPlan",
+		Message: "Invalid date plan",
+		Details: reason,
+		Hint:    fmt.Sprintf("Run 'gitcommit %s --help' for the syntax.", command),
+	}
+}
+
+// NewInvalidRangeError creates an error for a range of history that cannot be rewritten.
+func NewInvalidRangeError(rangeArg, reason string) *UserError {
+	return &UserError{
+		Type:    "InvalidRange",
+		Message: "Invalid range",
+		Details: fmt.Sprintf("Range:  %s\nReason: %s", rangeArg, reason),
+		Hint: "Give the commits to rewrite as <base>..<branch>, <base>.. or <base>,\n" +
+			"e.g. main~3..main, HEAD~3.. or HEAD~3 for the last three commits.",
+	}
+}
+
+// NewInvalidDatesSelectionError creates an error for an unknown --dates value.
+func NewInvalidDatesSelectionError(provided string) *UserError {
+	return &UserError{
+		Type:    "InvalidDatesSelection",
+		Message: "Invalid dates selection",
+		Details: fmt.Sprintf("Expected one of: author, committer, both\n\nYou provided:     %s", provided),
+		Hint:    "Use --dates=both (the default) to rewrite author and committer dates alike.",
+	}
+}
+
+// NewRewriteChronologyError creates an error when a rewritten commit would
+// not be dated after one of its parents.
+func NewRewriteChronologyError(
+	position int,
+	commit git.CommitObject,
+	date time.Time,
+	parent string,
+	parentDate time.Time,
+	basis datetime.ChronologyBasis,
+) *UserError {
+	return &UserError{
+		Type:    "ChronologyViolation",
+		Message: "Chronology violation",
+		Details: fmt.Sprintf("Commit %d (%s %s) would be dated: %s\nIts parent %s is dated:      %s\n"+
+			"Checked against:     %s (--chronology-basis=%s)",
+			position, git.ShortHash(commit.Hash), commit.Subject(), datetime.FormatForGit(date),
+			git.ShortHash(parent), datetime.FormatForGit(parentDate), basis.Describe(), basis),
+		Hint: "Every commit must be dated after its parents.\n" +
+			"Run again with --dry-run to review the planned dates.",
+	}
+}
+
+// NewBranchChronologyError creates an error when a commit of another branch,
+// which keeps its dates under --update-branches, would no longer be dated
+// after its rewritten parent.
+func NewBranchChronologyError(
+	branch string,
+	commit git.CommitObject,
+	date time.Time,
+	parent string,
+	parentDate time.Time,
+	basis datetime.ChronologyBasis,
+) *UserError {
+	return &UserError{
+		Type:    "ChronologyViolation",
+		Message: "Chronology violation on branch " + branch,
+		Details: fmt.Sprintf("Commit:           %s %s\nDated:            %s\nRewritten parent: %s, dated %s\n"+
+			"Checked against:  %s (--chronology-basis=%s)",
+			git.ShortHash(commit.Hash), commit.Subject(), datetime.FormatForGit(date),
+			git.ShortHash(parent), datetime.FormatForGit(parentDate), basis.Describe(), basis),
+		Hint: "The commits of other branches keep their dates under --update-branches.\n" +
+			"To fix this:\n" +
+			"  - Rewrite the dates of " + branch + " first, or\n" +
+			"  - Run again without --update-branches to leave " + branch + " on the old commits",
+	}
+}
+
+// NewInvalidOffsetError creates an error for a malformed shift offset.
+func NewInvalidOffsetError(provided string) *UserError {
+	return &UserError{
+		Type:    "InvalidOffset",
+		Message: "Invalid offset",
+		Details: "The offset must be a signed duration.\n\nYou provided: " + provided,
+		Hint:    "Use Go duration syntax, e.g. +2h, -90m or -1h30m (days as hours: +48h).",
+	}
+}
+
+// NewShiftChronologyError creates an error when shifting a range backwards
+// would date a commit before its parent outside the range.
+func NewShiftChronologyError(
+	commit git.CommitObject,
+	date time.Time,
+	parent string,
+	parentDate time.Time,
+	offset, limit time.Duration,
+) *UserError {
+	hint := "Commits must stay after their parents; shift by less, or include the parent in the range."
+	if limit >= 0 {
+		hint = fmt.Sprintf("The largest backward shift keeping %s after its parent is -%s.\n",
+			git.ShortHash(commit.Hash), limit) + "Shift by less, or include the parent in the range."
+	}
+
+	return &UserError{
+		Type:    "ChronologyViolation",
+		Message: "Chronology violation",
+		Details: fmt.Sprintf("Shifted by %s, commit %s (%s) would be dated: %s\n"+
+			"Its parent %s, outside the range, is dated: %s",
+			offset, git.ShortHash(commit.Hash), commit.Subject(), datetime.FormatForGit(date),
+			git.ShortHash(parent), datetime.FormatForGit(parentDate)),
+		Hint: hint,
+	}
+}
+
+// NewInvalidDistributionError creates an error for an unknown spread distribution.
+func NewInvalidDistributionError(provided string) *UserError {
+	return &UserError{
+		Type:    "InvalidDistribution",
+		Message: "Invalid distribution",
+		Details: "Valid distributions: even, working-hours, random\n\nYou provided: " + provided,
+		Hint:    "Use --distribution=random --seed=<n> to draw the same random dates again.",
+	}
+}
+
+// NewInvalidConfigError creates an error for a malformed gitcommit setting
+// in git config.
+func NewInvalidConfigError(key, value, expected string) *UserError {
+	return &UserError{
+		Type:    "InvalidConfig",
+		Message: "Invalid configuration",
+		Details: fmt.Sprintf("git config %s is invalid: %s\n\nExpected: %s", key, value, expected),
+		Hint:    fmt.Sprintf("To fix this:\n  - Correct it with: git config %s <value>\n  - Or remove it with: git config --unset-all %s", key, key),
+	}
+}
+
+// NewSpreadWindowError creates an error when a window has too little free
+// time for the commits of the range.
+func NewSpreadWindowError(from, to string, count int) *UserError {
+	return &UserError{
+		Type:    "SpreadWindowTooSmall",
+		Message: "Window too small",
+		Details: fmt.Sprintf("The window from %s to %s has no room for %d distinct dates\n"+
+			"once holidays and the time outside working hours are left out.", from, to, count),
+		Hint: "To fix this:\n  - Widen the window with --from and --to\n" +
+			"  - Or check the " + WorkingHoursConfigKey + ", " + WorkingDaysConfigKey + " and " +
+			HolidayConfigKey + " settings",
+	}
+}
+
+// NewTodoListError creates an error when the editor leaves a todo list with
+// unreadable lines unchanged.
+func NewTodoListError(path string, problems []string) *UserError {
+	return &UserError{
+		Type:    "InvalidTodoList",
+		Message: "Invalid todo list",
+		Details: "The todo list still has lines that cannot be read:\n  " + strings.Join(problems, "\n  "),
+		Hint: "The list was left unchanged in the editor, so nothing was rewritten.\n" +
+			"Your last version is in " + path + ".",
+	}
+}
+
+// NewTodoChronologyError creates an error when edited dates put commits
+// before their parents.
+func NewTodoChronologyError(violations []string, basis datetime.ChronologyBasis) *UserError {
+	return &UserError{
+		Type:    "ChronologyViolation",
+		Message: "Chronology violation",
+		Details: fmt.Sprintf("The edited dates put %d commit(s) before a parent:\n  %s\n\n"+
+			"Checked against: %s (--chronology-basis=%s)",
+			len(violations), strings.Join(violations, "\n  "), basis.Describe(), basis),
+		Hint: "Nothing was rewritten. Every commit must be dated after its parents;\n" +
+			"run gitcommit edit-dates again and fix the lines above.",
+	}
+}
+
+// NewInvalidZoneError creates an error for a zone that is neither an offset
+// nor a known location. where tells where it was given.
+func NewInvalidZoneError(where, provided string) *UserError {
+	return &UserError{
+		Type:    "InvalidZone",
+		Message: "Invalid zone",
+		Details: fmt.Sprintf("%s: %q is not a zone.", where, provided),
+		Hint: "Use a fixed offset (+0100, -05:30), UTC, or an IANA location\n" +
+			"such as Europe/Paris, whose offset follows daylight saving.",
+	}
+}
+
+// NewBackupError creates an error when the refs an operation moves cannot
+// be backed up, which stops the operation before it changes anything.
+func NewBackupError(gitError string) *UserError {
+	return &UserError{
+		Type:    "Backup",
+		Message: "Cannot back up the refs to be moved",
+		Details: "Git error: " + gitError,
+		Hint: "Nothing was changed. Check that the repository is writable\n" +
+			"and that no refs/gitcommit/backup ref is locked.",
+	}
+}
+
+// NewUnknownBackupError creates an error when no single backup matches the
+// ID given to gitcommit undo.
+func NewUnknownBackupError(id, reason string) *UserError {
+	return &UserError{
+		Type:    "UnknownBackup",
+		Message: "Unknown backup",
+		Details: fmt.Sprintf("No single backup matches %q: %s.", id, reason),
+		Hint:    "To fix this:\n  - List the backups: gitcommit undo --list\n  - Give more characters of the ID",
+	}
+}
+
+// NewNoBackupError creates an error when gitcommit undo finds no backup at all.
+func NewNoBackupError() *UserError {
+	return &UserError{
+		Type:    "NoBackup",
+		Message: "Nothing to undo",
+		Details: "There is no backup under refs/gitcommit/backup.",
+		Hint:    "Backups are made by the gitcommit commands that move refs; they may have been pruned.",
+	}
+}
+
+// NewUndoError creates an error when a ref saved by a backup cannot be restored.
+func NewUndoError(ref, gitError string) *UserError {
+	return &UserError{
+		Type:    "Undo",
+		Message: "Cannot restore " + ref,
+		Details: "Git error: " + gitError,
+		Hint: "To fix this:\n  - Commit or stash local changes that conflict with the restored commit\n" +
+			"  - Or move the branch only, leaving the working tree alone: gitcommit undo --soft\n" +
+			"  - Nothing was restored: every ref still points where it did",
+	}
+}
+
+// NewUndoDetachedHeadError creates an error when a backup saved a detached
+// HEAD but HEAD is now on a branch, which restoring would move instead.
+func NewUndoDetachedHeadError(hash string) *UserError {
+	return &UserError{
+		Type:    "UndoDetachedHead",
+		Message: "Cannot restore a detached HEAD from a branch",
+		Details: fmt.Sprintf("The backup saved a detached HEAD at %s, but HEAD is now on a branch.", git.ShortHash(hash)),
+		Hint:    "Detach HEAD first, then undo again: git checkout --detach",
+	}
+}
+
+// NewRewritePublishedError creates an error when a rewrite would change
+// commits that are already on a remote-tracking branch.
+func NewRewritePublishedError(commits []string) *UserError {
+	return &UserError{
+		Type:    "RewritePublished",
+		Message: "Refusing to rewrite published commits",
+		Details: "These commits are on a remote-tracking branch:\n  " + strings.Join(commits, "\n  "),
+		Hint: "Rewriting them forces everyone who fetched them to recover.\n" +
+			"To rewrite them anyway, and then push with --force-with-lease, use --force.",
+	}
+}
+
+// NewRewriteError creates an error when the history to rewrite cannot be
+// read, or a rewritten object cannot be written.
+func NewRewriteError(gitError string) *UserError {
+	return &UserError{
+		Type:    "RewriteFailed",
+		Message: "Cannot rewrite history",
+		Details: "Git error: " + gitError + "\n\nNo ref was moved.",
+		Hint: "To investigate:\n  - Check the objects in the range: git fsck\n" +
+			"  - Inspect a commit: git cat-file -p <commit>",
+	}
+}
+
+// NewResignHeadersError creates an error when --resign meets a commit whose
+// headers, such as mergetag, cannot be written along with a new signature.
+func NewResignHeadersError(gitError string) *UserError {
+	return &UserError{
+		Type:    "ResignHeaders",
+		Message: "Rewritten commit could not be signed",
+		Details: "Error: " + gitError + "\n\nNo ref was moved.",
+		Hint: "Git can only sign a commit it writes itself, which drops such headers.\n" +
+			"Rewrite without --resign to keep the headers, unsigned.",
+	}
+}
+
+// NewRefUpdateError creates an error when the ref at the tip of a rewrite cannot be moved.
+func NewRefUpdateError(ref, gitError string) *UserError {
+	return &UserError{
+		Type:    "RefUpdate",
+		Message: "Cannot update " + ref,
+		Details: "Git error: " + gitError,
+		Hint: "The rewritten commits were created but nothing points at them, so no history changed.\n" +
+			"If another command moved the ref in the meantime, run this one again.",
+	}
+}
+
+// NewSequenceChronologyError creates an error when a planned date does not
+// follow the date planned for the commit before it.
+func NewSequenceChronologyError(position int, shortHash string, date, previous time.Time) *UserError {
+	return &UserError{
+		Type:    "ChronologyViolation",
+		Message: "Chronology violation",
+		Details: fmt.Sprintf("Commit %d (%s) would be dated: %s\nThe commit before it:         %s",
+			position, shortHash, datetime.FormatForGit(date), datetime.FormatForGit(previous)),
+		Hint: "Every commit must be dated after the one before it.\n" +
+			"Give a later explicit date, or use --interval instead of --preserve-gaps\n" +
+			"when the original commits are not in date order.",
+	}
+}
+
+// NewOperationInProgressError creates an error when a command needs a quiet
+// repository but a merge, cherry-pick, revert or rebase is in progress.
+func NewOperationInProgressError(operation git.Operation) *UserError {
+	return &UserError{
+		Type:    "OperationInProgress",
+		Message: "A " + string(operation) + " is in progress",
+		Details: fmt.Sprintf("Finish or abort the %s before starting another operation.", operation),
+		Hint: fmt.Sprintf("To fix this:\n  - Conclude it: git %s --continue (or gitcommit <date> for a merge, cherry-pick or revert)\n"+
+			"  - Or give up on it: git %s --abort", operation, operation),
+	}
+}
+
+// NewStagedChangesPresentError creates an error when staged changes would be
+// mixed into commits created by another command.
+func NewStagedChangesPresentError() *UserError {
+	return &UserError{
+		Type:    "StagedChangesPresent",
+		Message: "Staged changes present",
+		Details: "The index has changes that would end up in the new commits.",
+		Hint: "To fix this:\n  - Commit them first: gitcommit <date> <message>\n" +
+			"  - Or set them aside: git stash",
+	}
+}
+
+// NewPickConflictError creates an error when picking a commit stops on conflicts.
+func NewPickConflictError(hash string, conflicts, resume []string) *UserError {
+	return &UserError{
+		Type:    "PickConflict",
+		Message: "Pick stopped on conflicts",
+		Details: fmt.Sprintf("Picking %s stopped on conflicts in:\n  %s",
+			git.ShortHash(hash), strings.Join(conflicts, "\n  ")),
+		Hint: "To fix this:\n  - Resolve the conflicts and stage them: git add <files>\n" +
+			"  - Then commit and pick the rest with the planned dates:\n      " +
+			strings.Join(resume, "\n      ") + "\n" +
+			"  - Or give up on this commit: git reset --merge",
+	}
+}
+
+// NewTagMessageRequiredError creates an error when gitcommit tag gets no message.
+func NewTagMessageRequiredError() *UserError {
+	return &UserError{
+		Type:    "TagMessageRequired",
+		Message: "Tag message required",
+		Details: "An annotated tag needs a message.",
+		Hint:    "Provide one with -m <message> or -F <file>.",
+	}
+}
+
+// NewTagChronologyError creates an error when a tag would be dated before
+// the commit it points to was committed.
+func NewTagChronologyError(providedDate string, tagged git.CommitDates) *UserError {
+	return &UserError{
+		Type:    "ChronologyViolation",
+		Message: "Chronology violation",
+		Details: fmt.Sprintf("Your date:           %s\nTagged commit:       %s\nIts committer date:  %s",
+			providedDate, git.ShortHash(tagged.Hash), datetime.FormatForGit(tagged.CommitterDate)),
+		Hint: "A tag cannot be dated before the commit it points to was committed.",
+	}
+}
+
+// NewInvalidTagNameError creates an error for a name git does not accept for a tag.
+func NewInvalidTagNameError(name string) *UserError {
+	return &UserError{
+		Type:    "InvalidTagName",
+		Message: "Invalid tag name",
+		Details: fmt.Sprintf("\"%s\" is not a valid tag name.", name),
+		Hint:    "Tag names cannot contain spaces, '..', '~', '^', ':', '?', '*' or '[', nor start with '-'.",
+	}
+}
+
+// NewTagExistsError creates an error when the tag name is already taken.
+func NewTagExistsError(name string) *UserError {
+	return &UserError{
+		Type:    "TagExists",
+		Message: "Tag already exists",
+		Details: fmt.Sprintf("The tag \"%s\" already exists.", name),
+		Hint: "To fix this:\n  - Choose another name\n" +
+			"  - Or replace it with -f (retagging a published tag confuses everyone who fetched it)",
+	}
+}
+
+// NewTagSigningFailedError creates an error when git cannot sign a tag.
+func NewTagSigningFailedError(format, gitError string) *UserError {
+	return &UserError{
+		Type:    "SigningFailed",
+		Message: "Tag could not be signed",
+		Details: fmt.Sprintf("Signature format: %s (gpg.format)\nGit error: %s", format, gitError),
+		Hint: "To fix this:\n  - Select a key: -u <keyid> or git config user.signingkey <keyid>\n" +
+			"  - Or create the tag without a signature: --no-sign",
+	}
+}
+
+// NewResignFailedError creates an error when --resign could not sign a rewritten commit.
+func NewResignFailedError(format, gitError string) *UserError {
+	hint := "To fix this:\n  - Check that gpg works: echo test | gpg --clearsign\n" +
+		"  - Select a key: git config user.signingkey <keyid>"
+	if format == git.SignatureFormatSSH {
+		hint = "To fix this:\n  - Point user.signingkey at your key: git config user.signingkey ~/.ssh/id_ed25519.pub"
+	}
+	hint += "\n  - Or rewrite without --resign, dropping the signatures"
+
+	return &UserError{
+		Type:    "SigningFailed",
+		Message: "Rewritten commit could not be signed",
+		Details: fmt.Sprintf("Signature format: %s (gpg.format)\nGit error: %s\n\nNo ref was moved.", format, gitError),
+		Hint:    hint,
+	}
+}
+
+// NewRebaseInProgressError creates an error when committing during a rebase or git am.
+func NewRebaseInProgressError(operation git.Operation) *UserError {
+	command := "git rebase"
+	if operation == git.OperationApplyMailbox {
+		command = "git am"
+	}
+
+	return &UserError{
+		Type:    "RebaseInProgress",
+		Message: "Cannot commit during a " + string(operation),
+		Details: fmt.Sprintf("A %s is in progress. It creates its own commits when continued,\n"+
+			"so a commit made now would be out of sequence.", operation),
+		Hint: "To fix this:\n  - Stage the resolved files, then continue: " + command + " --continue\n" +
+			"  - Or give up on it: " + command + " --abort\n" +
+			"  - Then set the dates of the new commits with gitcommit --amend",
+	}
+}
+
+// NewConflictingOptionsError creates an error when two options cannot be used together.
+func NewConflictingOptionsError(first, second string) *UserError {
+	return &UserError{
+		Type:    "ConflictingOptions",
+		Message: "Conflicting options",
+		Details: fmt.Sprintf("%s cannot be combined with %s.", first, second),
+		Hint:    "Run 'gitcommit --help' for more information.",
+	}
+}
+
+// NewUnknownIdentityError creates an error when git does not know who is committing.
+func NewUnknownIdentityError(gitError string) *UserError {
+	return &UserError{
+		Type:    "UnknownIdentity",
+		Message: "Git identity not configured",
+		Details: "Git error: " + gitError,
+		Hint: "To fix this:\n  - Set your name: git config --global user.name \"Your Name\"\n" +
+			"  - Set your email: git config --global user.email \"you@example.com\"\n" +
+			"  - Omit --global to set them for this repository only",
+	}
+}
+
+// NewInvalidIdentityError creates an error when --author or --committer is malformed.
+func NewInvalidIdentityError(flag, provided string) *UserError {
+	return &UserError{
+		Type:    "InvalidIdentity",
+		Message: "Invalid identity",
+		Details: fmt.Sprintf(
+			"Expected format: Name <email>\nExample:         Jane Doe <jane@example.com>\n\n"+
+				"You provided:    %s %s",
+			flag,
+			provided,
+		),
+		Hint: "Quote the identity so that the shell passes it as one argument:\n" +
+			"  " + flag + " \"Jane Doe <jane@example.com>\"",
+	}
+}
+
+// NewInvalidTrailerError creates an error when a --trailer value is malformed.
+func NewInvalidTrailerError(provided string) *UserError {
+	return &UserError{
+		Type:    "InvalidTrailer",
+		Message: "Invalid trailer",
+		Details: fmt.Sprintf(
+			"Expected format: key=value or \"key: value\"\nExample:         Refs=#123\n\n"+
+				"You provided:    --trailer %s",
+			provided,
+		),
+		Hint: "Keys may contain only letters, digits and hyphens, and the value must not be empty.",
+	}
+}
+
+// NewHookRejectedError creates an error when a commit hook rejects the commit.
+func NewHookRejectedError(hooks []string, hookOutput string) *UserError {
+	details := "Active hooks: " + strings.Join(hooks, ", ")
+	if hookOutput != "" {
+		details += "\n\nHook output:\n" + hookOutput
+	}
+
+	return &UserError{
+		Type:    "HookRejected",
+		Message: "Commit rejected by a Git hook",
+		Details: details,
+		Hint: "To fix this:\n  - Address the problems reported by the hook above\n" +
+			"  - Hooks are in .git/hooks (or the directory set by core.hooksPath)",
+	}
+}
+
+// NewIndexLockedError creates an error when another process holds the index lock.
+func NewIndexLockedError(lockFile string) *UserError {
+	if lockFile == "" {
+		lockFile = ".git/index.lock"
+	}
+
+	return &UserError{
+		Type:    "IndexLocked",
+		Message: "Git index is locked",
+		Details: fmt.Sprintf("The lock file %s exists.", lockFile),
+		Hint: "To fix this:\n  - Wait for any other Git process (editor, IDE, git gc) to finish\n" +
+			"  - If no Git process is running, remove the stale lock: rm " + lockFile,
+	}
+}
+
+// NewDetachedHeadError creates an error when an operation needs a branch but HEAD is detached.
+func NewDetachedHeadError(gitError string) *UserError {
+	return &UserError{
+		Type:    "DetachedHead",
+		Message: "Not on a branch",
+		Details: "HEAD is detached.\nGit error: " + gitError,
+		Hint: "To fix this:\n  - Create a branch here: git switch -c <branch>\n" +
+			"  - Or return to an existing branch: git switch <branch>",
+	}
+}
+
+// NewUnmergedPathsError creates an error when the index contains unresolved conflicts.
+func NewUnmergedPathsError(gitError string) *UserError {
+	return &UserError{
+		Type:    "UnmergedPaths",
+		Message: "Unresolved merge conflicts",
+		Details: "Git error: " + gitError,
+		Hint: "To fix this:\n  - List conflicted files: git diff --name-only --diff-filter=U\n" +
+			"  - Resolve them, then mark as resolved: git add <files>",
+	}
+}
+
+// NewSigningFailedError creates an error when git cannot sign the commit.
+func NewSigningFailedError(format, gitError string) *UserError {
+	hint := "To fix this:\n  - Check that gpg works: echo test | gpg --clearsign\n" +
+		"  - Select a key: -S<keyid> or git config user.signingkey <keyid>"
+	if format == git.SignatureFormatSSH {
+		hint = "To fix this:\n  - Point user.signingkey at your key: git config user.signingkey ~/.ssh/id_ed25519.pub\n" +
+			"  - Or pass the key file directly: -S<path-to-key>"
+	}
+	hint += "\n  - Or commit without a signature: --no-gpg-sign"
+
+	return &UserError{
+		Type:    "SigningFailed",
+		Message: "Commit could not be signed",
+		Details: fmt.Sprintf("Signature format: %s (gpg.format)\nGit error: %s", format, gitError),
+		Hint:    hint,
+	}
+}
+
+// NewSignatureVerificationError creates an error when the commit was created
+// but its signature is missing or invalid.
+func NewSignatureVerificationError(signature git.Signature) *UserError {
+	details := "The commit was created, but git reports: " + signature.Describe()
+	if signature.Key != "" {
+		details += "\nKey: " + signature.Key
+	}
+
+	return &UserError{
+		Type:    "SignatureVerification",
+		Message: "Commit signature is not valid",
+		Details: details,
+		Hint: "To fix this:\n  - Inspect the signature: git log -1 --show-signature\n" +
+			"  - Re-sign the commit: git commit --amend --no-edit -S\n" +
+			"  - Or undo the commit, keeping its changes: git reset --soft HEAD^",
+	}
+}
+
+// NewInvalidProvenanceModeError creates an error for an unrecognized provenance mode.
+func NewInvalidProvenanceModeError(provided string) *UserError {
+	return &UserError{
+		Type:    "InvalidProvenanceMode",
+		Message: "Invalid provenance mode",
+		Details: fmt.Sprintf("Valid modes: note, trailer\n\nYou provided: %s", provided),
+		Hint: "Use --provenance=note to record a git note under " + ProvenanceNotesRef + ",\n" +
+			"or --provenance=trailer to add trailers to the commit message.\n" +
+			"The default comes from git config " + ProvenanceConfigKey + ".",
+	}
+}
+
+// NewProvenanceNoteError creates an error when the provenance note cannot be attached.
+func NewProvenanceNoteError(gitError string) *UserError {
+	return &UserError{
+		Type:    "ProvenanceNote",
+		Message: "Provenance could not be recorded",
+		Details: "The commit was created, but the provenance note was not attached.\nGit error: " + gitError,
+		Hint:    "Check that git notes work here: git notes --ref=" + ProvenanceNotesRef + " list",
+	}
+}
+
+// NewNoProvenanceError creates an error when a commit carries no provenance.
+func NewNoProvenanceError(rev string) *UserError {
+	return &UserError{
+		Type:    "NoProvenance",
+		Message: "No provenance recorded",
+		Details: fmt.Sprintf("Commit %s has no note under %s and no provenance trailers.", rev, ProvenanceNotesRef),
+		Hint: "Notes are not fetched by default; fetch them with:\n" +
+			"  git fetch origin " + ProvenanceNotesRef + ":" + ProvenanceNotesRef,
+	}
+}
+
+// NewNothingToAmendError creates an error when --amend is used before the first commit.
+func NewNothingToAmendError() *UserError {
+	return &UserError{
+		Type:    "NothingToAmend",
+		Message: "Nothing to amend",
+		Details: "The repository has no commits yet.",
+		Hint:    "Create the first commit without --amend.",
+	}
+}
+
+// NewAmendPublishedError creates an error when amending a commit that is on a remote-tracking branch.
+func NewAmendPublishedError(remoteBranches []string) *UserError {
+	return &UserError{
+		Type:    "AmendPublished",
+		Message: "Commit already published",
+		Details: "HEAD is contained in: " + strings.Join(remoteBranches, ", "),
+		Hint: "Amending rewrites the commit and will require a force push.\n" +
+			"Use --force to amend it anyway.",
+	}
+}
+
+// NewEmptyMessageError creates an error when the commit message is empty.
+func NewEmptyMessageError(source string) *UserError {
+	return &UserError{
+		Type:    "EmptyMessage",
+		Message: "Empty commit message",
+		Details: "The commit message from " + source + " is empty.",
+		Hint: "Provide a message with <message>, -m, -F <file> or --edit.\n" +
+			"Lines starting with '#' are ignored when using the editor.",
+	}
+}
+
+// NewMessageFileError creates an error when the -F message file cannot be read.
+func NewMessageFileError(file, reason string) *UserError {
+	if file == StdinMessageFile {
+		file = "standard input"
+	}
+
+	return &UserError{
+		Type:    "MessageFile",
+		Message: "Cannot read commit message",
+		Details: fmt.Sprintf("File:   %s\nReason: %s", file, reason),
+		Hint:    "Paths given to -F are relative to the current directory; use -F - to read standard input.",
+	}
+}
+
+// NewEditorError creates an error when the message editor cannot be run.
+func NewEditorError(editor, reason string) *UserError {
+	details := "Reason: " + reason
+	if editor != "" {
+		details = fmt.Sprintf("Editor: %s\n%s", editor, details)
+	}
+
+	return &UserError{
+		Type:    "Editor",
+		Message: "Cannot edit commit message",
+		Details: details,
+		Hint: "Git picks the editor from GIT_EDITOR, core.editor, VISUAL or EDITOR.\n" +
+			"Set one, e.g.: git config --global core.editor vim",
+	}
+}
+
 // NewGitCommandError creates an error when a Git command fails.
 func NewGitCommandError(gitError string) *UserError {
 	return &UserError{
@@ -88,17 +894,32 @@ func NewMissingArgumentsError(expected, received int) *UserError {
 		Type:    "MissingArguments",
 		Message: "Missing required arguments",
 		Details: fmt.Sprintf(
-			"Usage: gitcommit <date> <message>\n\nExpected: %d arguments\nReceived: %d argument(s)",
+			"Usage: gitcommit [flags] <date> <message> [-- <pathspec>...]\n"+
+				"       gitcommit [flags] <date> (-m <message>... | -F <file> | --edit) [-- <pathspec>...]\n"+
+				"       gitcommit [flags] --amend <date> [<message>] [-- <pathspec>...]\n\n"+
+				"Expected: %d arguments before --\nReceived: %d argument(s)",
 			expected,
 			received,
 		),
 		Hint: "Examples:\n" +
 			"  gitcommit \"2025-02-05 20:19:19\" \"Add new feature\"\n" +
-			"  gitcommit \"2025-12-31 23:59:59\" \"End of year commit\"\n\n" +
+			"  gitcommit \"2025-12-31 23:59:59\" \"End of year commit\"\n" +
+			"  gitcommit \"2025-12-31 23:59:59\" \"Update docs\" -- README.md docs/\n" +
+			"  gitcommit -F message.txt \"2025-12-31 23:59:59\"\n\n" +
 			"Run 'gitcommit --help' for more information.",
 	}
 }
 
+// NewCommandArgumentsError creates an error when a subcommand gets the wrong number of arguments.
+func NewCommandArgumentsError(command, usage string, received int) *UserError {
+	return &UserError{
+		Type:    "MissingArguments",
+		Message: "Wrong number of arguments",
+		Details: fmt.Sprintf("Usage: gitcommit %s %s\n\nReceived: %d argument(s)", command, usage, received),
+		Hint:    fmt.Sprintf("Run 'gitcommit %s --help' for more information.", command),
+	}
+}
+
 // Error implements the error interface.
 func (e *UserError) Error() string {
 	if e.Details != "" && e.Hint != "" {
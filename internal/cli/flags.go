@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"flag"
+	"strings"
+)
+
+// StringList is a flag.Value that collects every occurrence of a repeatable flag.
+type StringList []string
+
+// String implements flag.Value.
+func (l *StringList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ", ")
+}
+
+// Set implements flag.Value by appending the value.
+func (l *StringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// SignFlag is a flag.Value for -S[<keyid>] and --gpg-sign[=<keyid>]: given
+// alone it requests signing with the default key, given a value it also
+// selects the key.
+type SignFlag struct {
+	// Enabled reports whether the flag was given.
+	Enabled bool
+
+	// KeyID is the key given with the flag, or empty for the default key.
+	KeyID string
+}
+
+// String implements flag.Value.
+func (s *SignFlag) String() string {
+	if s == nil || !s.Enabled {
+		return ""
+	}
+	return s.KeyID
+}
+
+// Set implements flag.Value. The flag package passes "true" when the flag is given alone.
+func (s *SignFlag) Set(value string) error {
+	s.Enabled = true
+	if value != "true" {
+		s.KeyID = value
+	}
+	return nil
+}
+
+// IsBoolFlag lets the flag be given without a value.
+func (s *SignFlag) IsBoolFlag() bool {
+	return true
+}
+
+// NormalizeSignFlag rewrites git's attached form -S<keyid> into -S=<keyid>,
+// which the flag package understands. Arguments after "--" and values of
+// -m and -F are left alone.
+func NormalizeSignFlag(args []string) []string {
+	normalized := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == PathspecSeparator {
+			return append(normalized, args[i:]...)
+		}
+
+		takesValue := i > 0 && (args[i-1] == "-m" || args[i-1] == "-F")
+		if !takesValue && len(arg) > len("-S") && strings.HasPrefix(arg, "-S") && arg[2] != '=' {
+			arg = "-S=" + arg[2:]
+		}
+		normalized = append(normalized, arg)
+	}
+	return normalized
+}
+
+// ParseInterspersed parses flags given before, between or after the
+// positional arguments, like git does, and returns the positional arguments.
+// Everything after "--" is positional.
+func ParseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		_ = flags.Parse(args)
+		remaining := flags.Args()
+		if len(remaining) == 0 {
+			return positional
+		}
+		if consumed := len(args) - len(remaining); consumed > 0 && args[consumed-1] == PathspecSeparator {
+			return append(positional, remaining...)
+		}
+		positional = append(positional, remaining[0])
+		args = remaining[1:]
+	}
+}
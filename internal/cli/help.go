@@ -5,7 +5,21 @@ func HelpText() string {
 	return `gitcommit - Create Git commits with custom dates
 
 Usage:
-  gitcommit <date> <message>
+  gitcommit [flags] <date> <message> [-- <pathspec>...]
+  gitcommit [flags] <date> (-m <message>... | -F <file> | --edit) [-- <pathspec>...]
+  gitcommit [flags] --amend <date> [<message>] [-- <pathspec>...]
+  gitcommit [flags] <date> [<message>]   (to conclude a merge, cherry-pick or revert)
+  gitcommit edit-dates [flags] <range>
+  gitcommit log [-n <count>] [--operation <name>] [--json]
+  gitcommit merge [flags] <date> <branch>...
+  gitcommit pick [flags] <date> <rev>... [<date> <rev>...]...
+  gitcommit provenance [<rev>]
+  gitcommit redate [flags] <range> (--start <date> | --plan <file> | --set <rev>=<date>...)
+  gitcommit shift [flags] <range> <+/-duration>
+  gitcommit spread [flags] <range> --from <date> --to <date>
+  gitcommit tag [flags] <date> <name> [<rev>] -m <message>
+  gitcommit tz-normalize [flags] <range> (--to <zone> | --map <file>)
+  gitcommit undo [--soft] [<id>] | --list | --prune
   gitcommit --help
   gitcommit --version
 
@@ -15,9 +29,74 @@ Arguments:
 
   <message>  Commit message (quote if contains spaces)
 
+  <pathspec> Paths to commit, given after --. Only these paths are
+             staged and committed (including new and deleted files);
+             anything else already staged stays in the index
+
 Flags:
   --help, -h       Show this help message
   --version, -v    Show version information
+  --chronology-basis=<basis>
+                   Date of the last commit that new commits must follow:
+                     author     author date (%aI)
+                     committer  committer date (%cI)
+                     max        later of the two (default)
+  --chronology-depth=<n>
+                   Also check n generations of parents of HEAD, so every
+                   side of a merge counts (default: 1, 0 = HEAD only).
+                   While finishing a merge, the commits in MERGE_HEAD
+                   are always checked too
+  --against <ref>  Also require the date to follow the tip of <ref>
+                   (repeatable, e.g. --against main --against origin/main)
+  --against-all-refs
+                   Require the date to follow the tips of all branches,
+                   remote-tracking branches and tags
+  -m <message>     Use <message> as a paragraph of the commit message
+                   (repeatable; replaces the <message> argument)
+  -F <file>        Read the commit message from <file>, or from standard
+                   input with -F -
+  --edit, -e       Write or adjust the message in the editor
+                   ($GIT_EDITOR, core.editor, $VISUAL or $EDITOR). The
+                   template shows the chosen date; lines starting with
+                   '#' are ignored
+  --allow-empty    Allow a commit that records no changes
+  --all, -a        Stage all modified and deleted tracked files
+                   (cannot be combined with paths after --)
+  --amend          Re-date the last commit instead of creating a new one.
+                   Staged changes are included; without <message> the
+                   last commit's message is kept. The date is checked
+                   against the parents of the last commit
+  --keep-content   With --amend, keep the last commit's content and
+                   ignore staged changes
+  --force          With --amend, allow rewriting a commit that is
+                   already on a remote-tracking branch
+  --author "Name <email>"
+                   Record another person as the author. The committer
+                   stays you unless --committer is given. A warning is
+                   shown when .mailmap maps the identity elsewhere, or
+                   when it has your name with another email or the reverse
+  --committer "Name <email>"
+                   Record another person as the committer, with a
+                   warning when it is not your configured identity
+  --signoff, -s    Add a Signed-off-by trailer for the committer
+  --co-author "Name <email>"
+                   Add a Co-authored-by trailer (repeatable)
+  --trailer <key>=<value>
+                   Add a trailer such as Refs=#123 (repeatable).
+                   Trailers already in the message are not repeated
+  -S[<keyid>], --gpg-sign[=<keyid>]
+                   Sign the commit, with <keyid> or user.signingkey.
+                   gpg.format=ssh signs with an SSH key. The signature
+                   is verified after committing
+  --no-gpg-sign    Do not sign, even if commit.gpgsign is set
+  --provenance=<mode>
+                   Record the real creation time, the tool version and
+                   the date as given: "note" attaches a git note under
+                   refs/notes/gitcommit, "trailer" adds Gitcommit-*
+                   trailers to the message. Defaults to the
+                   gitcommit.provenance config key
+  --show-git-output
+                   Echo git's own output as it runs, including on failure
 
 Description:
   gitcommit allows you to create Git commits with custom author and
@@ -26,10 +105,21 @@ Description:
   - Maintaining chronological commit history
   - Testing date-dependent Git workflows
 
+Merges, cherry-picks and reverts:
+  When git stops a merge, cherry-pick or revert for you to commit (for
+  instance after resolving conflicts), gitcommit concludes it with the
+  chosen date. Without <message>, the message git prepared in MERGE_MSG
+  is used, without its comment lines. The date must follow every parent,
+  including the commits being merged. A cherry-pick keeps the original
+  author unless --author is given. Rebases and git am are refused:
+  continue them with git, then re-date their commits.
+
 Requirements:
   - Must be run inside a Git repository
   - Dates must be after the last commit (chronological order)
-  - Changes must be staged before committing (git add)
+  - Commit messages must not be empty
+  - Changes must be staged before committing (git add), unless
+    --all, paths after -- or --allow-empty are given
 
 Examples:
   # Create a commit with a specific date
@@ -38,6 +128,74 @@ Examples:
   # Commit at midnight on New Year's Day
   gitcommit "2025-01-01 00:00:00" "Happy New Year!"
 
+  # Only check against the author date of the last commit
+  gitcommit --chronology-basis=author "2025-02-06 09:00:00" "Fix typo"
+
+  # Commit only these files, leaving the rest of the index untouched
+  gitcommit "2025-02-06 11:00:00" "Update docs" -- README.md docs/
+
+  # Commit every modified tracked file
+  gitcommit --all "2025-02-06 12:00:00" "Fix all the things"
+
+  # Multi-paragraph message from a file, or from the editor
+  gitcommit -F message.txt "2025-02-06 10:00:00"
+  gitcommit --edit "2025-02-06 10:00:00"
+
+  # Fix the date of the commit just made, keeping its message
+  gitcommit --amend "2025-02-06 08:30:00"
+
+  # Backfill a colleague's work, keeping yourself as committer
+  gitcommit --author "Jane Doe <jane@example.com>" "2025-02-06 14:00:00" "Add report"
+
+  # Credit a pair-programming partner and reference an issue
+  gitcommit --co-author "Jane Doe <jane@example.com>" --trailer Refs=#123 \
+    "2025-02-06 14:30:00" "Fix parser"
+
+  # Sign the commit with a specific SSH key
+  gitcommit -S~/.ssh/id_ed25519.pub "2025-02-06 15:00:00" "Signed work"
+
+  # Conclude a merge after resolving its conflicts, keeping MERGE_MSG
+  git add resolved.go
+  gitcommit "2025-02-06 17:00:00"
+
+  # Keep a feature branch commit later than everything on main
+  gitcommit --against main "2025-02-07 10:00:00" "Add feature branch work"
+
+  # Merge a branch with a dated merge commit
+  gitcommit merge --no-ff "2025-02-06 18:00:00" feature
+
+  # Move commits from a scratch branch onto this one, 15 minutes apart
+  gitcommit pick --interval=15m "2025-02-06 19:00:00" main..scratch
+
+  # Re-date the last three commits, an hour apart
+  gitcommit redate HEAD~3 --start "2025-02-06 09:00:00" --interval=1h
+
+  # Move a sprint committed with the wrong clock two hours back
+  gitcommit shift main~20..main -2h --dry-run
+
+  # Spread a week of offline work over its working hours
+  gitcommit spread HEAD~12 --from "2025-01-13 09:00:00" --to "2025-01-17 17:00:00" --distribution=working-hours
+
+  # Edit the dates of the last five commits in the editor
+  gitcommit edit-dates HEAD~5
+
+  # See which commits of main would change to the Paris zone
+  gitcommit tz-normalize main~50..main --to Europe/Paris --dry-run
+
+  # Tag a release with the date it was really cut
+  gitcommit tag "2025-02-07 17:00:00" v1.2.0 -m "Release 1.2.0"
+
+  # Put back the refs moved by the last gitcommit command
+  gitcommit undo --list
+  gitcommit undo
+
+  # Review the backdating done in this clone
+  gitcommit log -n 10
+
+  # Record that the commit was backdated, and read it back
+  gitcommit --provenance=note "2025-02-06 16:00:00" "Backdated work"
+  gitcommit provenance HEAD
+
   # Show version
   gitcommit --version
 
@@ -56,7 +214,510 @@ Error Messages:
   - Specific details about the issue
   - Suggestions for how to fix it
 
+  Common git failures (nothing to commit, unknown identity, hook
+  rejection, index.lock present, detached HEAD, unmerged paths,
+  signing failure) are recognized and reported with a tailored hint.
+
 For more information, visit:
   https://github.com/sgaunet/gitcommit
 `
 }
+
+// ProvenanceHelpText returns the help text for the provenance command.
+func ProvenanceHelpText() string {
+	return `gitcommit provenance - Show when a commit was really made
+
+Usage:
+  gitcommit provenance [<rev>]
+
+Arguments:
+  <rev>      Commit to inspect (default: HEAD)
+
+Description:
+  Shows the provenance recorded by gitcommit --provenance: the real
+  wall-clock time the commit was made, the gitcommit version and the
+  date exactly as it was given, next to the dates the commit claims.
+  The note under refs/notes/gitcommit is read first, then the
+  Gitcommit-* trailers of the commit message.
+
+  Notes are not pushed or fetched by default. Share them with:
+    git push origin refs/notes/gitcommit
+    git fetch origin refs/notes/gitcommit:refs/notes/gitcommit
+`
+}
+
+// MergeHelpText returns the help text for the merge command.
+func MergeHelpText() string {
+	return `gitcommit merge - Merge branches with a dated merge commit
+
+Usage:
+  gitcommit merge [flags] <date> <branch>...
+
+Arguments:
+  <date>     Date of the merge commit: YYYY-MM-DD HH:MM:SS
+  <branch>   Branches (or any commits) to merge into HEAD
+
+Flags:
+  --no-ff          Always create a merge commit
+  --ff-only        Refuse to merge unless it is a fast-forward
+  --squash         Squash the merged changes into a single ordinary
+                   commit, using the message git prepares (or -m)
+  -m <message>     Merge commit message paragraph (repeatable)
+  --chronology-basis=<basis>
+                   Date of the parents to check against: author,
+                   committer or max (default)
+  -S[<keyid>], --gpg-sign[=<keyid>]
+                   Sign the merge commit
+  --no-gpg-sign    Do not sign, even if commit.gpgsign is set
+  --show-git-output
+                   Echo git's own output as it runs
+
+Description:
+  The date must follow HEAD and the tip of every merged branch. By
+  default a merge that can fast-forward does so, like git merge: no
+  commit is created and the date is not used; use --no-ff to record a
+  dated merge commit.
+
+  When the merge stops on conflicts, resolve them, stage the files and
+  conclude the merge with: gitcommit <date>
+
+Examples:
+  gitcommit merge --no-ff "2025-02-06 18:00:00" feature
+  gitcommit merge -m "Merge release fixes" "2025-02-06 18:00:00" fixes
+  gitcommit merge --squash "2025-02-06 18:00:00" feature
+`
+}
+
+// PickHelpText returns the help text for the pick command.
+func PickHelpText() string {
+	return `gitcommit pick - Cherry-pick commits with new dates
+
+Usage:
+  gitcommit pick [flags] <date> <rev>... [<date> <rev>...]...
+
+Arguments:
+  <date>     Date of the next picked commit: YYYY-MM-DD HH:MM:SS
+  <rev>      Commit to pick, or a range such as main..scratch (picked
+             oldest first)
+
+Flags:
+  --interval=<duration>
+                   Time between commits without an explicit date
+                   (default 1m; e.g. 90s, 15m, 2h30m)
+  --preserve-gaps  Keep the gaps between the original author dates,
+                   shifted to start at the preceding explicit date
+  -x               Append "(cherry picked from commit ...)" to messages
+  --dry-run        Show the planned dates without picking
+  --chronology-basis=<basis>, --chronology-depth=<n>, --against <ref>
+                   Checks for the first date, as for commits
+  -S[<keyid>], --gpg-sign[=<keyid>], --no-gpg-sign
+                   Sign the new commits, or not
+  --show-git-output
+                   Echo git's own output as it runs
+
+Description:
+  Each revision is applied to HEAD and committed with its original
+  author and message. A date applies to the revision that follows it;
+  later revisions are dated --interval apart, or keep their original
+  gaps with --preserve-gaps, until the next explicit date. The first
+  date must follow HEAD and every date must follow the one before it;
+  all dates are checked before anything is picked.
+
+  Commits whose changes are already present are skipped. When a pick
+  stops on conflicts, the error shows the commands that commit it and
+  pick the rest with the dates already planned.
+
+Examples:
+  gitcommit pick "2025-02-06 19:00:00" abc1234 def5678
+  gitcommit pick --interval=15m "2025-02-06 19:00:00" main..scratch
+  gitcommit pick --preserve-gaps "2025-02-06 19:00:00" main..scratch
+  gitcommit pick "2025-02-06 19:00:00" abc1234 "2025-02-07 09:00:00" def5678
+`
+}
+
+// TagHelpText returns the help text for the tag command.
+func TagHelpText() string {
+	return `gitcommit tag - Create an annotated tag with a custom date
+
+Usage:
+  gitcommit tag [flags] <date> <name> [<rev>] (-m <message>... | -F <file>)
+
+Arguments:
+  <date>     Tagger date: YYYY-MM-DD HH:MM:SS
+  <name>     Name of the tag
+  <rev>      Commit to tag (default: HEAD)
+
+Flags:
+  -m <message>     Tag message paragraph (repeatable)
+  -F <file>        Read the tag message from a file (- for standard input)
+  -s               Sign the tag with the default key
+  -u <keyid>       Sign the tag with the given key
+  --no-sign        Do not sign, even if tag.gpgSign is set
+  -f               Replace an existing tag of the same name
+  --show-git-output
+                   Echo git's own output as it runs
+
+Description:
+  Creates an annotated tag whose tagger date is <date>. The date may
+  not be earlier than the committer date of the tagged commit.
+
+Examples:
+  gitcommit tag "2025-02-07 17:00:00" v1.2.0 -m "Release 1.2.0"
+  gitcommit tag -s "2025-02-07 17:00:00" v1.2.0 abc1234 -F notes.txt
+`
+}
+
+// RedateHelpText returns the help text for the redate command.
+func RedateHelpText() string {
+	return `gitcommit redate - Rewrite the dates of existing commits
+
+Usage:
+  gitcommit redate [flags] <range> (--start <date> | --plan <file> | --set <rev>=<date>...)
+
+Arguments:
+  <range>    Commits to rewrite: <base>..<branch>, <base>.. or <base>
+             (e.g. main~3..main, HEAD~3.. or HEAD~3). The range must end
+             at HEAD or a local branch, which is moved to the result.
+
+Dates (exactly one source):
+  --start=<date>   Date of the first commit; the others follow at
+                   --interval (default 1m), or keep their original gaps
+                   with --preserve-gaps
+  --plan=<file>    File of "<rev> <date>" lines; blank lines and lines
+                   starting with # are ignored
+  --set <rev>=<date>
+                   Date of one commit (repeatable)
+  Commits not given a date by --plan or --set keep their dates.
+
+Flags:
+  --dates=<which>  Dates to rewrite: author, committer or both (default)
+  --dry-run        Show the old and new dates without rewriting
+  --force          Rewrite commits that are on a remote-tracking branch
+  --update-branches
+                   Also rewrite the other local branches that contain
+                   rewritten commits
+  --resign         Sign the rewritten commits with the configured key
+                   (user.signingkey, gpg.format)
+  --chronology-basis=<basis>
+                   Date compared between commits and their parents:
+                   author, committer or max (default)
+
+Description:
+  Commits are recreated with the same trees, messages, authors,
+  committers, parents and provenance notes; only the dates change.
+  Every commit with a new date must follow its parents, and the
+  commits after it must still follow it; all dates are checked before
+  anything is written.
+
+  A new date makes a new commit, which an old signature cannot cover.
+  With --resign, every rewritten commit and every recreated tag that
+  was signed is signed with your key, as git commit -S and git tag -s
+  would; otherwise the signatures are dropped, and the signed commits
+  and tags that lost theirs are listed, by --dry-run too. The merged
+  tag signatures recorded by merges of signed tags are dropped, and
+  listed, when the tagged commit is rewritten or with --resign.
+
+  Tags pointing at rewritten commits follow them: lightweight tags are
+  moved, and annotated tags are recreated with the same tagger, date
+  and message. With --update-branches, the other local branches that
+  contain rewritten commits follow too: their own commits keep their
+  dates and are recreated on top of the new history.
+
+  The branch and those refs are then moved in a single update recorded
+  in their reflogs as "gitcommit redate: <range>", so <branch>@{1}
+  names the previous history, which gitcommit undo restores. The
+  working tree and index are not touched.
+
+Examples:
+  gitcommit redate HEAD~3 --start "2025-02-06 09:00:00" --interval=1h
+  gitcommit redate main~5..main --start "2025-02-06 09:00:00" --preserve-gaps
+  gitcommit redate HEAD~2 --set HEAD~1="2025-02-06 12:00:00" --dates=author
+  gitcommit redate HEAD~10 --plan dates.txt --dry-run
+  gitcommit redate main~3 --start "2025-02-06 09:00:00" --update-branches
+  gitcommit redate HEAD~3 --start "2025-02-06 09:00:00" --resign
+`
+}
+
+// rewriteBehaviorHelp describes what the commands built on redate keep,
+// in the Description section of their help texts.
+const rewriteBehaviorHelp = `  Like redate, commits keep their trees, messages, identities and
+  merges, tags follow the commits they point to, signatures are
+  dropped unless --resign, and the branch moves in one update recorded
+  in its reflog.
+`
+
+// ShiftHelpText returns the help text for the shift command.
+func ShiftHelpText() string {
+	return `gitcommit shift - Move a range of commits by a fixed offset
+
+Usage:
+  gitcommit shift [flags] <range> <+/-duration>
+
+Arguments:
+  <range>    Commits to rewrite: <base>..<branch>, <base>.. or <base>
+             (e.g. main~20..main or HEAD~5). The range must end at HEAD
+             or a local branch, which is moved to the result.
+  <duration> Signed offset in Go duration syntax: +2h, -90m, -1h30m,
+             +48h for two days
+
+Flags:
+  --dates=<which>  Dates to shift: author, committer or both (default)
+  --dry-run        Show the old and new dates without rewriting
+  --force          Rewrite commits that are on a remote-tracking branch
+  --update-branches
+                   Also rewrite the other local branches that contain
+                   rewritten commits
+  --resign         Sign the rewritten commits with the configured key
+                   (user.signingkey, gpg.format)
+  --chronology-basis=<basis>
+                   Date compared with the parents outside the range:
+                   author, committer or max (default)
+
+Description:
+  Every selected date in the range moves by the same amount, so the
+  gaps between commits are kept, and so are their zone offsets. A
+  backward shift is refused when the first rewritten commits would no
+  longer follow their parents outside the range; the error tells the
+  largest shift allowed.
+
+` + rewriteBehaviorHelp + `
+Examples:
+  gitcommit shift main~20..main -2h --dry-run
+  gitcommit shift HEAD~5 +1h30m
+  gitcommit shift HEAD~5 -- -24h --dates=committer
+`
+}
+
+// SpreadHelpText returns the help text for the spread command.
+func SpreadHelpText() string {
+	return `gitcommit spread - Spread a range of commits across a time window
+
+Usage:
+  gitcommit spread [flags] <range> --from <date> --to <date>
+
+Arguments:
+  <range>    Commits to rewrite: <base>..<branch>, <base>.. or <base>
+             (e.g. HEAD~12). The range must end at HEAD or a local
+             branch, which is moved to the result.
+
+Flags:
+  --from=<date>, --to=<date>
+                   The window, both ends included, as YYYY-MM-DD HH:MM:SS
+  --distribution=<how>
+                   even (default): evenly spaced, the first commit on
+                   --from and the last on --to
+                   working-hours: evenly spaced over working hours only
+                   random: drawn at random; the seed is printed
+  --seed=<n>       Seed of the random distribution, to draw the same dates again
+  --dates=<which>  Dates to rewrite: author, committer or both (default)
+  --dry-run        Show the old and new dates without rewriting
+  --force          Rewrite commits that are on a remote-tracking branch
+  --update-branches
+                   Also rewrite the other local branches that contain
+                   rewritten commits
+  --resign         Sign the rewritten commits with the configured key
+                   (user.signingkey, gpg.format)
+  --chronology-basis=<basis>
+                   Date compared with the parents outside the range:
+                   author, committer or max (default)
+
+Calendar policies (git config):
+  gitcommit.workingHours  Working hours, e.g. 09:00-17:00 (the default)
+  gitcommit.workingDays   Working days, e.g. mon-fri (the default) or sun-thu
+  gitcommit.holiday       A day off, as YYYY-MM-DD; repeat with git config --add
+
+  Holidays are skipped by every distribution. Working hours and days
+  apply to the working-hours distribution, and to every distribution
+  once either is configured.
+
+Description:
+  The commits keep their order and get strictly increasing dates, one
+  second apart at least.
+
+` + rewriteBehaviorHelp + `
+Examples:
+  gitcommit spread HEAD~12 --from "2025-01-13 09:00:00" --to "2025-01-17 17:00:00"
+  gitcommit spread HEAD~12 --from "2025-01-13 00:00:00" --to "2025-01-19 23:59:59" --distribution=working-hours
+  gitcommit spread HEAD~12 --from "2025-01-13 09:00:00" --to "2025-01-17 17:00:00" --distribution=random --seed=7
+  git config --add gitcommit.holiday 2025-01-15
+`
+}
+
+// EditDatesHelpText returns the help text for the edit-dates command.
+func EditDatesHelpText() string {
+	return `gitcommit edit-dates - Edit the dates of a range of commits in the editor
+
+Usage:
+  gitcommit edit-dates [flags] <range>
+
+Arguments:
+  <range>    Commits to rewrite: <base>..<branch>, <base>.. or <base>
+             (e.g. HEAD~5). The range must end at HEAD or a local
+             branch, which is moved to the result.
+
+Flags:
+  --dry-run        Show the old and new dates without rewriting
+  --force          Rewrite commits that are on a remote-tracking branch
+  --update-branches
+                   Also rewrite the other local branches that contain
+                   rewritten commits
+  --resign         Sign the rewritten commits with the configured key
+                   (user.signingkey, gpg.format)
+  --chronology-basis=<basis>
+                   Date compared with the parents: author, committer or
+                   max (default)
+
+Description:
+  Like git rebase -i, the editor opens on a todo list with one line per
+  commit, oldest first:
+
+    <commit> <author-date> <committer-date> <subject>
+
+  Dates are ISO 8601 with their zone offset (2025-01-16T09:30:00+01:00),
+  so the offset can be edited too. Only the dates are read: lines may
+  not be added, removed or reordered, and the subject is a reminder.
+
+  Lines that cannot be read send you back to the editor with the errors
+  at the top; saving the list unchanged gives up. The whole list is then
+  checked, and every commit dated before a parent is reported by its
+  line number before anything is rewritten. Removing every line aborts.
+
+  The editor is the one git uses: GIT_EDITOR, core.editor, VISUAL or
+  EDITOR.
+
+` + rewriteBehaviorHelp + `
+Examples:
+  gitcommit edit-dates HEAD~5
+  gitcommit edit-dates main~10..main --dry-run
+`
+}
+
+// TZNormalizeHelpText returns the help text for the tz-normalize command.
+func TZNormalizeHelpText() string {
+	return `gitcommit tz-normalize - Rewrite the zone offsets of a range of commits
+
+Usage:
+  gitcommit tz-normalize [flags] <range> (--to <zone> | --map <file>)
+
+Arguments:
+  <range>    Commits to rewrite: <base>..<branch>, <base>.. or <base>
+             (e.g. main~50..main). The range must end at HEAD or a local
+             branch, which is moved to the result.
+
+Flags:
+  --to=<zone>      Zone of the authors and committers not in the map
+  --map=<file>     Zone of each author and committer, one per line:
+                     jane@example.com Europe/Paris
+                     John Roe <john@example.com> -0500
+                   Blank lines and lines starting with # are ignored.
+                   The default is the file named by git config
+                   gitcommit.zoneMap.
+  --dates=<which>  Dates to normalize: author, committer or both (default)
+  --dry-run        Report the commits that would change, without rewriting
+  --force          Rewrite commits that are on a remote-tracking branch
+  --update-branches
+                   Also rewrite the other local branches that contain
+                   rewritten commits
+  --resign         Sign the rewritten commits with the configured key
+                   (user.signingkey, gpg.format)
+
+Zones:
+  A fixed offset (+0100, -05:30), UTC, or an IANA location such as
+  Europe/Paris, whose offset follows daylight saving at each date.
+
+Description:
+  Only the recorded offsets change: every date keeps its instant, so
+  the chronology of the history is untouched. The author date takes the
+  zone of the author, the committer date that of the committer; people
+  neither mapped nor covered by --to keep their offsets.
+
+` + rewriteBehaviorHelp + `
+Examples:
+  gitcommit tz-normalize main~50..main --to Europe/Paris --dry-run
+  gitcommit tz-normalize main~50..main --map zones.txt --to UTC
+  git config gitcommit.zoneMap ~/.config/gitcommit/zones.txt
+`
+}
+
+// UndoHelpText returns the help text for the undo command.
+func UndoHelpText() string {
+	return `gitcommit undo - Restore the refs saved before a gitcommit command
+
+Usage:
+  gitcommit undo [--soft] [<id>]
+  gitcommit undo --list
+  gitcommit undo --prune
+
+Arguments:
+  <id>       Backup to restore, or the start of its ID (default: the
+             latest backup)
+
+Flags:
+  --list     List the backups, newest first, with the refs they saved
+  --prune    Delete the backups past gitcommit.backupMaxAge or
+             gitcommit.backupMaxCount
+  --soft     Move the current branch without touching the index and
+             working tree
+
+Description:
+  Every gitcommit command that moves a ref (commits, merge, pick, tag,
+  redate, shift, spread, edit-dates and tz-normalize) first saves the
+  previous value of the ref under
+  refs/gitcommit/backup/<id>/<ref>, where <id> is the UTC time of the
+  backup, as a tag object whose message names the command that made
+  it. A ref that did not exist, such as a new tag, is deleted when
+  restored.
+
+  The current branch is restored with git reset --keep, which refuses
+  to overwrite local changes; other refs are only moved. The values
+  undo replaces are backed up too, so running undo again reverts it.
+
+Pruning:
+  After each backup, backups older than gitcommit.backupMaxAge (default
+  90d; a number of days or a duration such as 36h) or beyond the newest
+  gitcommit.backupMaxCount (default 100) are deleted. 0 disables either
+  limit.
+
+Examples:
+  gitcommit undo
+  gitcommit undo --list
+  gitcommit undo 20250206T1800
+  git config gitcommit.backupMaxCount 20
+`
+}
+
+// LogHelpText returns the help text for the log command.
+func LogHelpText() string {
+	return `gitcommit log - Show the journal of gitcommit operations
+
+Usage:
+  gitcommit log [-n <count>] [--operation <name>] [--json]
+
+Flags:
+  -n <count>          Show only the newest <count> entries
+  --operation=<name>  Show only one operation: commit, amend, merge,
+                      pick, tag, redate, shift, spread, edit-dates,
+                      tz-normalize or undo
+  --json              Print the entries as stored, one JSON object per
+                      line, for scripts
+
+Description:
+  Every gitcommit operation that moves a ref appends an entry to the
+  journal, $GIT_DIR/gitcommit/journal.jsonl, one JSON object per line.
+  Entries are never rewritten. Each one records:
+  - the time, the operation and the range it applied to
+  - the date exactly as given, and the date it resolved to
+  - the refs moved, with their old and new object IDs
+  - the commits written, with the commits they replace and their dates
+  - the committer identity and the gitcommit version
+  - the backup of the refs, which gitcommit undo restores
+
+  The journal stays in this clone: it is neither pushed nor fetched.
+  Entries are shown newest first.
+
+Examples:
+  gitcommit log
+  gitcommit log -n 5 --operation redate
+  gitcommit log --json | jq -r 'select(.operation == "commit") | .inputDate'
+`
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// resolveIdentities determines the author and committer of the commit.
+// Overrides given with --author and --committer are checked against .mailmap
+// and the user.* configuration; otherwise the identities come from the user.*
+// configuration, or, for the author of an amended or cherry-picked commit,
+// from the original commit.
+func (a *App) resolveIdentities(request *CommitRequest) error {
+	// git commit keeps the original author when amending or cherry-picking
+	originalAuthor := ""
+	switch {
+	case a.config.Amend:
+		originalAuthor = "HEAD"
+	case request.Operation == git.OperationCherryPick:
+		originalAuthor = request.Operation.Head()
+	}
+
+	author, err := resolveIdentity(a.config.Author, git.AuthorIdentVar, originalAuthor)
+	if err != nil {
+		return err
+	}
+
+	committer, err := resolveIdentity(a.config.Committer, git.CommitterIdentVar, "")
+	if err != nil {
+		return err
+	}
+
+	request.Author = author
+	request.Committer = committer
+	slog.Debug("Identities resolved", "author", author, "committer", committer)
+
+	if a.config.Author != "" {
+		request.IdentityWarnings = append(request.IdentityWarnings, checkIdentity("--author", author)...)
+	}
+	if a.config.Committer != "" {
+		request.IdentityWarnings = append(request.IdentityWarnings, checkIdentity("--committer", committer)...)
+	}
+	return nil
+}
+
+// resolveIdentity returns the identity given by override, else the author of
+// the commit originalRev when set, else the default for identVar.
+func resolveIdentity(override, identVar, originalRev string) (git.Identity, error) {
+	if override != "" {
+		identity, err := git.ParseIdentity(override)
+		if err != nil {
+			return git.Identity{}, NewInvalidIdentityError(identityFlag(identVar), override)
+		}
+		return identity, nil
+	}
+
+	if originalRev != "" {
+		identity, err := git.GetCommitAuthor(originalRev)
+		if err != nil {
+			return git.Identity{}, NewGitCommandError(err.Error())
+		}
+		return identity, nil
+	}
+
+	identity, err := git.GetDefaultIdentity(identVar)
+	if err != nil {
+		slog.Error("Identity not configured", "var", identVar, "error", err)
+		return git.Identity{}, NewUnknownIdentityError(err.Error())
+	}
+	return identity, nil
+}
+
+// checkIdentity describes what is suspicious about the identity given with
+// flag: .mailmap mapping it to another identity, which usually means a stale
+// or alternative address, or a mismatch with the configured user.* identity.
+// A committer should be the configured identity; an author may be anyone,
+// but not the configured name with another email, or the reverse.
+func checkIdentity(flag string, identity git.Identity) []string {
+	var warnings []string
+
+	canonical, err := git.MapIdentity(identity)
+	switch {
+	case err != nil:
+		slog.Debug("Could not check identity against .mailmap", "identity", identity, "error", err)
+	case canonical != identity:
+		slog.Warn("Identity is mapped to another identity by .mailmap",
+			"given", identity.String(),
+			"canonical", canonical.String())
+		warnings = append(warnings, fmt.Sprintf("%s %s is %s in .mailmap", flag, identity, canonical))
+	}
+
+	configured, err := git.GetDefaultIdentity(git.CommitterIdentVar)
+	if err != nil {
+		slog.Debug("Could not check identity against the configured one", "identity", identity, "error", err)
+		return warnings
+	}
+	sameName := identity.Name == configured.Name
+	sameEmail := strings.EqualFold(identity.Email, configured.Email)
+	if sameName != sameEmail || (flag == "--committer" && !sameName) {
+		slog.Warn("Identity differs from the configured identity",
+			"given", identity.String(),
+			"configured", configured.String())
+		warnings = append(warnings, fmt.Sprintf("%s %s differs from your configured identity %s", flag, identity, configured))
+	}
+	return warnings
+}
+
+// identityFlag returns the command-line flag that overrides identVar.
+func identityFlag(identVar string) string {
+	if identVar == git.AuthorIdentVar {
+		return "--author"
+	}
+	return "--committer"
+}
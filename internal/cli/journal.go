@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// logUsage is the argument synopsis of the log command.
+const logUsage = "[-n <count>] [--operation <name>] [--json]"
+
+// LogOptions selects the journal entries shown by gitcommit log.
+type LogOptions struct {
+	// Count limits the output to the newest entries; 0 shows them all.
+	Count int
+
+	// Operation keeps only the entries of this operation, such as "redate".
+	Operation string
+
+	// JSON prints the entries as they are stored, one JSON object per line.
+	JSON bool
+}
+
+// RunLog implements "gitcommit log".
+func RunLog(args []string) error {
+	config := NewConfig("")
+	options := LogOptions{}
+
+	flags := flag.NewFlagSet("log", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, LogHelpText()) }
+	flags.IntVar(&options.Count, "n", 0, "Show only the newest <count> entries")
+	flags.StringVar(&options.Operation, "operation", "", "Show only the entries of this operation")
+	flags.BoolVar(&options.JSON, "json", false, "Print the entries as stored, one JSON object per line")
+	positional := ParseInterspersed(flags, args)
+
+	if len(positional) != 0 {
+		return NewCommandArgumentsError("log", logUsage, len(positional))
+	}
+	if options.Count < 0 {
+		return NewInvalidPlanError("log", fmt.Sprintf("-n takes a number of entries, got %d.", options.Count))
+	}
+
+	return NewApp(config).Log(options)
+}
+
+// Log prints the journal of the repository, newest entry first.
+func (a *App) Log(options LogOptions) error {
+	if !git.IsGitRepository() {
+		slog.Error("Not in a Git repository")
+		return NewNoRepositoryError()
+	}
+
+	entries, invalid, err := git.ReadJournal()
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+	if len(invalid) > 0 {
+		slog.Warn("Skipped unreadable journal lines", "file", git.JournalPath, "lines", invalid)
+	}
+
+	var shown []git.JournalEntry
+	for _, entry := range slices.Backward(entries) {
+		if options.Operation != "" && entry.Operation != options.Operation {
+			continue
+		}
+		if options.Count > 0 && len(shown) == options.Count {
+			break
+		}
+		shown = append(shown, entry)
+	}
+
+	if options.JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		for _, entry := range shown {
+			if err := encoder.Encode(entry); err != nil {
+				return NewGitCommandError(err.Error())
+			}
+		}
+		return nil
+	}
+	fmt.Println(FormatJournalMessage(shown))
+	return nil
+}
+
+// recordJournal completes entry with the time, the version of gitcommit,
+// the default committer identity unless entry already names one, and the
+// refs saved in backup that moved with their new values, then appends it to the
+// journal. The operation already happened, so a failure is only logged.
+func (a *App) recordJournal(entry git.JournalEntry, backup git.Backup) {
+	entry.Time = time.Now()
+	entry.Version = a.config.Version
+	entry.Backup = backup.ID
+	for _, saved := range backup.Refs {
+		if current := git.ResolveRef(saved.Name); current != saved.Hash {
+			entry.Refs = append(entry.Refs, git.JournalRef{Name: saved.Name, Old: saved.Hash, New: current})
+		}
+	}
+
+	if entry.Identity == "" {
+		identity, err := git.GetDefaultIdentity(git.CommitterIdentVar)
+		if err != nil {
+			slog.Warn("Could not read the identity for the journal", "error", err)
+		} else {
+			entry.Identity = identity.String()
+		}
+	}
+
+	if err := git.AppendJournal(entry); err != nil {
+		slog.Warn("Could not record the operation in the journal", "error", err)
+		return
+	}
+	slog.Debug("Operation recorded in the journal", "operation", entry.Operation)
+}
+
+// journalCommitOf describes the commit rev, written in place of old if not
+// empty, for the journal. It reports false when the commit cannot be read.
+func journalCommitOf(rev, old string) (git.JournalCommit, bool) {
+	dates, err := git.GetCommitDates(rev)
+	if err != nil {
+		slog.Warn("Could not read the commit for the journal", "rev", rev, "error", err)
+		return git.JournalCommit{}, false
+	}
+	return git.JournalCommit{
+		Old: old, New: dates.Hash, AuthorDate: dates.AuthorDate, CommitterDate: dates.CommitterDate,
+	}, true
+}
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// mergeUsage is the argument synopsis of the merge command.
+const mergeUsage = "[flags] <date> <branch>..."
+
+// RunMerge implements "gitcommit merge <date> <branch>...".
+func RunMerge(args []string) error {
+	config := NewConfig("")
+	var noFastForward, fastForwardOnly, squash bool
+
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, MergeHelpText()) }
+	flags.BoolVar(&noFastForward, "no-ff", false, "Always create a merge commit")
+	flags.BoolVar(&fastForwardOnly, "ff-only", false, "Refuse to merge unless it is a fast-forward")
+	flags.BoolVar(&squash, "squash", false, "Squash the merged changes into a single ordinary commit")
+	flags.Var(&config.Messages, "m", "Merge commit message paragraph (repeatable)")
+	flags.StringVar(&config.ChronologyBasis, "chronology-basis", config.ChronologyBasis,
+		"Date of the parents to check against: author, committer or max")
+	flags.Var(&config.Sign, "S", "Sign the commit, optionally with the given key (-S<keyid>)")
+	flags.Var(&config.Sign, "gpg-sign", "Sign the commit, optionally with the given key (--gpg-sign=<keyid>)")
+	flags.BoolVar(&config.NoSign, "no-gpg-sign", false, "Do not sign the commit, even if commit.gpgsign is set")
+	flags.BoolVar(&config.ShowGitOutput, "show-git-output", false, "Echo git's own output, including on failure")
+	_ = flags.Parse(NormalizeSignFlag(args))
+
+	if flags.NArg() < RequiredArguments {
+		return NewCommandArgumentsError("merge", mergeUsage, flags.NArg())
+	}
+	config.Args = flags.Args()
+
+	mode, err := mergeMode(noFastForward, fastForwardOnly, squash)
+	if err != nil {
+		return err
+	}
+	if _, err := datetime.ParseChronologyBasis(config.ChronologyBasis); err != nil {
+		return NewInvalidChronologyBasisError(config.ChronologyBasis)
+	}
+	if config.Sign.Enabled && config.NoSign {
+		return NewConflictingOptionsError("-S", "--no-gpg-sign")
+	}
+
+	return NewApp(config).Merge(config.Args[0], config.Args[1:], mode)
+}
+
+// mergeMode returns the merge mode selected by the mutually exclusive flags.
+func mergeMode(noFastForward, fastForwardOnly, squash bool) (git.MergeMode, error) {
+	var selected []string
+	mode := git.MergeFastForward
+	for _, option := range []struct {
+		set  bool
+		mode git.MergeMode
+	}{
+		{noFastForward, git.MergeNoFastForward},
+		{fastForwardOnly, git.MergeFastForwardOnly},
+		{squash, git.MergeSquash},
+	} {
+		if option.set {
+			selected = append(selected, "--"+string(option.mode))
+			mode = option.mode
+		}
+	}
+
+	if len(selected) > 1 {
+		return "", NewConflictingOptionsError(selected[0], selected[1])
+	}
+	return mode, nil
+}
+
+// Merge merges branches into HEAD, dating any commit it creates with dateStr,
+// which must follow HEAD and the tip of every merged branch.
+func (a *App) Merge(dateStr string, branches []string, mode git.MergeMode) error {
+	slog.Info("Processing merge request", "date", dateStr, "branches", branches, "mode", mode)
+
+	if !git.IsGitRepository() {
+		slog.Error("Not in a Git repository")
+		return NewNoRepositoryError()
+	}
+
+	parsedDate, err := a.parseDate(dateStr)
+	if err != nil {
+		return err
+	}
+
+	upToDate, fastForward, err := mergeOutcome(branches)
+	if err != nil {
+		return err
+	}
+	if upToDate {
+		fmt.Println(FormatMergeUpToDateMessage(branches))
+		return nil
+	}
+	if fastForward && mode == git.MergeFastForward {
+		slog.Warn("Merge is a fast-forward: no commit is created, so the date is not used",
+			"hint", "use --no-ff to record a dated merge commit")
+	}
+
+	// The commit must follow HEAD and every branch being merged
+	createsCommit := !fastForward || mode == git.MergeNoFastForward || mode == git.MergeSquash
+	if createsCommit {
+		a.config.Against = append(a.config.Against, branches...)
+		floor, err := a.findChronologyFloor()
+		if err != nil {
+			return err
+		}
+		if err := a.validateChronology(parsedDate, floor); err != nil {
+			return err
+		}
+	}
+
+	gitFormattedDate := datetime.FormatForGit(parsedDate)
+	options := git.MergeOptions{
+		Mode:       mode,
+		Messages:   a.config.Messages,
+		Sign:       a.config.Sign.Enabled,
+		SignKey:    a.config.Sign.KeyID,
+		NoSign:     a.config.NoSign,
+		ShowOutput: a.config.ShowGitOutput,
+	}
+	head, err := headRef()
+	if err != nil {
+		return err
+	}
+	backup, err := backupRefs("gitcommit merge: "+strings.Join(branches, " "), head)
+	if err != nil {
+		return err
+	}
+	if err := git.ExecuteMerge(gitFormattedDate, branches, options); err != nil {
+		slog.Error("Git merge failed", "error", err)
+		discardUnusedBackup(backup)
+		if errors.Is(err, git.ErrMergeConflict) {
+			conflicts, _ := git.GetConflictedPaths()
+			return NewMergeConflictError(branches, conflicts, dateStr, mode == git.MergeSquash)
+		}
+		return NewMergeFailedError(err.Error())
+	}
+
+	if mode == git.MergeSquash {
+		if err := a.commitSquash(gitFormattedDate); err != nil {
+			discardUnusedBackup(backup)
+			return err
+		}
+	}
+
+	entry := git.JournalEntry{Operation: "merge", InputDate: dateStr, Date: parsedDate}
+	if createsCommit {
+		if commit, ok := journalCommitOf("HEAD", ""); ok {
+			entry.Commits = []git.JournalCommit{commit}
+		}
+	}
+	a.recordJournal(entry, backup)
+
+	fmt.Println(FormatMergeSuccessMessage(branches, gitFormattedDate, mode, fastForward))
+	slog.Info("Merge completed successfully")
+	return nil
+}
+
+// mergeOutcome reports whether every branch is already merged into HEAD, and
+// whether merging would be a fast-forward (a single branch descending from HEAD).
+func mergeOutcome(branches []string) (bool, bool, error) {
+	upToDate := true
+	for _, branch := range branches {
+		if _, err := git.ResolveCommit(branch); err != nil {
+			return false, false, NewUnknownRefError(branch)
+		}
+		if !git.HasCommits() {
+			upToDate = false
+			continue
+		}
+		merged, err := git.IsAncestor(branch, "HEAD")
+		if err != nil {
+			return false, false, NewGitCommandError(err.Error())
+		}
+		upToDate = upToDate && merged
+	}
+	if upToDate || len(branches) > 1 {
+		return upToDate, false, nil
+	}
+
+	if !git.HasCommits() {
+		return false, true, nil
+	}
+	fastForward, err := git.IsAncestor("HEAD", branches[0])
+	if err != nil {
+		return false, false, NewGitCommandError(err.Error())
+	}
+	return false, fastForward, nil
+}
+
+// commitSquash commits the changes staged by git merge --squash with the
+// -m message or the one git prepared in SQUASH_MSG.
+func (a *App) commitSquash(gitFormattedDate string) error {
+	message := strings.Join(a.config.Messages, "\n\n")
+	if message == "" {
+		var err error
+		if message, err = git.GetSquashMessage(); err != nil {
+			return NewGitCommandError(err.Error())
+		}
+	}
+	if strings.TrimSpace(message) == "" {
+		return NewEmptyMessageError("SQUASH_MSG")
+	}
+
+	options := git.CommitOptions{
+		Sign:       a.config.Sign.Enabled,
+		SignKey:    a.config.Sign.KeyID,
+		NoSign:     a.config.NoSign,
+		ShowOutput: a.config.ShowGitOutput,
+	}
+	if err := git.ExecuteCommit(gitFormattedDate, message, options); err != nil {
+		slog.Error("Git commit failed", "error", err)
+		return commitFailureError(err, "")
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+const (
+	// StdinMessageFile is the -F value that reads the message from standard input.
+	StdinMessageFile = "-"
+
+	// editMessageFile is the file, inside the git directory, opened in the editor.
+	editMessageFile = "GITCOMMIT_EDITMSG"
+
+	// editTemplateHelp explains the editor template, in git's wording.
+	editTemplateHelp = "Please enter the commit message for your changes. Lines starting\n" +
+		"with '#' will be ignored, and an empty message aborts the commit."
+)
+
+// resolveMessage determines the commit message from the positional argument,
+// -m paragraphs, -F file or standard input, or the message git prepared for
+// a merge, cherry-pick or revert, then opens the editor if --edit was given.
+// An empty result means HEAD's message is kept, which is only possible with
+// --amend; otherwise empty messages are rejected.
+func (a *App) resolveMessage(request *CommitRequest) (string, error) {
+	message, source, err := a.initialMessage(request.Operation)
+	if err != nil {
+		return "", err
+	}
+
+	if a.config.Edit {
+		return a.editMessage(message, request.GitFormattedDate)
+	}
+
+	if source == "" {
+		// Only reachable with --amend: git keeps HEAD's message
+		return "", nil
+	}
+
+	if strings.TrimSpace(message) == "" {
+		slog.Error("Empty commit message", "source", source)
+		return "", NewEmptyMessageError(source)
+	}
+
+	return message, nil
+}
+
+// initialMessage returns the message given on the command line, or prepared
+// by git for operation, and a description of where it came from, or an empty
+// source if there is none.
+func (a *App) initialMessage(operation git.Operation) (string, string, error) {
+	switch {
+	case len(a.config.Messages) > 0:
+		// Like git, each -m is a separate paragraph
+		return strings.Join(a.config.Messages, "\n\n"), "-m", nil
+	case a.config.MessageFile == StdinMessageFile:
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", "", NewMessageFileError(a.config.MessageFile, err.Error())
+		}
+		return string(content), "standard input", nil
+	case a.config.MessageFile != "":
+		content, err := os.ReadFile(a.config.MessageFile)
+		if err != nil {
+			return "", "", NewMessageFileError(a.config.MessageFile, err.Error())
+		}
+		return string(content), a.config.MessageFile, nil
+	case a.config.HasMessageArgument():
+		return a.config.GetMessage(), "<message> argument", nil
+	case operation != git.OperationNone:
+		message, err := git.GetPreparedMessage()
+		if err != nil {
+			return "", "", NewGitCommandError(err.Error())
+		}
+		return message, "MERGE_MSG", nil
+	default:
+		return "", "", nil
+	}
+}
+
+// editMessage opens the editor on a template holding message and a reminder
+// of the chosen date, and returns the edited message without comments.
+func (a *App) editMessage(message, gitFormattedDate string) (string, error) {
+	if message == "" && a.config.Amend {
+		headMessage, err := git.GetCommitMessage("HEAD")
+		if err != nil {
+			slog.Warn("Could not read the message of HEAD", "error", err)
+		}
+		message = headMessage
+	}
+
+	path, err := git.GetGitPath(editMessageFile)
+	if err != nil {
+		return "", NewGitCommandError(err.Error())
+	}
+
+	if err := os.WriteFile(path, []byte(editTemplate(message, gitFormattedDate, a.config.Amend)), 0o600); err != nil {
+		return "", NewEditorError("", err.Error())
+	}
+
+	editor, err := git.GetEditor()
+	if err != nil {
+		return "", NewEditorError("", err.Error())
+	}
+
+	slog.Debug("Opening editor", "editor", editor, "file", path)
+	if err := git.RunEditor(editor, path); err != nil {
+		return "", NewEditorError(editor, err.Error())
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", NewEditorError(editor, err.Error())
+	}
+
+	cleaned, err := git.StripSpace(string(edited), true)
+	if err != nil {
+		return "", NewGitCommandError(err.Error())
+	}
+
+	if strings.TrimSpace(cleaned) == "" {
+		slog.Error("Empty commit message", "source", "editor")
+		return "", NewEmptyMessageError("editor")
+	}
+
+	return cleaned, nil
+}
+
+// editTemplate builds the file presented in the editor.
+func editTemplate(message, gitFormattedDate string, amend bool) string {
+	var b strings.Builder
+
+	b.WriteString(strings.TrimRight(message, "\n"))
+	b.WriteString("\n\n")
+	for _, line := range strings.Split(editTemplateHelp, "\n") {
+		b.WriteString("# " + line + "\n")
+	}
+	b.WriteString("#\n")
+	if amend {
+		b.WriteString("# Amending the last commit.\n")
+	}
+	fmt.Fprintf(&b, "# Commit date: %s\n", gitFormattedDate)
+
+	return b.String()
+}
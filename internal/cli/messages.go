@@ -1,6 +1,383 @@
 package cli
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
 // FormatSuccessMessage formats a success message with a checkmark.
 func FormatSuccessMessage(gitFormattedDate string) string {
 	return "✓ Commit created with date: " + gitFormattedDate
 }
+
+// FormatAmendSuccessMessage formats a success message for an amended commit.
+func FormatAmendSuccessMessage(gitFormattedDate string) string {
+	return "✓ Commit amended with date: " + gitFormattedDate
+}
+
+// FormatIdentityMessage formats the author and committer recorded in a commit.
+func FormatIdentityMessage(author, committer git.Identity) string {
+	return "  Author:    " + author.String() + "\n  Committer: " + committer.String()
+}
+
+// FormatDetachedHeadMessage warns that no branch points to a commit made on a detached HEAD.
+func FormatDetachedHeadMessage() string {
+	return FormatWarningMessage("HEAD is detached, so no branch points to this commit; keep it with: git switch -c <branch>")
+}
+
+// FormatWarningMessage formats a warning about a commit, below its identities.
+func FormatWarningMessage(warning string) string {
+	return "  Warning:   " + warning
+}
+
+// FormatSignatureMessage formats the verification result of a signed commit.
+func FormatSignatureMessage(signature git.Signature, format string) string {
+	message := "  Signature: " + signature.Describe()
+	if signature.Signer != "" {
+		message += " from " + signature.Signer
+	}
+	if signature.Key != "" {
+		message += " (" + format + " key " + signature.Key + ")"
+	}
+	return message
+}
+
+// FormatProvenanceRecordedMessage tells where the provenance of a commit was recorded.
+func FormatProvenanceRecordedMessage(mode string) string {
+	if mode == ProvenanceNote {
+		return "  Provenance: recorded in a note under " + ProvenanceNotesRef
+	}
+	return "  Provenance: recorded in message trailers"
+}
+
+// FormatProvenanceMessage formats the provenance of a commit next to the dates it claims.
+func FormatProvenanceMessage(shortHash, source string, provenance Provenance, dates git.CommitDates) string {
+	return fmt.Sprintf(
+		"Commit:         %s\n"+
+			"Recorded in:    %s\n"+
+			"Created at:     %s\n"+
+			"Input date:     %s\n"+
+			"Tool version:   %s\n"+
+			"Author date:    %s\n"+
+			"Committer date: %s",
+		shortHash,
+		source,
+		provenance.CreatedAt.Format(time.RFC3339),
+		provenance.InputDate,
+		provenance.Version,
+		dates.AuthorDate.Format(time.RFC3339),
+		dates.CommitterDate.Format(time.RFC3339),
+	)
+}
+
+// FormatOperationMessage tells which operation the commit concluded and,
+// for a multi-commit cherry-pick or revert, how to carry on.
+func FormatOperationMessage(operation git.Operation, sequencer bool) string {
+	message := "  Concluded the " + string(operation) + " in progress"
+	if sequencer {
+		message += "\n  Carry on with: git " + string(operation) + " --continue"
+	}
+	return message
+}
+
+// FormatMergeSuccessMessage formats the result of gitcommit merge.
+func FormatMergeSuccessMessage(branches []string, gitFormattedDate string, mode git.MergeMode, fastForward bool) string {
+	merged := strings.Join(branches, ", ")
+	switch {
+	case mode == git.MergeSquash:
+		return "✓ Squashed " + merged + " into a commit with date: " + gitFormattedDate
+	case fastForward && mode != git.MergeNoFastForward:
+		return "✓ Fast-forwarded to " + merged + "; no commit was created, so the date was not used"
+	default:
+		return "✓ Merged " + merged + " with date: " + gitFormattedDate
+	}
+}
+
+// FormatMergeUpToDateMessage formats the result of merging branches that are already merged.
+func FormatMergeUpToDateMessage(branches []string) string {
+	return "✓ Already up to date with " + strings.Join(branches, ", ") + "; no commit was created"
+}
+
+// FormatTagSuccessMessage formats the result of gitcommit tag.
+func FormatTagSuccessMessage(name, hash, gitFormattedDate string) string {
+	return fmt.Sprintf("✓ Tag %s created on %s with date: %s", name, git.ShortHash(hash), gitFormattedDate)
+}
+
+// PlanLine describes one commit of a planned operation and its old and new date.
+type PlanLine struct {
+	// Hash is the full hash of the commit.
+	Hash string
+
+	// Subject is the first line of the commit message.
+	Subject string
+
+	// Before is the date the commit has now.
+	Before time.Time
+
+	// After is the date the commit will get.
+	After time.Time
+}
+
+// formatPlanTable formats plan lines as a table of old and new dates.
+func formatPlanTable(lines []PlanLine) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %-*s  %-31s  %-31s  %s", git.ShortHashLength, "Commit", "Original date", "New date", "Subject")
+	for _, line := range lines {
+		fmt.Fprintf(&b, "\n  %-*s  %-31s  %-31s  %s",
+			git.ShortHashLength, git.ShortHash(line.Hash),
+			datetime.FormatForGit(line.Before), datetime.FormatForGit(line.After), line.Subject)
+	}
+	return b.String()
+}
+
+// FormatPickPlanMessage formats the dates planned by gitcommit pick --dry-run.
+func FormatPickPlanMessage(lines []PlanLine) string {
+	return fmt.Sprintf("Would pick %d commit(s):\n%s", len(lines), formatPlanTable(lines))
+}
+
+// FormatPickStepMessage formats the result of picking one commit.
+func FormatPickStepMessage(hash, subject, gitFormattedDate string) string {
+	return fmt.Sprintf("✓ Picked %s with date: %s  %s", git.ShortHash(hash), gitFormattedDate, subject)
+}
+
+// FormatRewritePlanMessage formats the dates planned by a history rewrite with --dry-run.
+func FormatRewritePlanMessage(command string, lines []PlanLine) string {
+	return fmt.Sprintf("gitcommit %s would give %d commit(s) these dates (dry run, nothing rewritten):\n%s",
+		command, len(lines), formatPlanTable(lines))
+}
+
+// FormatRewriteUnchangedMessage formats the result of a history rewrite that changes nothing.
+func FormatRewriteUnchangedMessage(command string) string {
+	return "✓ Nothing to rewrite: gitcommit " + command + " leaves every date as it is"
+}
+
+// FormatRewriteSuccessMessage formats the result of a history rewrite, with
+// the tags and branches that followed it.
+func FormatRewriteSuccessMessage(command string, count int, ref, oldTip, newTip string, following []git.RefUpdate) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✓ Rewrote %d commit(s) on %s with gitcommit %s: %s → %s\n",
+		count, ref, command, git.ShortHash(oldTip), git.ShortHash(newTip))
+	for _, update := range following {
+		fmt.Fprintf(&b, "  Updated %s: %s → %s\n", describeRef(update.Name), git.ShortHash(update.Old), git.ShortHash(update.New))
+	}
+	b.WriteString("  To restore the previous history, run: gitcommit undo")
+	return b.String()
+}
+
+// FormatResignedMessage formats the result of a history rewrite with
+// --resign, which signed commits commits and tags tags with a key of format.
+func FormatResignedMessage(commits, tags int, format string) string {
+	if tags > 0 {
+		return fmt.Sprintf("✓ Signed %d rewritten commit(s) and %d recreated tag(s) with your %s key", commits, tags, format)
+	}
+	return fmt.Sprintf("✓ Signed %d rewritten commit(s) with your %s key", commits, format)
+}
+
+// FormatDroppedSignaturesMessage formats the signatures a history rewrite
+// could not keep.
+func FormatDroppedSignaturesMessage(loss signatureLoss) string {
+	var lines []string
+	if len(loss.commits) > 0 {
+		lines = append(lines, fmt.Sprintf("Signatures dropped from %d commit(s) that were signed: %s",
+			len(loss.commits), formatShortHashes(loss.commits)))
+	}
+	if len(loss.tags) > 0 {
+		lines = append(lines, fmt.Sprintf("Signatures dropped from %d tag(s) that were signed: %s",
+			len(loss.tags), strings.Join(loss.tags, ", ")))
+	}
+	if len(loss.mergeTags) > 0 {
+		lines = append(lines, fmt.Sprintf("Merged tag signatures dropped from %d merge(s): %s",
+			len(loss.mergeTags), strings.Join(loss.mergeTags, ", ")))
+	}
+	if len(loss.commits) > 0 || len(loss.tags) > 0 {
+		lines = append(lines, "  To keep them signed, run gitcommit undo, then the same command with --resign")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatDroppedSignaturesPlanMessage formats the signatures a history
+// rewrite would not keep.
+func FormatDroppedSignaturesPlanMessage(loss signatureLoss) string {
+	var lines []string
+	if len(loss.commits) > 0 {
+		lines = append(lines, fmt.Sprintf("It would drop the signatures of %d commit(s): %s",
+			len(loss.commits), formatShortHashes(loss.commits)))
+	}
+	if len(loss.tags) > 0 {
+		lines = append(lines, fmt.Sprintf("It would drop the signatures of %d tag(s): %s",
+			len(loss.tags), strings.Join(loss.tags, ", ")))
+	}
+	if len(loss.mergeTags) > 0 {
+		lines = append(lines, fmt.Sprintf("It would drop the merged tag signatures of %d merge(s): %s",
+			len(loss.mergeTags), strings.Join(loss.mergeTags, ", ")))
+	}
+	if len(loss.commits) > 0 || len(loss.tags) > 0 {
+		lines = append(lines, "  To sign the rewritten commits and tags with your key, add --resign")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatShortHashes lists commits by their abbreviated hashes.
+func formatShortHashes(commits []string) string {
+	short := make([]string, len(commits))
+	for i, commit := range commits {
+		short[i] = git.ShortHash(commit)
+	}
+	return strings.Join(short, ", ")
+}
+
+// FormatFollowingRefsPlanMessage formats the tags and branches a history
+// rewrite would move, and how many commits of those branches it would
+// rewrite on top of the range.
+func FormatFollowingRefsPlanMessage(refs []git.RefTarget, branchCommits int) string {
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = describeRef(ref.Name)
+	}
+	message := "It would also move " + strings.Join(names, ", ")
+	if branchCommits > 0 {
+		message += fmt.Sprintf(", rewriting %d commit(s) of other branches on top of the range", branchCommits)
+	}
+	return message
+}
+
+// FormatSpreadSeedMessage formats the seed of a random spread, so that the
+// same dates can be drawn again.
+func FormatSpreadSeedMessage(seed uint64) string {
+	return fmt.Sprintf("Random seed: %d (pass --seed=%d to draw the same dates again)", seed, seed)
+}
+
+// FormatEditDatesAbortedMessage formats the result of gitcommit edit-dates
+// when the todo list was emptied.
+func FormatEditDatesAbortedMessage() string {
+	return "Nothing to do: the todo list is empty, nothing rewritten"
+}
+
+// FormatZoneReportMessage formats the commits gitcommit tz-normalize --dry-run
+// would change, out of total.
+func FormatZoneReportMessage(changes []ZoneChange, total int) string {
+	if len(changes) == 0 {
+		return fmt.Sprintf("✓ All %d commit(s) already have the requested zone offsets (dry run, nothing rewritten)", total)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "gitcommit tz-normalize would change %d of %d commit(s) (dry run, nothing rewritten):", len(changes), total)
+	for _, change := range changes {
+		fmt.Fprintf(&b, "\n  %-*s  author %-13s  committer %-13s  %s",
+			git.ShortHashLength, git.ShortHash(change.Hash),
+			formatOffsetChange(change.AuthorBefore, change.AuthorAfter),
+			formatOffsetChange(change.CommitterBefore, change.CommitterAfter), change.Subject)
+	}
+	return b.String()
+}
+
+// formatOffsetChange formats a zone offset and the one replacing it, or the
+// offset alone when it is kept.
+func formatOffsetChange(before, after string) string {
+	if before == after {
+		return before
+	}
+	return before + " → " + after
+}
+
+// FormatUndoSuccessMessage formats the refs gitcommit undo restored from backup.
+func FormatUndoSuccessMessage(backup git.Backup, changes []RefChange) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✓ Restored backup %s (%s)", backup.ID, backup.Reason)
+	for _, change := range changes {
+		fmt.Fprintf(&b, "\n  %s: %s → %s", shortRefName(change.Name), formatSavedHash(change.Old), formatSavedHash(change.New))
+	}
+	b.WriteString("\n  The values replaced were backed up too: run gitcommit undo again to restore them")
+	return b.String()
+}
+
+// FormatUndoUnchangedMessage formats the result of gitcommit undo when every
+// ref already has the value saved by backup.
+func FormatUndoUnchangedMessage(backup git.Backup) string {
+	return fmt.Sprintf("✓ Nothing to restore: every ref already matches backup %s (%s)", backup.ID, backup.Reason)
+}
+
+// FormatBackupListMessage formats the backups listed by gitcommit undo --list.
+func FormatBackupListMessage(backups []git.Backup) string {
+	if len(backups) == 0 {
+		return "No backups under " + git.BackupRefPrefix
+	}
+
+	var b strings.Builder
+	b.WriteString("Backups, newest first (restore one with: gitcommit undo <id>):")
+	for _, backup := range backups {
+		fmt.Fprintf(&b, "\n  %s  %s  %s", backup.ID, backup.Created.Local().Format(datetime.InputDateLayout), backup.Reason)
+		for _, saved := range backup.Refs {
+			fmt.Fprintf(&b, "\n      %-*s  %s", git.ShortHashLength, formatSavedHash(saved.Hash), shortRefName(saved.Name))
+		}
+	}
+	return b.String()
+}
+
+// FormatBackupsPrunedMessage formats the result of gitcommit undo --prune.
+func FormatBackupsPrunedMessage(count int) string {
+	return fmt.Sprintf("✓ Pruned %d backup(s) past %s or %s", count, BackupMaxAgeConfigKey, BackupMaxCountConfigKey)
+}
+
+// formatSavedHash formats the value of a ref, "(none)" when it does not exist.
+func formatSavedHash(hash string) string {
+	if hash == "" {
+		return "(none)"
+	}
+	return git.ShortHash(hash)
+}
+
+// FormatJournalMessage formats the journal entries shown by gitcommit log.
+func FormatJournalMessage(entries []git.JournalEntry) string {
+	if len(entries) == 0 {
+		return "No operations recorded in the journal"
+	}
+
+	blocks := make([]string, len(entries))
+	for i, entry := range entries {
+		blocks[i] = formatJournalEntry(entry)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// formatJournalEntry formats one journal entry: what ran, when and by whom,
+// then the refs it moved, the commits it wrote and its backup.
+func formatJournalEntry(entry git.JournalEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s", entry.Time.Local().Format(datetime.InputDateLayout), entry.Operation)
+	if entry.Range != "" {
+		b.WriteString(" " + entry.Range)
+	}
+	if entry.InputDate != "" {
+		b.WriteString(": " + entry.InputDate)
+	}
+	fmt.Fprintf(&b, "\n  By %s with gitcommit %s", entry.Identity, entry.Version)
+	if entry.Author != "" && entry.Author != entry.Identity {
+		b.WriteString("\n  Author:  " + entry.Author)
+	}
+	if !entry.Date.IsZero() {
+		b.WriteString("\n  Date:    " + entry.Date.Format(time.RFC3339))
+	}
+	for _, ref := range entry.Refs {
+		fmt.Fprintf(&b, "\n  Ref:     %s %s → %s", shortRefName(ref.Name), formatSavedHash(ref.Old), formatSavedHash(ref.New))
+	}
+	for _, commit := range entry.Commits {
+		written := git.ShortHash(commit.New)
+		if commit.Old != "" {
+			written = git.ShortHash(commit.Old) + " → " + written
+		}
+		fmt.Fprintf(&b, "\n  Commit:  %s  author %s  committer %s",
+			written, commit.AuthorDate.Format(time.RFC3339), commit.CommitterDate.Format(time.RFC3339))
+	}
+	if entry.Backup != "" {
+		b.WriteString("\n  Backup:  " + entry.Backup)
+	}
+	if entry.Restored != "" {
+		b.WriteString("\n  Restored backup: " + entry.Restored)
+	}
+	return b.String()
+}
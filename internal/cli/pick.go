@@ -0,0 +1,425 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+const (
+	// pickUsage is the argument synopsis of the pick command.
+	pickUsage = "[flags] <date> <rev>... [<date> <rev>...]..."
+
+	// DefaultInterval separates scheduled commits that have no explicit date.
+	DefaultInterval = time.Minute
+
+	// pickMessageFile is the file, inside the git directory, holding the
+	// message of a pick that stopped on conflicts.
+	pickMessageFile = "GITCOMMIT_PICKMSG"
+)
+
+// PickOptions holds the settings of the pick command.
+type PickOptions struct {
+	// Interval separates commits that have no explicit date.
+	Interval time.Duration
+
+	// PreserveGaps keeps the gaps between the original author dates instead of Interval.
+	PreserveGaps bool
+
+	// RecordOrigin appends "(cherry picked from commit ...)" like git cherry-pick -x.
+	RecordOrigin bool
+
+	// DryRun prints the planned dates without picking anything.
+	DryRun bool
+}
+
+// pickStep is one commit to pick and the date it gets.
+type pickStep struct {
+	commit  git.CommitDates
+	subject string
+	date    time.Time
+}
+
+// pickGroup is a run of revisions following an explicit date.
+type pickGroup struct {
+	date time.Time
+	revs []string
+}
+
+// RunPick implements "gitcommit pick <date> <rev>...".
+func RunPick(args []string) error {
+	config := NewConfig("")
+	options := PickOptions{}
+
+	flags := flag.NewFlagSet("pick", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, PickHelpText()) }
+	flags.DurationVar(&options.Interval, "interval", DefaultInterval,
+		"Time between commits without an explicit date (e.g. 90s, 15m, 2h)")
+	flags.BoolVar(&options.PreserveGaps, "preserve-gaps", false,
+		"Keep the gaps between the original author dates")
+	flags.BoolVar(&options.RecordOrigin, "x", false, "Append \"(cherry picked from commit ...)\" to the messages")
+	flags.BoolVar(&options.DryRun, "dry-run", false, "Show the planned dates without picking")
+	flags.StringVar(&config.ChronologyBasis, "chronology-basis", config.ChronologyBasis,
+		"Date of the last commit to check against: author, committer or max")
+	flags.IntVar(&config.ChronologyDepth, "chronology-depth", config.ChronologyDepth,
+		"Generations of parents of HEAD to check against")
+	flags.Var(&config.Against, "against", "Also require dates after the tip of this ref (repeatable)")
+	flags.Var(&config.Sign, "S", "Sign the commits, optionally with the given key (-S<keyid>)")
+	flags.Var(&config.Sign, "gpg-sign", "Sign the commits, optionally with the given key (--gpg-sign=<keyid>)")
+	flags.BoolVar(&config.NoSign, "no-gpg-sign", false, "Do not sign the commits, even if commit.gpgsign is set")
+	flags.BoolVar(&config.ShowGitOutput, "show-git-output", false, "Echo git's own output, including on failure")
+	_ = flags.Parse(NormalizeSignFlag(args))
+
+	if flags.NArg() < RequiredArguments {
+		return NewCommandArgumentsError("pick", pickUsage, flags.NArg())
+	}
+
+	intervalSet := false
+	flags.Visit(func(f *flag.Flag) { intervalSet = intervalSet || f.Name == "interval" })
+	if intervalSet && options.PreserveGaps {
+		return NewConflictingOptionsError("--interval", "--preserve-gaps")
+	}
+	if options.Interval <= 0 {
+		return NewInvalidIntervalError(options.Interval.String())
+	}
+	if _, err := datetime.ParseChronologyBasis(config.ChronologyBasis); err != nil {
+		return NewInvalidChronologyBasisError(config.ChronologyBasis)
+	}
+	if config.ChronologyDepth < 0 {
+		return NewInvalidChronologyDepthError(config.ChronologyDepth)
+	}
+	if config.Sign.Enabled && config.NoSign {
+		return NewConflictingOptionsError("-S", "--no-gpg-sign")
+	}
+
+	return NewApp(config).Pick(flags.Args(), options)
+}
+
+// Pick cherry-picks revisions onto HEAD, one commit each, dated by the
+// explicit dates in args and options. Every date must follow HEAD and the
+// date before it.
+func (a *App) Pick(args []string, options PickOptions) error {
+	slog.Info("Processing pick request", "args", args)
+
+	if !git.IsGitRepository() {
+		slog.Error("Not in a Git repository")
+		return NewNoRepositoryError()
+	}
+
+	groups, err := parsePickArgs(args)
+	if err != nil {
+		return err
+	}
+
+	steps, err := planPick(groups, options)
+	if err != nil {
+		return err
+	}
+
+	if err := a.validatePickChronology(steps); err != nil {
+		return err
+	}
+
+	if options.DryRun {
+		fmt.Println(FormatPickPlanMessage(pickPlanLines(steps)))
+		return nil
+	}
+
+	if err := checkReadyToPick(); err != nil {
+		return err
+	}
+	head, err := headRef()
+	if err != nil {
+		return err
+	}
+	backup, err := backupRefs("gitcommit pick: "+strings.Join(args, " "), head)
+	if err != nil {
+		return err
+	}
+
+	// Commits picked before a conflict are recorded too, but a pick that
+	// created none is not
+	entry := git.JournalEntry{Operation: "pick", InputDate: strings.Join(args, " ")}
+	defer func() {
+		if len(entry.Commits) > 0 {
+			a.recordJournal(entry, backup)
+		}
+	}()
+
+	for i, step := range steps {
+		before := git.ResolveRef("HEAD")
+		if err := a.pickOne(step, options); err != nil {
+			if i > 0 {
+				slog.Info("Commits picked before stopping", "count", i)
+			}
+			if discardUnusedBackup(backup) {
+				backup = git.Backup{}
+			}
+			return pickFailureError(err, steps[i:])
+		}
+		if after := git.ResolveRef("HEAD"); after != before {
+			if commit, ok := journalCommitOf(after, step.commit.Hash); ok {
+				entry.Commits = append(entry.Commits, commit)
+			}
+		}
+	}
+
+	slog.Info("Pick completed successfully", "count", len(steps))
+	return nil
+}
+
+// parsePickArgs splits the arguments into groups, each an explicit date
+// followed by the revisions it starts. The first argument must be a date.
+func parsePickArgs(args []string) ([]pickGroup, error) {
+	var groups []pickGroup
+	for _, arg := range args {
+		date, err := datetime.ParseDate(arg)
+		if err == nil {
+			groups = append(groups, pickGroup{date: date})
+			continue
+		}
+
+		if len(groups) == 0 {
+			return nil, NewInvalidDateFormatError(arg)
+		}
+		groups[len(groups)-1].revs = append(groups[len(groups)-1].revs, arg)
+	}
+
+	for _, group := range groups {
+		if len(group.revs) == 0 {
+			return nil, NewInvalidPlanError("pick",
+				fmt.Sprintf("The date %s is not followed by any revision.", datetime.FormatForGit(group.date)))
+		}
+	}
+	return groups, nil
+}
+
+// planPick resolves the revisions of every group and assigns their dates:
+// the group's date for its first commit, then the interval or original gaps.
+func planPick(groups []pickGroup, options PickOptions) ([]pickStep, error) {
+	var steps []pickStep
+	for _, group := range groups {
+		hashes, err := resolvePickRevisions(group.revs)
+		if err != nil {
+			return nil, err
+		}
+
+		commits, err := git.GetCommitsDates(hashes)
+		if err != nil {
+			return nil, NewGitCommandError(err.Error())
+		}
+
+		originals := make([]time.Time, len(commits))
+		for i, commit := range commits {
+			if len(commit.Parents) > 1 {
+				return nil, NewInvalidPlanError("pick", fmt.Sprintf(
+					"Commit %s is a merge; pick the commits it merged instead.", git.ShortHash(commit.Hash)))
+			}
+			originals[i] = commit.AuthorDate
+		}
+
+		dates := datetime.ScheduleByInterval(group.date, len(commits), options.Interval)
+		if options.PreserveGaps {
+			dates = datetime.ScheduleByGaps(group.date, originals)
+		}
+		for i, commit := range commits {
+			subject, err := git.GetCommitSubject(commit.Hash)
+			if err != nil {
+				return nil, NewGitCommandError(err.Error())
+			}
+			steps = append(steps, pickStep{commit: commit, subject: subject, date: dates[i]})
+		}
+	}
+	return steps, nil
+}
+
+// resolvePickRevisions resolves revisions to commit hashes, expanding
+// ranges such as main..feature oldest first.
+func resolvePickRevisions(revs []string) ([]string, error) {
+	var hashes []string
+	for _, rev := range revs {
+		if strings.Contains(rev, "..") {
+			listed, err := git.ListRange(rev)
+			if err != nil || len(listed) == 0 {
+				return nil, NewUnknownRefError(rev)
+			}
+			hashes = append(hashes, listed...)
+			continue
+		}
+
+		hash, err := git.ResolveCommit(rev)
+		if err != nil {
+			return nil, NewUnknownRefError(rev)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+// validatePickChronology checks the first date against the chronology floor
+// and every later date against the one before it.
+func (a *App) validatePickChronology(steps []pickStep) error {
+	floor, err := a.findChronologyFloor()
+	if err != nil {
+		return err
+	}
+	if err := a.validateChronology(steps[0].date, floor); err != nil {
+		return err
+	}
+
+	dates := make([]time.Time, len(steps))
+	for i, step := range steps {
+		dates[i] = step.date
+	}
+	if i := datetime.FirstNonIncreasing(dates); i >= 0 {
+		slog.Error("Planned dates are not increasing", "index", i, "date", dates[i], "previous", dates[i-1])
+		return NewSequenceChronologyError(i+1, git.ShortHash(steps[i].commit.Hash), dates[i], dates[i-1])
+	}
+	return nil
+}
+
+// checkReadyToPick refuses to pick while another operation is in progress
+// or changes are staged, which would end up in the first picked commit.
+func checkReadyToPick() error {
+	if err := checkNoOperationInProgress(); err != nil {
+		return err
+	}
+
+	staged, err := git.HasIndexChanges("")
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+	if staged {
+		return NewStagedChangesPresentError()
+	}
+	return nil
+}
+
+// pickOne applies one commit and commits it with its original author and
+// message at the planned date. Commits whose changes are already present are skipped.
+func (a *App) pickOne(step pickStep, options PickOptions) error {
+	hash := step.commit.Hash
+	gitFormattedDate := datetime.FormatForGit(step.date)
+
+	if err := git.CherryPickNoCommit(hash); err != nil {
+		return err
+	}
+
+	staged, err := git.HasIndexChanges("")
+	if err != nil {
+		return err
+	}
+	if !staged {
+		slog.Warn("Skipping commit whose changes are already present", "commit", git.ShortHash(hash))
+		return nil
+	}
+
+	author, err := git.GetCommitAuthor(hash)
+	if err != nil {
+		return err
+	}
+	message, err := pickMessage(hash, options.RecordOrigin)
+	if err != nil {
+		return err
+	}
+
+	commitOptions := git.CommitOptions{
+		Author:     &author,
+		Sign:       a.config.Sign.Enabled,
+		SignKey:    a.config.Sign.KeyID,
+		NoSign:     a.config.NoSign,
+		ShowOutput: a.config.ShowGitOutput,
+	}
+	if err := git.ExecuteCommit(gitFormattedDate, message, commitOptions); err != nil {
+		return err
+	}
+
+	fmt.Println(FormatPickStepMessage(step.commit.Hash, step.subject, gitFormattedDate))
+	return nil
+}
+
+// pickMessage returns the message of the commit at hash, with its origin
+// appended when recordOrigin is set.
+func pickMessage(hash string, recordOrigin bool) (string, error) {
+	message, err := git.GetCommitMessage(hash)
+	if err != nil {
+		return "", err
+	}
+	message = strings.TrimRight(message, "\n")
+	if recordOrigin {
+		message += "\n\n(cherry picked from commit " + hash + ")"
+	}
+	return message, nil
+}
+
+// pickFailureError converts the failure of the first of remaining into a
+// UserError explaining how to finish it and resume with the rest.
+func pickFailureError(err error, remaining []pickStep) *UserError {
+	step := remaining[0]
+	slog.Error("Pick failed", "commit", step.commit.Hash, "error", err)
+
+	if !errors.Is(err, git.ErrMergeConflict) {
+		var commitErr *git.CommitError
+		if errors.As(err, &commitErr) {
+			return commitFailureError(err, "")
+		}
+		return NewGitCommandError(err.Error())
+	}
+
+	// Leave the original message where the hint can point at it
+	messageFile, fileErr := git.GetGitPath(pickMessageFile)
+	if fileErr == nil {
+		message, msgErr := git.GetCommitMessage(step.commit.Hash)
+		if msgErr == nil {
+			fileErr = os.WriteFile(messageFile, []byte(message), 0o600)
+		}
+	}
+	if fileErr != nil {
+		slog.Warn("Could not save the message of the conflicting commit", "error", fileErr)
+	}
+
+	author, authorErr := git.GetCommitAuthor(step.commit.Hash)
+	if authorErr != nil {
+		slog.Warn("Could not read the author of the conflicting commit", "error", authorErr)
+	}
+
+	conflicts, _ := git.GetConflictedPaths()
+	return NewPickConflictError(step.commit.Hash, conflicts, pickResumeCommands(remaining, author, messageFile))
+}
+
+// pickResumeCommands returns the commands that finish the conflicting first
+// step and pick the rest with the dates already planned.
+func pickResumeCommands(remaining []pickStep, author git.Identity, messageFile string) []string {
+	commands := []string{fmt.Sprintf("gitcommit --author %q -F %s %q",
+		author.String(), messageFile, remaining[0].date.Format(datetime.InputDateLayout))}
+
+	if len(remaining) > 1 {
+		resume := "gitcommit pick"
+		for _, step := range remaining[1:] {
+			resume += fmt.Sprintf(" %q %s", step.date.Format(datetime.InputDateLayout), git.ShortHash(step.commit.Hash))
+		}
+		commands = append(commands, resume)
+	}
+	return commands
+}
+
+// pickPlanLines describes each step of a pick for FormatPickPlanMessage.
+func pickPlanLines(steps []pickStep) []PlanLine {
+	lines := make([]PlanLine, len(steps))
+	for i, step := range steps {
+		lines[i] = PlanLine{
+			Hash:    step.commit.Hash,
+			Subject: step.subject,
+			Before:  step.commit.AuthorDate,
+			After:   step.date,
+		}
+	}
+	return lines
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+const (
+	// ProvenanceNote records provenance in a git note under ProvenanceNotesRef.
+	ProvenanceNote = "note"
+
+	// ProvenanceTrailer records provenance as trailers of the commit message.
+	ProvenanceTrailer = "trailer"
+
+	// ProvenanceNotesRef is the notes ref holding provenance notes.
+	ProvenanceNotesRef = "refs/notes/gitcommit"
+
+	// ProvenanceConfigKey is the git config key that enables provenance by default.
+	ProvenanceConfigKey = "gitcommit.provenance"
+
+	// Keys of the provenance fields, used both as note lines and as trailers.
+	provenanceCreatedAtKey = "Gitcommit-Created-At"
+	provenanceVersionKey   = "Gitcommit-Version"
+	provenanceInputKey     = "Gitcommit-Input-Date"
+)
+
+// Provenance records when and how a commit with a custom date was really made.
+type Provenance struct {
+	// CreatedAt is the wall-clock time at which gitcommit ran.
+	CreatedAt time.Time
+
+	// Version is the gitcommit version that made the commit.
+	Version string
+
+	// InputDate is the date string exactly as the user gave it.
+	InputDate string
+}
+
+// Trailers returns the provenance fields as "Key: value" trailers.
+func (p Provenance) Trailers() []git.Trailer {
+	return []git.Trailer{
+		{Key: provenanceCreatedAtKey, Value: p.CreatedAt.Format(time.RFC3339)},
+		{Key: provenanceVersionKey, Value: p.Version},
+		{Key: provenanceInputKey, Value: p.InputDate},
+	}
+}
+
+// Note returns the provenance fields as the body of a git note.
+func (p Provenance) Note() string {
+	var b strings.Builder
+	for _, trailer := range p.Trailers() {
+		b.WriteString(trailer.String() + "\n")
+	}
+	return b.String()
+}
+
+// provenanceFromTrailers extracts provenance from trailers or note lines,
+// reporting whether the creation time was found.
+func provenanceFromTrailers(trailers []git.Trailer) (Provenance, bool) {
+	var provenance Provenance
+	found := false
+	for _, trailer := range trailers {
+		switch {
+		case strings.EqualFold(trailer.Key, provenanceCreatedAtKey):
+			createdAt, err := time.Parse(time.RFC3339, trailer.Value)
+			if err != nil {
+				slog.Warn("Ignoring unparseable provenance time", "value", trailer.Value, "error", err)
+				continue
+			}
+			provenance.CreatedAt = createdAt
+			found = true
+		case strings.EqualFold(trailer.Key, provenanceVersionKey):
+			provenance.Version = trailer.Value
+		case strings.EqualFold(trailer.Key, provenanceInputKey):
+			provenance.InputDate = trailer.Value
+		}
+	}
+	return provenance, found
+}
+
+// parseNote reads the "Key: value" lines of a provenance note.
+func parseNote(note string) []git.Trailer {
+	var trailers []git.Trailer
+	for _, line := range strings.Split(note, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if found {
+			trailers = append(trailers, git.Trailer{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+		}
+	}
+	return trailers
+}
+
+// resolveProvenanceMode returns the provenance mode from --provenance or,
+// failing that, the gitcommit.provenance config key. Empty means disabled.
+func (a *App) resolveProvenanceMode() (string, error) {
+	mode := a.config.Provenance
+	if mode == "" {
+		configured, err := git.GetConfig(ProvenanceConfigKey)
+		if err != nil {
+			return "", NewGitCommandError(err.Error())
+		}
+		mode = configured
+	}
+
+	if !isProvenanceMode(mode) {
+		return "", NewInvalidProvenanceModeError(mode)
+	}
+	return mode, nil
+}
+
+// isProvenanceMode reports whether mode is a valid provenance mode, or empty.
+func isProvenanceMode(mode string) bool {
+	return mode == "" || mode == ProvenanceNote || mode == ProvenanceTrailer
+}
+
+// provenance returns the provenance of the commit being made.
+func (a *App) provenance(request *CommitRequest) Provenance {
+	return Provenance{
+		CreatedAt: request.CreatedAt,
+		Version:   a.config.Version,
+		InputDate: request.InputDate,
+	}
+}
+
+// recordProvenanceNote attaches the provenance note to HEAD.
+func recordProvenanceNote(provenance Provenance) error {
+	if err := git.AddNote(ProvenanceNotesRef, "HEAD", provenance.Note()); err != nil {
+		slog.Error("Recording provenance failed", "error", err)
+		return NewProvenanceNoteError(err.Error())
+	}
+	slog.Debug("Provenance note recorded", "ref", ProvenanceNotesRef)
+	return nil
+}
+
+// RunProvenance implements "gitcommit provenance [<rev>]".
+func RunProvenance(args []string) error {
+	flags := flag.NewFlagSet("provenance", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, ProvenanceHelpText()) }
+	_ = flags.Parse(args)
+
+	rev := "HEAD"
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		rev = flags.Arg(0)
+	default:
+		return NewCommandArgumentsError("provenance", "[<rev>]", flags.NArg())
+	}
+
+	return ShowProvenance(rev)
+}
+
+// ShowProvenance prints the provenance recorded for rev, read from its note
+// under ProvenanceNotesRef or, failing that, from its message trailers.
+func ShowProvenance(rev string) error {
+	if !git.IsGitRepository() {
+		return NewNoRepositoryError()
+	}
+
+	hash, err := git.ResolveCommit(rev)
+	if err != nil {
+		return NewUnknownRefError(rev)
+	}
+
+	source := "note (" + ProvenanceNotesRef + ")"
+	note, hasNote, err := git.GetNote(ProvenanceNotesRef, hash)
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+	provenance, found := provenanceFromTrailers(parseNote(note))
+
+	if !hasNote || !found {
+		message, err := git.GetCommitMessage(hash)
+		if err != nil {
+			return NewGitCommandError(err.Error())
+		}
+		trailers, err := git.ParseTrailers(message)
+		if err != nil {
+			return NewGitCommandError(err.Error())
+		}
+		source = "message trailers"
+		provenance, found = provenanceFromTrailers(trailers)
+	}
+
+	if !found {
+		return NewNoProvenanceError(rev)
+	}
+
+	dates, err := git.GetCommitDates(hash)
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+
+	fmt.Println(FormatProvenanceMessage(git.ShortHash(hash), source, provenance, dates))
+	return nil
+}
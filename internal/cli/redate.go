@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// redateUsage is the argument synopsis of the redate command.
+const redateUsage = "[flags] <range> (--start <date> | --plan <file> | --set <rev>=<date>...)"
+
+// planComment starts a comment line in plan files.
+const planComment = "#"
+
+// RedateSource holds where gitcommit redate takes the new dates from.
+// Exactly one of Start, PlanFile and Set is given.
+type RedateSource struct {
+	// Start is the date of the first commit of the range; later commits
+	// follow at Interval, or keep their original gaps with PreserveGaps.
+	Start string
+
+	// Interval separates consecutive commits when scheduling from Start.
+	Interval time.Duration
+
+	// PreserveGaps keeps the gaps between the original dates when scheduling from Start.
+	PreserveGaps bool
+
+	// PlanFile names a file of "<rev> <date>" lines.
+	PlanFile string
+
+	// Set holds "<rev>=<date>" values.
+	Set StringList
+}
+
+// plannedDate is a date given for one revision in a plan file or with --set.
+type plannedDate struct {
+	rev    string
+	date   time.Time
+	origin string // where it was given, for error messages
+}
+
+// RunRedate implements "gitcommit redate <range>".
+func RunRedate(args []string) error {
+	config := NewConfig("")
+	source := RedateSource{}
+	options := RewriteOptions{}
+
+	flags := flag.NewFlagSet("redate", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, RedateHelpText()) }
+	flags.StringVar(&source.Start, "start", "", "Date of the first commit of the range")
+	flags.DurationVar(&source.Interval, "interval", DefaultInterval, "Time between commits scheduled from --start")
+	flags.BoolVar(&source.PreserveGaps, "preserve-gaps", false, "Keep the original gaps between commits after --start")
+	flags.StringVar(&source.PlanFile, "plan", "", "File of \"<rev> <date>\" lines")
+	flags.Var(&source.Set, "set", "Date of one commit, as <rev>=<date> (repeatable)")
+	flags.StringVar(&options.Dates, "dates", DatesBoth, "Dates to rewrite: author, committer or both")
+	flags.BoolVar(&options.DryRun, "dry-run", false, "Show the old and new dates without rewriting")
+	flags.BoolVar(&options.Force, "force", false, "Rewrite commits that are on a remote-tracking branch")
+	flags.BoolVar(&options.UpdateBranches, "update-branches", false,
+		"Also rewrite the other local branches that contain rewritten commits")
+	flags.BoolVar(&options.Resign, "resign", false, "Sign the rewritten commits with the configured key")
+	flags.StringVar(&config.ChronologyBasis, "chronology-basis", config.ChronologyBasis,
+		"Date of the parents to check against: author, committer or max")
+	positional := ParseInterspersed(flags, args)
+
+	if len(positional) != 1 {
+		return NewCommandArgumentsError("redate", redateUsage, len(positional))
+	}
+
+	intervalSet := false
+	flags.Visit(func(f *flag.Flag) { intervalSet = intervalSet || f.Name == "interval" })
+	if err := validateRedateSource(source, intervalSet); err != nil {
+		return err
+	}
+	if !isDatesSelection(options.Dates) {
+		return NewInvalidDatesSelectionError(options.Dates)
+	}
+	if _, err := datetime.ParseChronologyBasis(config.ChronologyBasis); err != nil {
+		return NewInvalidChronologyBasisError(config.ChronologyBasis)
+	}
+
+	options.Input = source.describe()
+	return NewApp(config).Redate(positional[0], source, options)
+}
+
+// describe returns the source of dates as given on the command line.
+func (s RedateSource) describe() string {
+	switch {
+	case s.Start != "":
+		return "--start " + s.Start
+	case s.PlanFile != "":
+		return "--plan " + s.PlanFile
+	default:
+		return "--set " + strings.Join(s.Set, " --set ")
+	}
+}
+
+// validateRedateSource checks that exactly one source of dates is given and
+// that the scheduling flags accompany --start.
+func validateRedateSource(source RedateSource, intervalSet bool) error {
+	var given []string
+	if source.Start != "" {
+		given = append(given, "--start")
+	}
+	if source.PlanFile != "" {
+		given = append(given, "--plan")
+	}
+	if len(source.Set) > 0 {
+		given = append(given, "--set")
+	}
+
+	switch {
+	case len(given) == 0:
+		return NewInvalidPlanError("redate", "Give the new dates with --start, --plan or --set.")
+	case len(given) > 1:
+		return NewConflictingOptionsError(given[0], given[1])
+	case source.Start == "" && (intervalSet || source.PreserveGaps):
+		option := "--interval"
+		if source.PreserveGaps {
+			option = "--preserve-gaps"
+		}
+		return NewConflictingOptionsError(option, given[0]+" (it requires --start)")
+	case intervalSet && source.PreserveGaps:
+		return NewConflictingOptionsError("--interval", "--preserve-gaps")
+	case source.Interval <= 0:
+		return NewInvalidIntervalError(source.Interval.String())
+	}
+	return nil
+}
+
+// Redate rewrites the dates of the commits in rangeArg from source, keeping
+// trees, messages, identities and merge topology, and moves the branch at
+// the tip of the range to the rewritten history.
+func (a *App) Redate(rangeArg string, source RedateSource, options RewriteOptions) error {
+	slog.Info("Processing redate request", "range", rangeArg, "dates", options.Dates)
+
+	if !git.IsGitRepository() {
+		slog.Error("Not in a Git repository")
+		return NewNoRepositoryError()
+	}
+
+	target, err := resolveRewriteTarget(rangeArg)
+	if err != nil {
+		return err
+	}
+
+	var steps []rewriteStep
+	if source.Start != "" {
+		steps, err = a.scheduleRedate(target, source, options.Dates)
+	} else {
+		steps, err = planRedate(target, source, options.Dates)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := a.validateRewriteChronology(steps); err != nil {
+		return err
+	}
+
+	return a.applyRewrite("redate", target, steps, options)
+}
+
+// scheduleRedate dates the commits of target from source.Start onwards, at
+// source.Interval or keeping their original gaps.
+func (a *App) scheduleRedate(target *rewriteTarget, source RedateSource, dates string) ([]rewriteStep, error) {
+	start, err := a.parseDate(source.Start)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := datetime.ScheduleByInterval(start, len(target.commits), source.Interval)
+	if source.PreserveGaps {
+		originals := make([]time.Time, len(target.commits))
+		for i, commit := range target.commits {
+			originals[i] = selectedDate(commit, dates)
+		}
+		scheduled = datetime.ScheduleByGaps(start, originals)
+	}
+
+	steps := make([]rewriteStep, len(target.commits))
+	for i, commit := range target.commits {
+		steps[i] = dateStep(commit, scheduled[i], dates)
+	}
+	return steps, nil
+}
+
+// planRedate dates the commits named in source.PlanFile or source.Set and
+// leaves the others alone.
+func planRedate(target *rewriteTarget, source RedateSource, dates string) ([]rewriteStep, error) {
+	var planned []plannedDate
+	var err error
+	if source.PlanFile != "" {
+		planned, err = readPlanFile(source.PlanFile)
+	} else {
+		planned, err = parseSetValues(source.Set)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inRange := make(map[string]bool, len(target.commits))
+	for _, commit := range target.commits {
+		inRange[commit.Hash] = true
+	}
+
+	byHash := make(map[string]plannedDate, len(planned))
+	for _, entry := range planned {
+		hash, err := git.ResolveCommit(entry.rev)
+		if err != nil {
+			return nil, NewInvalidPlanError("redate", fmt.Sprintf("%s: unknown revision %q.", entry.origin, entry.rev))
+		}
+		if !inRange[hash] {
+			return nil, NewInvalidPlanError("redate",
+				fmt.Sprintf("%s: %s is not in the range %s.", entry.origin, entry.rev, target.rangeArg))
+		}
+		if previous, ok := byHash[hash]; ok {
+			return nil, NewInvalidPlanError("redate",
+				fmt.Sprintf("%s: %s is already dated by %s.", entry.origin, entry.rev, previous.origin))
+		}
+		byHash[hash] = entry
+	}
+
+	steps := make([]rewriteStep, len(target.commits))
+	for i, commit := range target.commits {
+		steps[i] = keepStep(commit)
+		if entry, ok := byHash[commit.Hash]; ok {
+			steps[i] = dateStep(commit, entry.date, dates)
+		}
+	}
+	return steps, nil
+}
+
+// readPlanFile reads "<rev> <date>" lines, skipping blank lines and comments.
+func readPlanFile(path string) ([]plannedDate, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, NewInvalidPlanError("redate", "Cannot read the plan file: "+err.Error())
+	}
+	defer file.Close()
+
+	var planned []plannedDate
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, planComment) {
+			continue
+		}
+
+		origin := fmt.Sprintf("%s:%d", path, number)
+		rev, dateStr, found := strings.Cut(line, " ")
+		if !found {
+			return nil, NewInvalidPlanError("redate", origin+": expected \"<rev> <date>\", got "+line)
+		}
+		entry, err := newPlannedDate(rev, strings.TrimSpace(dateStr), origin)
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewInvalidPlanError("redate", "Cannot read the plan file: "+err.Error())
+	}
+	return planned, nil
+}
+
+// parseSetValues parses "<rev>=<date>" values given with --set.
+func parseSetValues(values []string) ([]plannedDate, error) {
+	planned := make([]plannedDate, 0, len(values))
+	for _, value := range values {
+		origin := "--set " + value
+		rev, dateStr, found := strings.Cut(value, "=")
+		if !found {
+			return nil, NewInvalidPlanError("redate", origin+": expected <rev>=<date>.")
+		}
+		entry, err := newPlannedDate(strings.TrimSpace(rev), strings.TrimSpace(dateStr), origin)
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, entry)
+	}
+	return planned, nil
+}
+
+// newPlannedDate parses the date given for rev at origin.
+func newPlannedDate(rev, dateStr, origin string) (plannedDate, error) {
+	date, err := datetime.ParseDate(dateStr)
+	if err != nil {
+		return plannedDate{}, NewInvalidPlanError("redate",
+			fmt.Sprintf("%s: invalid date %q (expected YYYY-MM-DD HH:MM:SS).", origin, dateStr))
+	}
+	return plannedDate{rev: rev, date: date, origin: origin}, nil
+}
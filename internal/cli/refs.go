@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+const (
+	// tagRefPrefix is the namespace of tags, which follow the commits they point to.
+	tagRefPrefix = "refs/tags/"
+)
+
+// refRewrite is what a rewrite does beyond its range: the tags and, with
+// --update-branches, the other local branches that point at rewritten
+// commits or build on them.
+type refRewrite struct {
+	// refs are the tags and branches to move, in the order listed by git.
+	refs []git.RefTarget
+
+	// branchCommits are the commits of those branches, outside the range,
+	// that get new parents, parents before children. They keep their dates.
+	branchCommits []git.CommitObject
+
+	// tags are the annotated tag objects of the tags among refs, by ref name.
+	tags map[string]git.TagObject
+}
+
+// planRefRewrite finds the refs that follow a rewrite of target, where
+// affected are the commits getting a new hash. With --update-branches, the
+// commits other branches build on top of the range are checked to still
+// follow their rewritten parents and, unless check is false or --force is
+// set, to be unpublished.
+func (a *App) planRefRewrite(
+	target *rewriteTarget,
+	steps []rewriteStep,
+	affected map[string]bool,
+	options RewriteOptions,
+	check bool,
+) (*refRewrite, error) {
+	plan := &refRewrite{tags: map[string]git.TagObject{}}
+	moved := maps.Clone(affected)
+
+	if options.UpdateBranches {
+		branches, err := git.ListRefTargets(strings.TrimSuffix(branchRefPrefix, "/"))
+		if err != nil {
+			return nil, NewRewriteError(err.Error())
+		}
+		for _, branch := range branches {
+			if branch.Name == target.ref {
+				continue
+			}
+			commits, err := a.branchCommitsToRewrite(target, steps, branch, moved, check && !options.Force)
+			if err != nil {
+				return nil, err
+			}
+			plan.branchCommits = append(plan.branchCommits, commits...)
+			if moved[branch.Commit] {
+				plan.refs = append(plan.refs, branch)
+			}
+		}
+	}
+
+	tags, err := git.ListRefTargets(strings.TrimSuffix(tagRefPrefix, "/"))
+	if err != nil {
+		return nil, NewRewriteError(err.Error())
+	}
+	for _, tag := range tags {
+		if !moved[tag.Commit] {
+			continue
+		}
+		plan.refs = append(plan.refs, tag)
+		if tag.Type == "tag" {
+			object, err := git.ReadTag(tag.Hash)
+			if err != nil {
+				return nil, NewRewriteError(err.Error())
+			}
+			plan.tags[tag.Name] = object
+		}
+	}
+
+	slog.Debug("Refs following the rewrite", "refs", len(plan.refs), "branchCommits", len(plan.branchCommits))
+	return plan, nil
+}
+
+// branchCommitsToRewrite returns the commits of branch, outside the range
+// of target, that build on a commit in moved, and adds them to moved.
+// Each must be dated after the rewritten parents it keeps; with check, it
+// must also be on no remote-tracking branch.
+func (a *App) branchCommitsToRewrite(
+	target *rewriteTarget,
+	steps []rewriteStep,
+	branch git.RefTarget,
+	moved map[string]bool,
+	check bool,
+) ([]git.CommitObject, error) {
+	branchRange := target.tip + ".." + branch.Hash
+	hashes, err := git.ListRange(branchRange)
+	if err != nil {
+		return nil, NewRewriteError(err.Error())
+	}
+
+	stepOf := make(map[string]rewriteStep, len(steps))
+	for _, step := range steps {
+		stepOf[step.commit.Hash] = step
+	}
+	basis := a.config.GetChronologyBasis()
+
+	var commits []git.CommitObject
+	for _, hash := range hashes {
+		if moved[hash] {
+			continue // shared with a branch already planned
+		}
+		commit, err := git.ReadCommit(hash)
+		if err != nil {
+			return nil, NewRewriteError(err.Error())
+		}
+		if !slices.ContainsFunc(commit.Parents, func(parent string) bool { return moved[parent] }) {
+			continue
+		}
+
+		for _, parent := range commit.Parents {
+			step, ok := stepOf[parent]
+			if !ok || !step.changed() {
+				continue
+			}
+			date := basis.Select(commit.AuthorDate, commit.CommitterDate)
+			parentDate := basis.Select(step.authorDate, step.committerDate)
+			if !date.After(parentDate) {
+				slog.Error("Branch commit would not follow its rewritten parent",
+					"branch", branch.Name, "commit", commit.Hash, "parent", parent)
+				return nil, NewBranchChronologyError(shortRefName(branch.Name), commit, date, parent, parentDate, basis)
+			}
+		}
+		moved[hash] = true
+		commits = append(commits, commit)
+	}
+
+	if check && len(commits) > 0 {
+		if err := checkBranchUnpublished(branchRange, commits); err != nil {
+			return nil, err
+		}
+	}
+	return commits, nil
+}
+
+// checkBranchUnpublished refuses to rewrite branch commits that a
+// remote-tracking branch contains.
+func checkBranchUnpublished(branchRange string, commits []git.CommitObject) error {
+	unpublished, err := git.ListUnpublished(branchRange)
+	if err != nil {
+		return NewRewriteError(err.Error())
+	}
+
+	var published []string
+	for _, commit := range commits {
+		if !slices.Contains(unpublished, commit.Hash) {
+			published = append(published, git.ShortHash(commit.Hash))
+		}
+	}
+	if len(published) > 0 {
+		slog.Error("Refusing to rewrite published commits", "commits", published)
+		return NewRewritePublishedError(published)
+	}
+	return nil
+}
+
+// writeRefRewrite writes the branch commits of plan on top of their
+// rewritten parents, signed with resign, adding them to rewritten, and
+// returns the updates that move the refs of plan. Annotated tags are
+// recreated on the new commit with their tagger, date and message; their
+// signature cannot be kept, but signed tags are signed again with resign.
+func writeRefRewrite(plan *refRewrite, rewritten map[string]string, resign bool, format string) ([]git.RefUpdate, error) {
+	for _, commit := range plan.branchCommits {
+		old := commit.Hash
+		commit.Parents = slices.Clone(commit.Parents)
+		for i, parent := range commit.Parents {
+			if hash, ok := rewritten[parent]; ok {
+				commit.Parents[i] = hash
+			}
+		}
+
+		hash, err := writeRewrittenCommit(commit, old, resign, format)
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("Branch commit rewritten", "old", old, "new", hash)
+		rewritten[old] = hash
+	}
+
+	updates := make([]git.RefUpdate, 0, len(plan.refs))
+	for _, ref := range plan.refs {
+		update := git.RefUpdate{Name: ref.Name, Old: ref.Hash, New: rewritten[ref.Commit]}
+		if ref.Type == "tag" {
+			hash, err := rewriteTagObject(plan.tags[ref.Name], update.New, resign, format)
+			if err != nil {
+				return nil, err
+			}
+			update.New = hash
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
+
+// rewriteTagObject writes a copy of the annotated tag on commit instead, and
+// returns its hash. With resign, a signed tag is signed again with the
+// configured key, of format; otherwise the copy is not signed.
+func rewriteTagObject(tag git.TagObject, commit string, resign bool, format string) (string, error) {
+	old := tag.Hash
+	tag.Object = commit
+
+	var hash string
+	var err error
+	if resign && tag.Signed {
+		hash, err = git.WriteSignedTag(tag)
+	} else {
+		hash, err = git.WriteTag(tag)
+	}
+	switch {
+	case errors.Is(err, git.ErrTagSigningFailed):
+		slog.Error("Signing rewritten tag failed", "tag", tag.Name, "format", format, "error", err)
+		return "", NewResignFailedError(format, err.Error())
+	case err != nil:
+		slog.Error("Writing rewritten tag failed", "tag", tag.Name, "error", err)
+		return "", NewRewriteError(err.Error())
+	}
+	slog.Debug("Tag rewritten", "tag", tag.Name, "old", old, "new", hash)
+	return hash, nil
+}
+
+// shortTagName returns the tag name of a full ref such as refs/tags/v1.0.
+func shortTagName(ref string) string {
+	return strings.TrimPrefix(ref, tagRefPrefix)
+}
+
+// describeRef names a ref for messages: "tag v1.0", "branch feature", or the
+// full name of any other ref.
+func describeRef(ref string) string {
+	if name, ok := strings.CutPrefix(ref, tagRefPrefix); ok {
+		return "tag " + name
+	}
+	if name, ok := strings.CutPrefix(ref, branchRefPrefix); ok {
+		return "branch " + name
+	}
+	return ref
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"time"
+
+	"github.com/sgaunet/gitcommit/internal/git"
 )
 
 // CommitRequest represents a user's request to create a commit with a specific date.
@@ -17,6 +19,31 @@ type CommitRequest struct {
 
 	// GitFormattedDate is the date formatted for Git environment variables.
 	GitFormattedDate string
+
+	// Author is the identity recorded as the commit's author.
+	Author git.Identity
+
+	// Committer is the identity recorded as the commit's committer.
+	Committer git.Identity
+
+	// IdentityWarnings describe --author and --committer values that .mailmap
+	// maps elsewhere or that disagree with the configured user.* identity.
+	IdentityWarnings []string
+
+	// Sign reports whether the commit is signed, from -S or commit.gpgsign.
+	Sign bool
+
+	// SigningFormat is gpg.format: openpgp, x509 or ssh.
+	SigningFormat string
+
+	// Operation is the merge, cherry-pick or revert being concluded, if any.
+	Operation git.Operation
+
+	// CreatedAt is the wall-clock time at which the request was made.
+	CreatedAt time.Time
+
+	// ProvenanceMode is how provenance is recorded: note, trailer, or empty for none.
+	ProvenanceMode string
 }
 
 // NewCommitRequest creates a new CommitRequest from user input.
@@ -24,5 +51,6 @@ func NewCommitRequest(date, message string) *CommitRequest {
 	return &CommitRequest{
 		InputDate:     date,
 		CommitMessage: message,
+		CreatedAt:     time.Now(),
 	}
 }
@@ -0,0 +1,601 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+const (
+	// DatesAuthor rewrites author dates only.
+	DatesAuthor = "author"
+
+	// DatesCommitter rewrites committer dates only.
+	DatesCommitter = "committer"
+
+	// DatesBoth rewrites author and committer dates alike.
+	DatesBoth = "both"
+
+	// branchRefPrefix is the namespace of local branches, the only refs a rewrite moves.
+	branchRefPrefix = "refs/heads/"
+)
+
+// RewriteOptions holds the settings shared by the commands that rewrite history.
+type RewriteOptions struct {
+	// Dates selects which dates are rewritten: author, committer or both.
+	Dates string
+
+	// DryRun prints the old and new dates without rewriting anything.
+	DryRun bool
+
+	// Force allows rewriting commits that are on a remote-tracking branch.
+	Force bool
+
+	// UpdateBranches also rewrites the other local branches that contain
+	// rewritten commits, keeping the dates of their own commits.
+	UpdateBranches bool
+
+	// Resign signs every rewritten commit with the configured key. Without
+	// it, the signatures of the rewritten commits are dropped and reported.
+	Resign bool
+
+	// Input is what the new dates came from, as given, for the journal.
+	Input string
+}
+
+// rewriteTarget is a range of history to rewrite and the ref at its tip.
+type rewriteTarget struct {
+	// rangeArg is the range as given by the user.
+	rangeArg string
+
+	// revisionRange is the resolved range, "<base>..<tip hash>".
+	revisionRange string
+
+	// ref is the full name of the branch to update, or "HEAD" when detached.
+	ref string
+
+	// tip is the commit ref points to.
+	tip string
+
+	// commits are the commits of the range, parents before children.
+	commits []git.CommitObject
+}
+
+// rewriteStep is a commit of the range and the dates it gets.
+type rewriteStep struct {
+	commit        git.CommitObject
+	authorDate    time.Time
+	committerDate time.Time
+}
+
+// changed reports whether the step gives the commit new dates or zone offsets.
+func (s rewriteStep) changed() bool {
+	return !sameRecordedDate(s.authorDate, s.commit.AuthorDate) ||
+		!sameRecordedDate(s.committerDate, s.commit.CommitterDate)
+}
+
+// sameRecordedDate reports whether git would record a and b identically:
+// the same instant with the same zone offset.
+func sameRecordedDate(a, b time.Time) bool {
+	_, aOffset := a.Zone()
+	_, bOffset := b.Zone()
+	return a.Equal(b) && aOffset == bOffset
+}
+
+// keepStep returns a step that leaves the dates of commit alone.
+func keepStep(commit git.CommitObject) rewriteStep {
+	return rewriteStep{commit: commit, authorDate: commit.AuthorDate, committerDate: commit.CommitterDate}
+}
+
+// dateStep returns a step that gives commit date, in the dates selected by dates.
+func dateStep(commit git.CommitObject, date time.Time, dates string) rewriteStep {
+	step := keepStep(commit)
+	if dates != DatesCommitter {
+		step.authorDate = date
+	}
+	if dates != DatesAuthor {
+		step.committerDate = date
+	}
+	return step
+}
+
+// selectedDate returns the date of commit shown and scheduled from: the
+// committer date when only committer dates are rewritten, else the author date.
+func selectedDate(commit git.CommitObject, dates string) time.Time {
+	if dates == DatesCommitter {
+		return commit.CommitterDate
+	}
+	return commit.AuthorDate
+}
+
+// isDatesSelection reports whether dates is a valid --dates value.
+func isDatesSelection(dates string) bool {
+	return dates == DatesAuthor || dates == DatesCommitter || dates == DatesBoth
+}
+
+// resolveRewriteTarget resolves a range such as main~3..main, HEAD~3.. or
+// HEAD~3 (short for HEAD~3..HEAD). The range must end at HEAD or a local
+// branch, which is the ref the rewrite moves.
+func resolveRewriteTarget(rangeArg string) (*rewriteTarget, error) {
+	if strings.Contains(rangeArg, "...") {
+		return nil, NewInvalidRangeError(rangeArg, "Symmetric ranges (A...B) cannot be rewritten.")
+	}
+
+	base, tipRev, _ := strings.Cut(rangeArg, "..")
+	if tipRev == "" {
+		tipRev = "HEAD"
+	}
+
+	ref, err := rewriteRef(tipRev)
+	if err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		return nil, NewInvalidRangeError(rangeArg, "The range must end at HEAD or at a local branch.")
+	}
+
+	tip, err := git.ResolveCommit(tipRev)
+	if err != nil {
+		return nil, NewUnknownRefError(tipRev)
+	}
+	if _, err := git.ResolveCommit(base); err != nil {
+		return nil, NewUnknownRefError(base)
+	}
+
+	revisionRange := base + ".." + tip
+	hashes, err := git.ListRange(revisionRange)
+	if err != nil {
+		return nil, NewRewriteError(err.Error())
+	}
+	if len(hashes) == 0 {
+		return nil, NewInvalidRangeError(rangeArg, "The range contains no commits.")
+	}
+
+	commits := make([]git.CommitObject, 0, len(hashes))
+	for _, hash := range hashes {
+		commit, err := git.ReadCommit(hash)
+		if err != nil {
+			return nil, NewRewriteError(err.Error())
+		}
+		commits = append(commits, commit)
+	}
+
+	slog.Debug("Rewrite target resolved", "range", revisionRange, "ref", ref, "commits", len(commits))
+	return &rewriteTarget{rangeArg: rangeArg, revisionRange: revisionRange, ref: ref, tip: tip, commits: commits}, nil
+}
+
+// rewriteRef returns the ref a rewrite ending at tipRev moves: the current
+// branch (or HEAD when detached) for HEAD, the branch for a branch name,
+// and an empty string for anything else.
+func rewriteRef(tipRev string) (string, error) {
+	if tipRev == "HEAD" {
+		branch, err := git.GetCurrentBranch()
+		if err != nil {
+			return "", NewGitCommandError(err.Error())
+		}
+		if branch == "" {
+			return "HEAD", nil
+		}
+		return branch, nil
+	}
+
+	ref, err := git.GetFullRefName(tipRev)
+	if err != nil {
+		return "", NewUnknownRefError(tipRev)
+	}
+	if !strings.HasPrefix(ref, branchRefPrefix) {
+		return "", nil
+	}
+	return ref, nil
+}
+
+// chronologyViolation is a commit of a rewrite that would not be dated
+// after one of its parents.
+type chronologyViolation struct {
+	// position is the 1-based index of the commit among the steps.
+	position int
+
+	commit     git.CommitObject
+	date       time.Time
+	parent     string
+	parentDate time.Time
+
+	// parentPosition is the 1-based index of the parent among the steps,
+	// or 0 when it lies outside the range.
+	parentPosition int
+}
+
+// validateRewriteChronology checks that every commit is dated after each of
+// its parents, by the configured basis. Pairs in which neither commit gets
+// new dates are left alone, so that existing history is not second-guessed.
+func (a *App) validateRewriteChronology(steps []rewriteStep) error {
+	violations, err := a.chronologyViolations(steps)
+	if err != nil || len(violations) == 0 {
+		return err
+	}
+
+	first := violations[0]
+	slog.Error("Rewritten commit would not follow its parent",
+		"commit", first.commit.Hash, "date", first.date, "parent", first.parent, "parentDate", first.parentDate)
+	return NewRewriteChronologyError(first.position, first.commit, first.date, first.parent, first.parentDate,
+		a.config.GetChronologyBasis())
+}
+
+// chronologyViolations returns, in order, every commit of steps not dated
+// after one of its parents, as validateRewriteChronology checks them.
+func (a *App) chronologyViolations(steps []rewriteStep) ([]chronologyViolation, error) {
+	basis := a.config.GetChronologyBasis()
+
+	positions := make(map[string]int, len(steps))
+	var outside []string
+	for i, step := range steps {
+		positions[step.commit.Hash] = i + 1
+	}
+	for _, step := range steps {
+		for _, parent := range step.commit.Parents {
+			if _, ok := positions[parent]; !ok {
+				outside = append(outside, parent)
+			}
+		}
+	}
+
+	outsideDates := make(map[string]git.CommitDates, len(outside))
+	commits, err := git.GetCommitsDates(outside)
+	if err != nil {
+		return nil, NewRewriteError(err.Error())
+	}
+	for _, commit := range commits {
+		outsideDates[commit.Hash] = commit
+	}
+
+	var violations []chronologyViolation
+	for i, step := range steps {
+		date := basis.Select(step.authorDate, step.committerDate)
+		for _, parent := range step.commit.Parents {
+			parentPosition := positions[parent]
+			if !step.changed() && (parentPosition == 0 || !steps[parentPosition-1].changed()) {
+				continue
+			}
+
+			parentDate := basis.Select(outsideDates[parent].AuthorDate, outsideDates[parent].CommitterDate)
+			if parentPosition > 0 {
+				parentStep := steps[parentPosition-1]
+				parentDate = basis.Select(parentStep.authorDate, parentStep.committerDate)
+			}
+			if !date.After(parentDate) {
+				violations = append(violations, chronologyViolation{
+					position: i + 1, commit: step.commit, date: date,
+					parent: parent, parentDate: parentDate, parentPosition: parentPosition,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// affectedCommits returns the commits that get a new hash: those with new
+// dates and their descendants within the range.
+func affectedCommits(steps []rewriteStep) map[string]bool {
+	affected := make(map[string]bool)
+	for _, step := range steps {
+		if step.changed() {
+			affected[step.commit.Hash] = true
+			continue
+		}
+		for _, parent := range step.commit.Parents {
+			if affected[parent] {
+				affected[step.commit.Hash] = true
+				break
+			}
+		}
+	}
+	return affected
+}
+
+// copyProvenanceNotes copies the provenance notes of the rewritten commits
+// onto their new commits. The history is already rewritten, so a failure is
+// only reported.
+func copyProvenanceNotes(rewritten map[string]string) {
+	copied, err := git.CopyNotes(ProvenanceNotesRef, rewritten)
+	if err != nil {
+		slog.Warn("Could not copy the provenance notes to the rewritten commits",
+			"ref", ProvenanceNotesRef, "error", err)
+		return
+	}
+	slog.Debug("Provenance notes copied", "ref", ProvenanceNotesRef, "notes", copied)
+}
+
+// applyRewrite writes the commits of steps with their new dates, keeping
+// trees, messages, identities and merge topology, then moves the target
+// ref, and the tags and branches that follow it, in one atomic update
+// recorded in their reflogs. With DryRun, it only shows the old and new
+// dates. command names the operation in messages.
+func (a *App) applyRewrite(command string, target *rewriteTarget, steps []rewriteStep, options RewriteOptions) error {
+	affected := affectedCommits(steps)
+
+	if options.DryRun {
+		fmt.Println(FormatRewritePlanMessage(command, rewritePlanLines(steps, options.Dates)))
+		return a.showRewriteEffects(target, steps, options)
+	}
+
+	if len(affected) == 0 {
+		fmt.Println(FormatRewriteUnchangedMessage(command))
+		return nil
+	}
+
+	if err := checkNoOperationInProgress(); err != nil {
+		return err
+	}
+	if !options.Force {
+		if err := checkUnpublished(target, affected); err != nil {
+			return err
+		}
+	}
+	plan, err := a.planRefRewrite(target, steps, affected, options, true)
+	if err != nil {
+		return err
+	}
+	signing, err := git.GetSigningConfig()
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+
+	rewrittenHashes := make(map[string]string, len(affected))
+	for _, step := range steps {
+		if !affected[step.commit.Hash] {
+			continue
+		}
+
+		commit := step.commit
+		commit.AuthorDate = step.authorDate
+		commit.CommitterDate = step.committerDate
+		commit.Parents = make([]string, len(step.commit.Parents))
+		for i, parent := range step.commit.Parents {
+			commit.Parents[i] = parent
+			if rewritten, ok := rewrittenHashes[parent]; ok {
+				commit.Parents[i] = rewritten
+			}
+		}
+
+		hash, err := writeRewrittenCommit(commit, step.commit.Hash, options.Resign, signing.Format)
+		if err != nil {
+			return err
+		}
+		slog.Debug("Commit rewritten", "old", step.commit.Hash, "new", hash)
+		rewrittenHashes[step.commit.Hash] = hash
+	}
+
+	following, err := writeRefRewrite(plan, rewrittenHashes, options.Resign, signing.Format)
+	if err != nil {
+		return err
+	}
+
+	newTip := rewrittenHashes[target.tip]
+	updates := append([]git.RefUpdate{{Name: target.ref, Old: target.tip, New: newTip}}, following...)
+	names := make([]string, len(updates))
+	for i, update := range updates {
+		names[i] = update.Name
+	}
+
+	// The provenance notes are copied onto the new commits, so save them too
+	reason := fmt.Sprintf("gitcommit %s: %s", command, target.rangeArg)
+	backup, err := backupRefs(reason, append(names, ProvenanceNotesRef)...)
+	if err != nil {
+		return err
+	}
+	if err := git.UpdateRefs(updates, reason); err != nil {
+		slog.Error("Updating refs failed", "refs", names, "error", err)
+		discardUnusedBackup(backup)
+		return NewRefUpdateError(strings.Join(names, ", "), err.Error())
+	}
+	copyProvenanceNotes(rewrittenHashes)
+
+	entry := git.JournalEntry{Operation: command, Range: target.rangeArg, InputDate: options.Input}
+	for _, step := range steps {
+		if affected[step.commit.Hash] {
+			entry.Commits = append(entry.Commits, git.JournalCommit{
+				Old: step.commit.Hash, New: rewrittenHashes[step.commit.Hash],
+				AuthorDate: step.authorDate, CommitterDate: step.committerDate,
+			})
+		}
+	}
+	for _, commit := range plan.branchCommits {
+		entry.Commits = append(entry.Commits, git.JournalCommit{
+			Old: commit.Hash, New: rewrittenHashes[commit.Hash],
+			AuthorDate: commit.AuthorDate, CommitterDate: commit.CommitterDate,
+		})
+	}
+	a.recordJournal(entry, backup)
+
+	fmt.Println(FormatRewriteSuccessMessage(command, len(affected), shortRefName(target.ref), target.tip, newTip, following))
+	if options.Resign {
+		fmt.Println(FormatResignedMessage(len(entry.Commits), signedTags(plan), signing.Format))
+	}
+	if loss := droppedSignatures(steps, affected, plan, options.Resign); !loss.empty() {
+		slog.Warn("Signatures dropped from rewritten commits",
+			"commits", len(loss.commits), "tags", len(loss.tags), "mergeTags", len(loss.mergeTags))
+		fmt.Println(FormatDroppedSignaturesMessage(loss))
+	}
+	slog.Info("History rewritten", "command", command, "ref", target.ref, "commits", len(affected),
+		"refs", len(following), "branchCommits", len(plan.branchCommits))
+	return nil
+}
+
+// showRewriteEffects prints, for a dry run, the tags and branches that
+// would follow the rewrite of target and the signatures it would drop.
+func (a *App) showRewriteEffects(target *rewriteTarget, steps []rewriteStep, options RewriteOptions) error {
+	affected := affectedCommits(steps)
+	plan, err := a.planRefRewrite(target, steps, affected, options, false)
+	if err != nil {
+		return err
+	}
+
+	if len(plan.refs) > 0 {
+		fmt.Println(FormatFollowingRefsPlanMessage(plan.refs, len(plan.branchCommits)))
+	}
+	if loss := droppedSignatures(steps, affected, plan, options.Resign); !loss.empty() {
+		fmt.Println(FormatDroppedSignaturesPlanMessage(loss))
+	}
+	return nil
+}
+
+// writeRewrittenCommit writes commit in place of old and returns its hash.
+// With resign, the commit is signed with the configured key, of format.
+// Its mergetag headers are dropped when resigning, which git cannot do with
+// them, and when the tagged parent was rewritten, which voids them.
+func writeRewrittenCommit(commit git.CommitObject, old string, resign bool, format string) (string, error) {
+	parents := commit.Parents
+	commit = commit.WithoutMergeTags(func(tag git.MergeTag) bool {
+		return resign || !slices.Contains(parents, tag.Object)
+	})
+
+	hash, err := git.WriteCommit(commit, resign)
+	switch {
+	case errors.Is(err, git.ErrCommitSigningFailed):
+		slog.Error("Signing rewritten commit failed", "commit", old, "format", format, "error", err)
+		return "", NewResignFailedError(format, err.Error())
+	case errors.Is(err, git.ErrHeadersNotSignable):
+		slog.Error("Rewritten commit cannot be signed", "commit", old, "error", err)
+		return "", NewResignHeadersError(err.Error())
+	case err != nil:
+		slog.Error("Writing rewritten commit failed", "commit", old, "error", err)
+		return "", NewRewriteError(err.Error())
+	}
+	return hash, nil
+}
+
+// signatureLoss lists the signatures a rewrite cannot keep.
+type signatureLoss struct {
+	// commits are the signed commits recreated without a signature.
+	commits []string
+
+	// tags are the names of the signed tags recreated without a signature.
+	tags []string
+
+	// mergeTags are the signed tags merged by rewritten commits whose
+	// mergetag headers are dropped, as "<tag> in <commit>".
+	mergeTags []string
+}
+
+// empty reports whether no signature is lost.
+func (l signatureLoss) empty() bool {
+	return len(l.commits) == 0 && len(l.tags) == 0 && len(l.mergeTags) == 0
+}
+
+// droppedSignatures returns the signatures a rewrite loses: those of the
+// signed commits it recreates, range first, then the commits of the
+// branches that follow it, and of the signed tags it recreates, unless
+// resign signs them again; and those of the mergetag headers that
+// writeRewrittenCommit drops, which resign cannot restore.
+func droppedSignatures(steps []rewriteStep, affected map[string]bool, plan *refRewrite, resign bool) signatureLoss {
+	rewritten := make([]git.CommitObject, 0, len(affected)+len(plan.branchCommits))
+	moved := make(map[string]bool, cap(rewritten))
+	for _, step := range steps {
+		if affected[step.commit.Hash] {
+			rewritten = append(rewritten, step.commit)
+			moved[step.commit.Hash] = true
+		}
+	}
+	for _, commit := range plan.branchCommits {
+		rewritten = append(rewritten, commit)
+		moved[commit.Hash] = true
+	}
+
+	var loss signatureLoss
+	for _, commit := range rewritten {
+		if commit.Signed && !resign {
+			loss.commits = append(loss.commits, commit.Hash)
+		}
+		for _, tag := range commit.MergeTags() {
+			if resign || moved[tag.Object] || !slices.Contains(commit.Parents, tag.Object) {
+				loss.mergeTags = append(loss.mergeTags, tag.Name+" in "+git.ShortHash(commit.Hash))
+			}
+		}
+	}
+	if !resign {
+		for _, ref := range plan.refs {
+			if tag, ok := plan.tags[ref.Name]; ok && tag.Signed {
+				loss.tags = append(loss.tags, shortTagName(ref.Name))
+			}
+		}
+	}
+	return loss
+}
+
+// signedTags returns how many of the tags of plan are signed, which resign
+// signs again.
+func signedTags(plan *refRewrite) int {
+	count := 0
+	for _, tag := range plan.tags {
+		if tag.Signed {
+			count++
+		}
+	}
+	return count
+}
+
+// checkNoOperationInProgress refuses to run while a merge, cherry-pick,
+// revert or rebase is in progress.
+func checkNoOperationInProgress() error {
+	operation, err := git.GetOperationInProgress()
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+	if operation != git.OperationNone {
+		return NewOperationInProgressError(operation)
+	}
+	return nil
+}
+
+// checkUnpublished refuses to rewrite commits that a remote-tracking branch contains.
+func checkUnpublished(target *rewriteTarget, affected map[string]bool) error {
+	unpublished, err := git.ListUnpublished(target.revisionRange)
+	if err != nil {
+		return NewRewriteError(err.Error())
+	}
+
+	isUnpublished := make(map[string]bool, len(unpublished))
+	for _, hash := range unpublished {
+		isUnpublished[hash] = true
+	}
+
+	var published []string
+	for _, commit := range target.commits {
+		if affected[commit.Hash] && !isUnpublished[commit.Hash] {
+			published = append(published, git.ShortHash(commit.Hash))
+		}
+	}
+	if len(published) > 0 {
+		slog.Error("Refusing to rewrite published commits", "commits", published)
+		return NewRewritePublishedError(published)
+	}
+	return nil
+}
+
+// rewritePlanLines describes each step of a rewrite for FormatRewritePlanMessage.
+func rewritePlanLines(steps []rewriteStep, dates string) []PlanLine {
+	lines := make([]PlanLine, len(steps))
+	for i, step := range steps {
+		after := step.authorDate
+		if dates == DatesCommitter {
+			after = step.committerDate
+		}
+		lines[i] = PlanLine{
+			Hash:    step.commit.Hash,
+			Subject: step.commit.Subject(),
+			Before:  selectedDate(step.commit, dates),
+			After:   after,
+		}
+	}
+	return lines
+}
+
+// shortRefName returns the branch name of a full ref such as refs/heads/main.
+func shortRefName(ref string) string {
+	return strings.TrimPrefix(ref, branchRefPrefix)
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// shiftUsage is the argument synopsis of the shift command.
+const shiftUsage = "[flags] <range> <+/-duration>"
+
+// shiftArguments is the number of positional arguments of the shift command.
+const shiftArguments = 2
+
+// offsetPattern matches a signed offset such as -2h or +1h30m, which would
+// otherwise be taken for a flag.
+var offsetPattern = regexp.MustCompile(`^[+-][0-9]`)
+
+// RunShift implements "gitcommit shift <range> <+/-duration>".
+func RunShift(args []string) error {
+	config := NewConfig("")
+	options := RewriteOptions{}
+
+	flags := flag.NewFlagSet("shift", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, ShiftHelpText()) }
+	flags.StringVar(&options.Dates, "dates", DatesBoth, "Dates to shift: author, committer or both")
+	flags.BoolVar(&options.DryRun, "dry-run", false, "Show the old and new dates without rewriting")
+	flags.BoolVar(&options.Force, "force", false, "Rewrite commits that are on a remote-tracking branch")
+	flags.BoolVar(&options.UpdateBranches, "update-branches", false,
+		"Also rewrite the other local branches that contain rewritten commits")
+	flags.BoolVar(&options.Resign, "resign", false, "Sign the rewritten commits with the configured key")
+	flags.StringVar(&config.ChronologyBasis, "chronology-basis", config.ChronologyBasis,
+		"Date of the parents to check against: author, committer or max")
+
+	// A negative offset looks like a flag, so take it out before parsing.
+	// "--" may still precede it, but does not end the flags.
+	var offsets, rest []string
+	for _, arg := range args {
+		switch {
+		case arg == PathspecSeparator:
+			continue
+		case offsetPattern.MatchString(arg):
+			offsets = append(offsets, arg)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	positional := append(ParseInterspersed(flags, rest), offsets...)
+
+	if len(positional) != shiftArguments {
+		return NewCommandArgumentsError("shift", shiftUsage, len(positional))
+	}
+
+	offset, err := time.ParseDuration(positional[1])
+	if err != nil {
+		return NewInvalidOffsetError(positional[1])
+	}
+	if !isDatesSelection(options.Dates) {
+		return NewInvalidDatesSelectionError(options.Dates)
+	}
+	if _, err := datetime.ParseChronologyBasis(config.ChronologyBasis); err != nil {
+		return NewInvalidChronologyBasisError(config.ChronologyBasis)
+	}
+
+	options.Input = positional[1]
+	return NewApp(config).Shift(positional[0], offset, options)
+}
+
+// Shift moves the selected dates of every commit in rangeArg by offset,
+// keeping the gaps between them and their zone offsets. A backward shift
+// is refused when a commit would no longer follow a parent outside the range.
+func (a *App) Shift(rangeArg string, offset time.Duration, options RewriteOptions) error {
+	slog.Info("Processing shift request", "range", rangeArg, "offset", offset, "dates", options.Dates)
+
+	if !git.IsGitRepository() {
+		slog.Error("Not in a Git repository")
+		return NewNoRepositoryError()
+	}
+
+	target, err := resolveRewriteTarget(rangeArg)
+	if err != nil {
+		return err
+	}
+
+	steps := make([]rewriteStep, len(target.commits))
+	for i, commit := range target.commits {
+		steps[i] = keepStep(commit)
+		if options.Dates != DatesCommitter {
+			steps[i].authorDate = commit.AuthorDate.Add(offset)
+		}
+		if options.Dates != DatesAuthor {
+			steps[i].committerDate = commit.CommitterDate.Add(offset)
+		}
+	}
+
+	if err := a.validateShiftChronology(steps, offset); err != nil {
+		return err
+	}
+
+	return a.applyRewrite("shift", target, steps, options)
+}
+
+// validateShiftChronology checks that the commits whose parents lie outside
+// the range, the first ones rewritten, still follow those parents. Within
+// the range every date moves alike, so the order is kept.
+func (a *App) validateShiftChronology(steps []rewriteStep, offset time.Duration) error {
+	basis := a.config.GetChronologyBasis()
+
+	inRange := make(map[string]bool, len(steps))
+	var outside []string
+	for _, step := range steps {
+		inRange[step.commit.Hash] = true
+	}
+	for _, step := range steps {
+		for _, parent := range step.commit.Parents {
+			if !inRange[parent] {
+				outside = append(outside, parent)
+			}
+		}
+	}
+
+	parents, err := git.GetCommitsDates(outside)
+	if err != nil {
+		return NewRewriteError(err.Error())
+	}
+	parentDates := make(map[string]time.Time, len(parents))
+	for _, parent := range parents {
+		parentDates[parent.Hash] = basis.Select(parent.AuthorDate, parent.CommitterDate)
+	}
+
+	for _, step := range steps {
+		date := basis.Select(step.authorDate, step.committerDate)
+		for _, parent := range step.commit.Parents {
+			parentDate, ok := parentDates[parent]
+			if !ok || date.After(parentDate) {
+				continue
+			}
+
+			// The largest backward shift that keeps this commit after its parent
+			original := basis.Select(step.commit.AuthorDate, step.commit.CommitterDate)
+			limit := original.Sub(parentDate) - time.Second
+			slog.Error("Shifted commit would precede its parent",
+				"commit", step.commit.Hash, "date", date, "parent", parent, "parentDate", parentDate)
+			return NewShiftChronologyError(step.commit, date, parent, parentDate, offset, limit)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"log/slog"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// resolveSigning decides whether the commit is signed: -S always signs,
+// --no-gpg-sign never does, otherwise commit.gpgsign decides as it does for git.
+func (a *App) resolveSigning(request *CommitRequest) error {
+	config, err := git.GetSigningConfig()
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+
+	request.Sign = a.config.Sign.Enabled || (config.Sign && !a.config.NoSign)
+	request.SigningFormat = config.Format
+	slog.Debug("Signing resolved",
+		"sign", request.Sign,
+		"format", config.Format,
+		"key", a.config.Sign.KeyID)
+	return nil
+}
+
+// verifySignature checks that the signed commit at HEAD carries a valid signature.
+// A signature git cannot check (e.g. no gpg.ssh.allowedSignersFile) only logs a warning.
+func verifySignature(signingFormat string) (git.Signature, error) {
+	signature, err := git.VerifyCommitSignature("HEAD")
+	if err != nil {
+		return git.Signature{}, NewGitCommandError(err.Error())
+	}
+
+	switch {
+	case signature.Verified():
+		slog.Debug("Signature verified", "signer", signature.Signer, "key", signature.Key)
+		return signature, nil
+	case signature.Present && (signature.Status == "E" || signature.Status == "N"):
+		slog.Warn("Commit is signed but the signature could not be verified",
+			"format", signingFormat,
+			"hint", verificationHint(signingFormat))
+		return signature, nil
+	default:
+		slog.Error("Signature verification failed", "status", signature.Status, "present", signature.Present)
+		return signature, NewSignatureVerificationError(signature)
+	}
+}
+
+// verificationHint explains how to let git verify signatures of the given format.
+func verificationHint(format string) string {
+	if format == git.SignatureFormatSSH {
+		return "list your public key in the file set by gpg.ssh.allowedSignersFile"
+	}
+	return "import your public key into the keyring used by gpg"
+}
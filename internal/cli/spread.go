@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+const (
+	// spreadUsage is the argument synopsis of the spread command.
+	spreadUsage = "[flags] <range> --from <date> --to <date>"
+
+	// DistributionEven spaces the commits evenly over the window.
+	DistributionEven = "even"
+
+	// DistributionWorkingHours spaces the commits evenly over the working
+	// hours of the window.
+	DistributionWorkingHours = "working-hours"
+
+	// DistributionRandom draws the dates at random, reproducibly from a seed.
+	DistributionRandom = "random"
+
+	// WorkingHoursConfigKey is the git config key holding the working hours, as "HH:MM-HH:MM".
+	WorkingHoursConfigKey = "gitcommit.workingHours"
+
+	// WorkingDaysConfigKey is the git config key holding the working days, such as "mon-fri".
+	WorkingDaysConfigKey = "gitcommit.workingDays"
+
+	// HolidayConfigKey is the multi-valued git config key holding the days, as
+	// "YYYY-MM-DD", on which no commit is dated.
+	HolidayConfigKey = "gitcommit.holiday"
+)
+
+// SpreadOptions holds the window and distribution of gitcommit spread.
+type SpreadOptions struct {
+	// From and To bound the window, both included.
+	From, To string
+
+	// Distribution is one of DistributionEven, DistributionWorkingHours and DistributionRandom.
+	Distribution string
+
+	// Seed drives DistributionRandom; SeedSet tells whether it was given.
+	Seed    uint64
+	SeedSet bool
+}
+
+// RunSpread implements "gitcommit spread <range> --from <date> --to <date>".
+func RunSpread(args []string) error {
+	config := NewConfig("")
+	spread := SpreadOptions{}
+	options := RewriteOptions{}
+
+	flags := flag.NewFlagSet("spread", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, SpreadHelpText()) }
+	flags.StringVar(&spread.From, "from", "", "Start of the window")
+	flags.StringVar(&spread.To, "to", "", "End of the window")
+	flags.StringVar(&spread.Distribution, "distribution", DistributionEven, "How dates are placed: even, working-hours or random")
+	flags.Uint64Var(&spread.Seed, "seed", 0, "Seed of the random distribution")
+	flags.StringVar(&options.Dates, "dates", DatesBoth, "Dates to rewrite: author, committer or both")
+	flags.BoolVar(&options.DryRun, "dry-run", false, "Show the old and new dates without rewriting")
+	flags.BoolVar(&options.Force, "force", false, "Rewrite commits that are on a remote-tracking branch")
+	flags.BoolVar(&options.UpdateBranches, "update-branches", false,
+		"Also rewrite the other local branches that contain rewritten commits")
+	flags.BoolVar(&options.Resign, "resign", false, "Sign the rewritten commits with the configured key")
+	flags.StringVar(&config.ChronologyBasis, "chronology-basis", config.ChronologyBasis,
+		"Date of the parents to check against: author, committer or max")
+	positional := ParseInterspersed(flags, args)
+
+	if len(positional) != 1 {
+		return NewCommandArgumentsError("spread", spreadUsage, len(positional))
+	}
+
+	flags.Visit(func(f *flag.Flag) { spread.SeedSet = spread.SeedSet || f.Name == "seed" })
+	if err := validateSpreadOptions(spread); err != nil {
+		return err
+	}
+	if !isDatesSelection(options.Dates) {
+		return NewInvalidDatesSelectionError(options.Dates)
+	}
+	if _, err := datetime.ParseChronologyBasis(config.ChronologyBasis); err != nil {
+		return NewInvalidChronologyBasisError(config.ChronologyBasis)
+	}
+
+	options.Input = "--from " + spread.From + " --to " + spread.To
+	return NewApp(config).Spread(positional[0], spread, options)
+}
+
+// validateSpreadOptions checks the window is given and the seed goes with
+// the random distribution.
+func validateSpreadOptions(spread SpreadOptions) error {
+	switch {
+	case spread.From == "" || spread.To == "":
+		return NewInvalidPlanError("spread", "Give the window with --from and --to.")
+	case spread.Distribution != DistributionEven && spread.Distribution != DistributionWorkingHours &&
+		spread.Distribution != DistributionRandom:
+		return NewInvalidDistributionError(spread.Distribution)
+	case spread.SeedSet && spread.Distribution != DistributionRandom:
+		return NewConflictingOptionsError("--seed", "--distribution="+spread.Distribution+" (it requires random)")
+	}
+	return nil
+}
+
+// Spread dates the commits in rangeArg across the window of spread, in
+// order and strictly increasing, skipping configured holidays and, for the
+// working-hours distribution or when working hours are configured, the time
+// outside working hours.
+func (a *App) Spread(rangeArg string, spread SpreadOptions, options RewriteOptions) error {
+	slog.Info("Processing spread request", "range", rangeArg, "from", spread.From, "to", spread.To,
+		"distribution", spread.Distribution)
+
+	if !git.IsGitRepository() {
+		slog.Error("Not in a Git repository")
+		return NewNoRepositoryError()
+	}
+
+	from, err := a.parseDate(spread.From)
+	if err != nil {
+		return err
+	}
+	to, err := a.parseDate(spread.To)
+	if err != nil {
+		return err
+	}
+	if !to.After(from) {
+		return NewInvalidPlanError("spread", fmt.Sprintf("The window ends (%s) before it starts (%s).", spread.To, spread.From))
+	}
+
+	calendar, err := spreadCalendar(spread.Distribution)
+	if err != nil {
+		return err
+	}
+
+	target, err := resolveRewriteTarget(rangeArg)
+	if err != nil {
+		return err
+	}
+
+	var dates []time.Time
+	if spread.Distribution == DistributionRandom {
+		if !spread.SeedSet {
+			spread.Seed = uint64(time.Now().UnixNano())
+		}
+		fmt.Println(FormatSpreadSeedMessage(spread.Seed))
+		dates, err = calendar.SpreadRandomly(from, to, len(target.commits), spread.Seed)
+	} else {
+		dates, err = calendar.SpreadEvenly(from, to, len(target.commits))
+	}
+	if err != nil {
+		slog.Error("Window too small for the range", "commits", len(target.commits), "error", err)
+		return NewSpreadWindowError(spread.From, spread.To, len(target.commits))
+	}
+
+	steps := make([]rewriteStep, len(target.commits))
+	for i, commit := range target.commits {
+		steps[i] = dateStep(commit, dates[i], options.Dates)
+	}
+
+	if err := a.validateRewriteChronology(steps); err != nil {
+		return err
+	}
+
+	return a.applyRewrite("spread", target, steps, options)
+}
+
+// spreadCalendar reads the holidays and working-hours policies from git
+// config. Working hours apply to the working-hours distribution, with
+// defaults, and to every distribution once configured.
+func spreadCalendar(distribution string) (datetime.Calendar, error) {
+	calendar := datetime.Calendar{WorkingHours: distribution == DistributionWorkingHours}
+
+	hours, err := git.GetConfig(WorkingHoursConfigKey)
+	if err != nil {
+		return calendar, NewGitCommandError(err.Error())
+	}
+	days, err := git.GetConfig(WorkingDaysConfigKey)
+	if err != nil {
+		return calendar, NewGitCommandError(err.Error())
+	}
+	holidays, err := git.GetConfigAll(HolidayConfigKey)
+	if err != nil {
+		return calendar, NewGitCommandError(err.Error())
+	}
+
+	if hours != "" || days != "" {
+		calendar.WorkingHours = true
+	}
+	if hours == "" {
+		hours = datetime.DefaultWorkingHours
+	}
+	if days == "" {
+		days = datetime.DefaultWorkingDays
+	}
+
+	if calendar.DayStart, calendar.DayEnd, err = datetime.ParseWorkingHours(hours); err != nil {
+		return calendar, NewInvalidConfigError(WorkingHoursConfigKey, hours, "HH:MM-HH:MM, e.g. 09:00-17:00")
+	}
+	if calendar.WorkingDays, err = datetime.ParseWorkingDays(days); err != nil {
+		return calendar, NewInvalidConfigError(WorkingDaysConfigKey, days, "weekdays and ranges, e.g. mon-fri or mon,tue,thu")
+	}
+	if calendar.Holidays, err = datetime.ParseHolidays(holidays); err != nil {
+		return calendar, NewInvalidConfigError(HolidayConfigKey, err.Error(), "one YYYY-MM-DD day per value")
+	}
+
+	slog.Debug("Spread calendar", "workingHours", calendar.WorkingHours, "hours", hours, "days", days,
+		"holidays", len(calendar.Holidays))
+	return calendar, nil
+}
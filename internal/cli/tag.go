@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// tagUsage is the argument synopsis of the tag command.
+const tagUsage = "[flags] <date> <name> [<rev>] (-m <message>... | -F <file>)"
+
+// tagArguments is the largest number of positional arguments of the tag command.
+const tagArguments = 3
+
+// TagOptions holds the settings of the tag command that commits do not share.
+type TagOptions struct {
+	// Sign signs the tag (-s), with SignKey if set (-u).
+	Sign bool
+
+	// SignKey selects the signing key instead of user.signingkey.
+	SignKey string
+
+	// NoSign disables signing even when tag.gpgSign is set.
+	NoSign bool
+}
+
+// RunTag implements "gitcommit tag <date> <name> [<rev>]".
+func RunTag(args []string) error {
+	config := NewConfig("")
+	options := TagOptions{}
+
+	flags := flag.NewFlagSet("tag", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, TagHelpText()) }
+	flags.Var(&config.Messages, "m", "Tag message paragraph (repeatable)")
+	flags.StringVar(&config.MessageFile, "F", "", "Read the tag message from a file (- for standard input)")
+	flags.BoolVar(&options.Sign, "s", false, "Sign the tag with the default key")
+	flags.StringVar(&options.SignKey, "u", "", "Sign the tag with the given key")
+	flags.BoolVar(&options.NoSign, "no-sign", false, "Do not sign the tag, even if tag.gpgSign is set")
+	flags.BoolVar(&config.Force, "f", false, "Replace an existing tag of the same name")
+	flags.BoolVar(&config.ShowGitOutput, "show-git-output", false, "Echo git's own output, including on failure")
+	positional := ParseInterspersed(flags, args)
+
+	if len(positional) < RequiredArguments || len(positional) > tagArguments {
+		return NewCommandArgumentsError("tag", tagUsage, len(positional))
+	}
+
+	if err := config.validateMessageSources(); err != nil {
+		return err
+	}
+	if !config.hasMessageFlag() {
+		return NewTagMessageRequiredError()
+	}
+	if (options.Sign || options.SignKey != "") && options.NoSign {
+		return NewConflictingOptionsError("-s", "--no-sign")
+	}
+
+	rev := "HEAD"
+	if len(positional) == tagArguments {
+		rev = positional[2]
+	}
+	return NewApp(config).Tag(positional[0], positional[1], rev, options)
+}
+
+// Tag creates the annotated tag name on rev with tagger date dateStr, which
+// must not be earlier than the committer date of the tagged commit.
+func (a *App) Tag(dateStr, name, rev string, options TagOptions) error {
+	slog.Info("Processing tag request", "date", dateStr, "name", name, "rev", rev)
+
+	if !git.IsGitRepository() {
+		slog.Error("Not in a Git repository")
+		return NewNoRepositoryError()
+	}
+
+	parsedDate, err := a.parseDate(dateStr)
+	if err != nil {
+		return err
+	}
+
+	hash, err := git.ResolveCommit(rev)
+	if err != nil {
+		return NewUnknownRefError(rev)
+	}
+	tagged, err := git.GetCommitDates(hash)
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+	if parsedDate.Before(tagged.CommitterDate) {
+		slog.Error("Tag date precedes the tagged commit",
+			"date", parsedDate, "committerDate", tagged.CommitterDate)
+		return NewTagChronologyError(dateStr, tagged)
+	}
+
+	message, source, err := a.initialMessage(git.OperationNone)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(message) == "" {
+		return NewEmptyMessageError(source)
+	}
+
+	gitFormattedDate := datetime.FormatForGit(parsedDate)
+	tagOptions := git.TagOptions{
+		Sign:       options.Sign,
+		SignKey:    options.SignKey,
+		NoSign:     options.NoSign,
+		Force:      a.config.Force,
+		ShowOutput: a.config.ShowGitOutput,
+	}
+	var backup git.Backup
+	if git.IsValidTagName(name) && (a.config.Force || !git.TagExists(name)) {
+		if backup, err = backupRefs("gitcommit tag: "+name, "refs/tags/"+name); err != nil {
+			return err
+		}
+	}
+	if err := git.CreateTag(gitFormattedDate, name, hash, message, tagOptions); err != nil {
+		slog.Error("Git tag failed", "error", err)
+		discardUnusedBackup(backup)
+		return tagFailureError(err, name)
+	}
+
+	a.recordJournal(git.JournalEntry{Operation: "tag", InputDate: dateStr, Date: parsedDate}, backup)
+
+	fmt.Println(FormatTagSuccessMessage(name, hash, gitFormattedDate))
+	slog.Info("Tag created successfully")
+	return nil
+}
+
+// tagFailureError converts a failed git tag into a UserError.
+func tagFailureError(err error, name string) *UserError {
+	switch {
+	case errors.Is(err, git.ErrInvalidTagName):
+		return NewInvalidTagNameError(name)
+	case errors.Is(err, git.ErrTagExists):
+		return NewTagExistsError(name)
+	case errors.Is(err, git.ErrTagSigningFailed):
+		signing, configErr := git.GetSigningConfig()
+		if configErr != nil {
+			slog.Warn("Could not read the signing configuration", "error", configErr)
+		}
+		return NewTagSigningFailedError(signing.Format, err.Error())
+	default:
+		return NewGitCommandError(err.Error())
+	}
+}
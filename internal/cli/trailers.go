@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"log/slog"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// trailers returns the trailers requested on the command line: co-authors,
+// then --trailer values, then provenance in trailer mode, then the sign-off
+// of the committer, which by convention comes last.
+func (a *App) trailers(request *CommitRequest) []git.Trailer {
+	trailers := make([]git.Trailer, 0, len(a.config.CoAuthors)+len(a.config.Trailers)+4)
+
+	// Values were validated with the configuration
+	for _, coAuthor := range a.config.CoAuthors {
+		identity, _ := git.ParseIdentity(coAuthor)
+		trailers = append(trailers, git.Trailer{Key: git.CoAuthoredByKey, Value: identity.String()})
+	}
+	for _, value := range a.config.Trailers {
+		trailer, _ := git.ParseTrailer(value)
+		trailers = append(trailers, trailer)
+	}
+	if request.ProvenanceMode == ProvenanceTrailer {
+		trailers = append(trailers, a.provenance(request).Trailers()...)
+	}
+	if a.config.Signoff {
+		trailers = append(trailers, git.Trailer{Key: git.SignedOffByKey, Value: request.Committer.String()})
+	}
+
+	return trailers
+}
+
+// applyTrailers appends the requested trailers to the commit message,
+// skipping any already present. When amending without a new message,
+// the trailers are added to HEAD's message.
+func (a *App) applyTrailers(request *CommitRequest) error {
+	trailers := a.trailers(request)
+	if len(trailers) == 0 {
+		return nil
+	}
+
+	message := request.CommitMessage
+	if message == "" && a.config.Amend {
+		headMessage, err := git.GetCommitMessage("HEAD")
+		if err != nil {
+			return NewGitCommandError(err.Error())
+		}
+		message = headMessage
+	}
+
+	withTrailers, err := git.AddTrailers(message, trailers)
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+
+	request.CommitMessage = withTrailers
+	slog.Debug("Trailers applied", "trailers", len(trailers))
+	return nil
+}
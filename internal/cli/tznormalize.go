@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/gitcommit/internal/datetime"
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+const (
+	// tzNormalizeUsage is the argument synopsis of the tz-normalize command.
+	tzNormalizeUsage = "[flags] <range> (--to <zone> | --map <file>)"
+
+	// ZoneMapConfigKey is the git config key naming the default zone map file.
+	ZoneMapConfigKey = "gitcommit.zoneMap"
+
+	// zoneOffsetLayout formats a zone offset the way git stores it: "+0100".
+	zoneOffsetLayout = "-0700"
+)
+
+// ZoneChange describes the zone offsets a commit has and would get, for
+// FormatZoneReportMessage.
+type ZoneChange struct {
+	Hash    string
+	Subject string
+
+	// AuthorBefore, AuthorAfter, CommitterBefore and CommitterAfter are
+	// offsets such as "+0100".
+	AuthorBefore, AuthorAfter       string
+	CommitterBefore, CommitterAfter string
+}
+
+// zoneMap gives the zone of each author or committer, by lowercase email.
+type zoneMap map[string]*time.Location
+
+// RunTZNormalize implements "gitcommit tz-normalize <range> --to <zone>".
+func RunTZNormalize(args []string) error {
+	config := NewConfig("")
+	options := RewriteOptions{}
+	var zone, mapFile string
+
+	flags := flag.NewFlagSet("tz-normalize", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, TZNormalizeHelpText()) }
+	flags.StringVar(&zone, "to", "", "Zone for authors not in the map: +0100, UTC or Europe/Paris")
+	flags.StringVar(&mapFile, "map", "", "File of \"<email> <zone>\" lines (default: git config "+ZoneMapConfigKey+")")
+	flags.StringVar(&options.Dates, "dates", DatesBoth, "Dates to normalize: author, committer or both")
+	flags.BoolVar(&options.DryRun, "dry-run", false, "Report the commits that would change without rewriting")
+	flags.BoolVar(&options.Force, "force", false, "Rewrite commits that are on a remote-tracking branch")
+	flags.BoolVar(&options.UpdateBranches, "update-branches", false,
+		"Also rewrite the other local branches that contain rewritten commits")
+	flags.BoolVar(&options.Resign, "resign", false, "Sign the rewritten commits with the configured key")
+	positional := ParseInterspersed(flags, args)
+
+	if len(positional) != 1 {
+		return NewCommandArgumentsError("tz-normalize", tzNormalizeUsage, len(positional))
+	}
+	if !isDatesSelection(options.Dates) {
+		return NewInvalidDatesSelectionError(options.Dates)
+	}
+
+	if zone != "" {
+		options.Input = "--to " + zone
+	}
+	if mapFile != "" {
+		options.Input = strings.TrimSpace(options.Input + " --map " + mapFile)
+	}
+	return NewApp(config).TZNormalize(positional[0], zone, mapFile, options)
+}
+
+// TZNormalize rewrites the zone offsets of the dates of the commits in
+// rangeArg, keeping their instants: each identity gets its zone from the
+// map file, falling back to zone. Identities with neither keep their offsets.
+func (a *App) TZNormalize(rangeArg, zone, mapFile string, options RewriteOptions) error {
+	slog.Info("Processing tz-normalize request", "range", rangeArg, "zone", zone, "map", mapFile)
+
+	if !git.IsGitRepository() {
+		slog.Error("Not in a Git repository")
+		return NewNoRepositoryError()
+	}
+
+	var fallback *time.Location
+	if zone != "" {
+		location, err := datetime.ParseZone(zone)
+		if err != nil {
+			return NewInvalidZoneError("--to", zone)
+		}
+		fallback = location
+	}
+
+	if mapFile == "" {
+		configured, err := git.GetConfigPath(ZoneMapConfigKey)
+		if err != nil {
+			return NewGitCommandError(err.Error())
+		}
+		mapFile = configured
+	}
+	zones := zoneMap{}
+	if mapFile != "" {
+		var err error
+		if zones, err = readZoneMap(mapFile); err != nil {
+			return err
+		}
+	}
+	if fallback == nil && len(zones) == 0 {
+		return NewInvalidPlanError("tz-normalize",
+			"Give the zone with --to, or a zone map with --map or git config "+ZoneMapConfigKey+".")
+	}
+
+	target, err := resolveRewriteTarget(rangeArg)
+	if err != nil {
+		return err
+	}
+
+	steps := make([]rewriteStep, len(target.commits))
+	for i, commit := range target.commits {
+		steps[i] = keepStep(commit)
+		if location := zones.zoneOf(commit.Author, fallback); location != nil && options.Dates != DatesCommitter {
+			steps[i].authorDate = commit.AuthorDate.In(location)
+		}
+		if location := zones.zoneOf(commit.Committer, fallback); location != nil && options.Dates != DatesAuthor {
+			steps[i].committerDate = commit.CommitterDate.In(location)
+		}
+	}
+
+	if options.DryRun {
+		fmt.Println(FormatZoneReportMessage(zoneChanges(steps), len(steps)))
+		return a.showRewriteEffects(target, steps, options)
+	}
+
+	return a.applyRewrite("tz-normalize", target, steps, options)
+}
+
+// zoneOf returns the zone of identity, or fallback when it is not mapped.
+func (z zoneMap) zoneOf(identity git.Identity, fallback *time.Location) *time.Location {
+	if location, ok := z[strings.ToLower(identity.Email)]; ok {
+		return location
+	}
+	return fallback
+}
+
+// readZoneMap reads "<email> <zone>" or "Name <email> <zone>" lines,
+// skipping blank lines and comments.
+func readZoneMap(path string) (zoneMap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, NewInvalidPlanError("tz-normalize", "Cannot read the zone map: "+err.Error())
+	}
+	defer file.Close()
+
+	zones := zoneMap{}
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, planComment) {
+			continue
+		}
+
+		origin := fmt.Sprintf("%s:%d", path, number)
+		separator := strings.LastIndexAny(line, " \t")
+		if separator < 0 {
+			return nil, NewInvalidPlanError("tz-normalize", origin+": expected \"<email> <zone>\", got "+line)
+		}
+		who, zone := strings.TrimSpace(line[:separator]), line[separator+1:]
+
+		email := who
+		if strings.Contains(who, "<") {
+			identity, err := git.ParseIdentity(who)
+			if err != nil {
+				return nil, NewInvalidPlanError("tz-normalize", fmt.Sprintf("%s: invalid identity %q.", origin, who))
+			}
+			email = identity.Email
+		}
+		location, err := datetime.ParseZone(zone)
+		if err != nil {
+			return nil, NewInvalidZoneError(origin, zone)
+		}
+		zones[strings.ToLower(email)] = location
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewInvalidPlanError("tz-normalize", "Cannot read the zone map: "+err.Error())
+	}
+	return zones, nil
+}
+
+// zoneChanges describes the steps that change a zone offset.
+func zoneChanges(steps []rewriteStep) []ZoneChange {
+	var changes []ZoneChange
+	for _, step := range steps {
+		if !step.changed() {
+			continue
+		}
+		changes = append(changes, ZoneChange{
+			Hash:            step.commit.Hash,
+			Subject:         step.commit.Subject(),
+			AuthorBefore:    step.commit.AuthorDate.Format(zoneOffsetLayout),
+			AuthorAfter:     step.authorDate.Format(zoneOffsetLayout),
+			CommitterBefore: step.commit.CommitterDate.Format(zoneOffsetLayout),
+			CommitterAfter:  step.committerDate.Format(zoneOffsetLayout),
+		})
+	}
+	return changes
+}
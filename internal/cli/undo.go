@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/sgaunet/gitcommit/internal/git"
+)
+
+// undoUsage is the argument synopsis of the undo command.
+const undoUsage = "[--soft] [<id>] | --list | --prune"
+
+// RefChange is a ref moved by gitcommit undo. An empty hash means the ref
+// does not exist.
+type RefChange struct {
+	Name     string
+	Old, New string
+}
+
+// RunUndo implements "gitcommit undo [--list] [<id>]".
+func RunUndo(args []string) error {
+	config := NewConfig("")
+	var list, prune, soft bool
+
+	flags := flag.NewFlagSet("undo", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, UndoHelpText()) }
+	flags.BoolVar(&list, "list", false, "List the backups, newest first")
+	flags.BoolVar(&prune, "prune", false, "Delete the backups past the configured age or count")
+	flags.BoolVar(&soft, "soft", false, "Move the current branch without touching the index and working tree")
+	positional := ParseInterspersed(flags, args)
+
+	if len(positional) > 1 || (len(positional) == 1 && (list || prune)) {
+		return NewCommandArgumentsError("undo", undoUsage, len(positional))
+	}
+	switch {
+	case list && prune:
+		return NewConflictingOptionsError("--list", "--prune")
+	case soft && (list || prune):
+		return NewConflictingOptionsError("--soft", "--list or --prune")
+	}
+
+	if !git.IsGitRepository() {
+		return NewNoRepositoryError()
+	}
+
+	switch {
+	case list:
+		return ShowBackups()
+	case prune:
+		return PruneBackups()
+	}
+
+	id := ""
+	if len(positional) == 1 {
+		id = positional[0]
+	}
+	return NewApp(config).Undo(id, soft)
+}
+
+// Undo restores the refs saved by the backup whose ID starts with id, or by
+// the latest backup when id is empty. The current branch is restored with
+// git reset --keep unless soft is set; other refs are only moved. The values
+// replaced are backed up first, so an undo can itself be undone.
+func (a *App) Undo(id string, soft bool) error {
+	slog.Info("Processing undo request", "id", id, "soft", soft)
+
+	backup, err := findUndoBackup(id)
+	if err != nil {
+		return err
+	}
+	if err := checkNoOperationInProgress(); err != nil {
+		return err
+	}
+	head, err := headRef()
+	if err != nil {
+		return err
+	}
+
+	var changes []RefChange
+	var names []string
+	for _, saved := range backup.Refs {
+		current := git.ResolveRef(saved.Name)
+		if current == saved.Hash {
+			continue
+		}
+		if saved.Name == "HEAD" && head != "HEAD" {
+			return NewUndoDetachedHeadError(saved.Hash)
+		}
+		changes = append(changes, RefChange{Name: saved.Name, Old: current, New: saved.Hash})
+		names = append(names, saved.Name)
+	}
+	if len(changes) == 0 {
+		fmt.Println(FormatUndoUnchangedMessage(backup))
+		return nil
+	}
+
+	reason := "gitcommit undo: " + backup.ID
+	saved, err := backupRefs(reason, names...)
+	if err != nil {
+		return err
+	}
+	if err := restoreRefs(changes, head, soft, reason); err != nil {
+		discardUnusedBackup(saved)
+		return err
+	}
+	a.recordJournal(git.JournalEntry{Operation: "undo", Restored: backup.ID}, saved)
+
+	fmt.Println(FormatUndoSuccessMessage(backup, changes))
+	slog.Info("Backup restored", "id", backup.ID, "refs", len(changes))
+	return nil
+}
+
+// ShowBackups prints every backup, newest first.
+func ShowBackups() error {
+	backups, err := git.ListBackups()
+	if err != nil {
+		return NewGitCommandError(err.Error())
+	}
+	fmt.Println(FormatBackupListMessage(backups))
+	return nil
+}
+
+// PruneBackups deletes the backups past the configured age or count.
+func PruneBackups() error {
+	pruned, err := pruneBackups("")
+	if err != nil {
+		return err
+	}
+	fmt.Println(FormatBackupsPrunedMessage(pruned))
+	return nil
+}
+
+// findUndoBackup returns the backup whose ID starts with id, or the latest
+// one when id is empty.
+func findUndoBackup(id string) (git.Backup, error) {
+	if id != "" {
+		backup, err := git.FindBackup(id)
+		switch {
+		case errors.Is(err, git.ErrBackupNotFound):
+			return backup, NewUnknownBackupError(id, "no backup has this ID")
+		case errors.Is(err, git.ErrAmbiguousBackup):
+			return backup, NewUnknownBackupError(id, "several backups start with it")
+		case err != nil:
+			return backup, NewGitCommandError(err.Error())
+		}
+		return backup, nil
+	}
+
+	backups, err := git.ListBackups()
+	if err != nil {
+		return git.Backup{}, NewGitCommandError(err.Error())
+	}
+	if len(backups) == 0 {
+		return git.Backup{}, NewNoBackupError()
+	}
+	return backups[0], nil
+}
+
+// restoreRefs points the refs of changes back at their values, or deletes
+// them, in one transaction. Unless soft, the checked-out branch head then
+// moves with git reset --keep, so that the index and working tree follow;
+// if that fails, the other refs are moved back, so either every ref is
+// restored or none is.
+func restoreRefs(changes []RefChange, head string, soft bool, reason string) error {
+	var updates []git.RefUpdate
+	var reset *RefChange
+	for _, change := range changes {
+		if change.Name == head && !soft && change.Old != "" && change.New != "" {
+			reset = &change
+			continue
+		}
+		updates = append(updates, git.RefUpdate{Name: change.Name, Old: change.Old, New: change.New})
+	}
+
+	if len(updates) > 0 {
+		if err := git.UpdateRefs(updates, reason); err != nil {
+			slog.Error("Restoring refs failed", "refs", len(updates), "error", err)
+			return NewUndoError(refUpdateNames(updates), err.Error())
+		}
+	}
+	if reset == nil {
+		return nil
+	}
+
+	if err := git.ResetKeep(reset.New, reason); err != nil {
+		slog.Error("Restoring ref failed", "ref", reset.Name, "error", err)
+		rollback := make([]git.RefUpdate, len(updates))
+		for i, update := range updates {
+			rollback[i] = git.RefUpdate{Name: update.Name, Old: update.New, New: update.Old}
+		}
+		if len(rollback) > 0 {
+			if rollbackErr := git.UpdateRefs(rollback, reason+" (rolled back)"); rollbackErr != nil {
+				slog.Error("Rolling back restored refs failed", "error", rollbackErr)
+			}
+		}
+		return NewUndoError(reset.Name, err.Error())
+	}
+	return nil
+}
+
+// refUpdateNames lists the names of the refs of updates, for messages.
+func refUpdateNames(updates []git.RefUpdate) string {
+	names := make([]string, len(updates))
+	for i, update := range updates {
+		names[i] = update.Name
+	}
+	return strings.Join(names, ", ")
+}
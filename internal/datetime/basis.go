@@ -0,0 +1,75 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ChronologyBasis selects which date of the last commit a new commit is checked against.
+type ChronologyBasis string
+
+const (
+	// BasisAuthor compares against the author date (%aI) of the last commit.
+	BasisAuthor ChronologyBasis = "author"
+
+	// BasisCommitter compares against the committer date (%cI) of the last commit.
+	BasisCommitter ChronologyBasis = "committer"
+
+	// BasisMax compares against the later of the author and committer dates.
+	// This is the strictest basis and the default.
+	BasisMax ChronologyBasis = "max"
+
+	// DefaultChronologyBasis is the basis used when none is specified.
+	DefaultChronologyBasis = BasisMax
+)
+
+var (
+	// ErrInvalidChronologyBasis is returned when a chronology basis is not recognized.
+	ErrInvalidChronologyBasis = errors.New("invalid chronology basis")
+)
+
+// ParseChronologyBasis parses a chronology basis name.
+// An empty string yields DefaultChronologyBasis.
+func ParseChronologyBasis(s string) (ChronologyBasis, error) {
+	switch ChronologyBasis(s) {
+	case "":
+		return DefaultChronologyBasis, nil
+	case BasisAuthor, BasisCommitter, BasisMax:
+		return ChronologyBasis(s), nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidChronologyBasis, s)
+	}
+}
+
+// Select returns the date a new commit must follow, given the author and
+// committer dates of the last commit.
+func (b ChronologyBasis) Select(authorDate, committerDate time.Time) time.Time {
+	switch b {
+	case BasisAuthor:
+		return authorDate
+	case BasisCommitter:
+		return committerDate
+	case BasisMax:
+		fallthrough
+	default:
+		if committerDate.After(authorDate) {
+			return committerDate
+		}
+		return authorDate
+	}
+}
+
+// Describe returns a short human-readable description of the basis.
+func (b ChronologyBasis) Describe() string {
+	switch b {
+	case BasisAuthor:
+		return "author date"
+	case BasisCommitter:
+		return "committer date"
+	case BasisMax:
+		fallthrough
+	default:
+		return "latest of author and committer dates"
+	}
+}
@@ -0,0 +1,69 @@
+package datetime
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseChronologyBasis tests parsing of chronology basis names.
+func TestParseChronologyBasis(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    ChronologyBasis
+		expectError bool
+	}{
+		{name: "author", input: "author", expected: BasisAuthor},
+		{name: "committer", input: "committer", expected: BasisCommitter},
+		{name: "max", input: "max", expected: BasisMax},
+		{name: "empty defaults to max", input: "", expected: BasisMax},
+		{name: "unknown basis", input: "newest", expectError: true},
+		{name: "wrong case", input: "Author", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			basis, err := ParseChronologyBasis(tt.input)
+
+			if tt.expectError {
+				if !errors.Is(err, ErrInvalidChronologyBasis) {
+					t.Errorf("ParseChronologyBasis(%q) error = %v, expected ErrInvalidChronologyBasis", tt.input, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseChronologyBasis(%q) unexpected error: %v", tt.input, err)
+			}
+			if basis != tt.expected {
+				t.Errorf("ParseChronologyBasis(%q) = %q, expected %q", tt.input, basis, tt.expected)
+			}
+		})
+	}
+}
+
+// TestChronologyBasisSelect tests which date each basis selects.
+func TestChronologyBasisSelect(t *testing.T) {
+	tests := []struct {
+		name          string
+		basis         ChronologyBasis
+		authorDate    string
+		committerDate string
+		expected      string
+	}{
+		{"author basis", BasisAuthor, "2025-01-10 09:00:00", "2025-03-01 18:30:00", "2025-01-10 09:00:00"},
+		{"committer basis", BasisCommitter, "2025-01-10 09:00:00", "2025-03-01 18:30:00", "2025-03-01 18:30:00"},
+		{"max picks committer", BasisMax, "2025-01-10 09:00:00", "2025-03-01 18:30:00", "2025-03-01 18:30:00"},
+		{"max picks author", BasisMax, "2025-03-01 18:30:00", "2025-01-10 09:00:00", "2025-03-01 18:30:00"},
+		{"max with equal dates", BasisMax, "2025-01-10 09:00:00", "2025-01-10 09:00:00", "2025-01-10 09:00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.basis.Select(parseTestDate(tt.authorDate), parseTestDate(tt.committerDate))
+			if !result.Equal(parseTestDate(tt.expected)) {
+				t.Errorf("%s.Select() = %v, expected %s", tt.basis, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DayLayout is the format of holidays: "2025-12-25".
+	DayLayout = "2006-01-02"
+
+	// clockLayout is the format of the bounds of working hours: "09:00".
+	clockLayout = "15:04"
+
+	// DefaultWorkingHours is the working day used when none is configured.
+	DefaultWorkingHours = "09:00-17:00"
+
+	// DefaultWorkingDays are the working days used when none are configured.
+	DefaultWorkingDays = "mon-fri"
+)
+
+var (
+	// ErrInvalidWorkingHours is returned when working hours are not "HH:MM-HH:MM".
+	ErrInvalidWorkingHours = errors.New("invalid working hours")
+
+	// ErrInvalidWorkingDays is returned when working days are not a list of weekdays.
+	ErrInvalidWorkingDays = errors.New("invalid working days")
+
+	// ErrInvalidHoliday is returned when a holiday is not a "YYYY-MM-DD" date.
+	ErrInvalidHoliday = errors.New("invalid holiday")
+
+	// ErrWindowTooSmall is returned when a window has fewer free seconds than dates to place.
+	ErrWindowTooSmall = errors.New("window too small")
+)
+
+// weekdayNames maps the three-letter names accepted in working days to weekdays.
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Calendar describes when commits may be dated. The zero Calendar allows
+// any time of any day.
+type Calendar struct {
+	// WorkingHours restricts dates to working hours on working days.
+	WorkingHours bool
+
+	// DayStart and DayEnd bound the working hours, as offsets from midnight.
+	DayStart, DayEnd time.Duration
+
+	// WorkingDays are the days worked when WorkingHours is set.
+	WorkingDays [7]bool
+
+	// Holidays are the days, as "YYYY-MM-DD", on which no commit is dated.
+	Holidays map[string]bool
+}
+
+// ParseWorkingHours parses working hours such as "09:00-17:00" into offsets
+// from midnight.
+func ParseWorkingHours(s string) (time.Duration, time.Duration, error) {
+	startStr, endStr, found := strings.Cut(s, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidWorkingHours, s)
+	}
+
+	start, startErr := time.Parse(clockLayout, strings.TrimSpace(startStr))
+	end, endErr := time.Parse(clockLayout, strings.TrimSpace(endStr))
+	if startErr != nil || endErr != nil || !end.After(start) {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidWorkingHours, s)
+	}
+
+	midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Sub(midnight), end.Sub(midnight), nil
+}
+
+// ParseWorkingDays parses a list of weekdays and ranges of weekdays such as
+// "mon-fri" or "mon,tue,thu". Ranges may wrap around the week ("sun-thu").
+func ParseWorkingDays(s string) ([7]bool, error) {
+	var days [7]bool
+	for _, part := range strings.Split(strings.ToLower(s), ",") {
+		firstStr, lastStr, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		first, ok := weekdayNames[firstStr]
+		if !isRange {
+			lastStr = firstStr
+		}
+		last, lastOK := weekdayNames[lastStr]
+		if !ok || !lastOK {
+			return [7]bool{}, fmt.Errorf("%w: %q", ErrInvalidWorkingDays, s)
+		}
+
+		for day := first; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// ParseHolidays parses "YYYY-MM-DD" days into a set for Calendar.Holidays.
+func ParseHolidays(values []string) (map[string]bool, error) {
+	holidays := make(map[string]bool, len(values))
+	for _, value := range values {
+		day, err := time.Parse(DayLayout, strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidHoliday, value)
+		}
+		holidays[day.Format(DayLayout)] = true
+	}
+	return holidays, nil
+}
+
+// span is a stretch of time in which commits may be dated, from start
+// inclusive to end exclusive.
+type span struct {
+	start, end time.Time
+}
+
+// freeSpans returns the stretches of [from, to] allowed by the calendar, in
+// order. Days are those of the location of from.
+func (c Calendar) freeSpans(from, to time.Time) []span {
+	end := to.Add(time.Second) // to itself may be used
+	var spans []span
+	for day := startOfDay(from); day.Before(end); day = nextDay(day) {
+		if c.Holidays[day.Format(DayLayout)] || (c.WorkingHours && !c.WorkingDays[day.Weekday()]) {
+			continue
+		}
+
+		next := span{start: day, end: nextDay(day)}
+		if c.WorkingHours {
+			next = span{start: clockTime(day, c.DayStart), end: clockTime(day, c.DayEnd)}
+		}
+		next.start = latest(next.start, from)
+		next.end = earliest(next.end, end)
+		if !next.end.After(next.start) {
+			continue
+		}
+
+		if last := len(spans) - 1; last >= 0 && spans[last].end.Equal(next.start) {
+			spans[last].end = next.end
+			continue
+		}
+		spans = append(spans, next)
+	}
+	return spans
+}
+
+// SpreadEvenly returns count dates spread evenly over the free time of the
+// calendar between from and to, both included: the first on from (or the
+// first free second after it), the last on to (or the last free second
+// before it). The dates are whole seconds and strictly increasing.
+func (c Calendar) SpreadEvenly(from, to time.Time, count int) ([]time.Time, error) {
+	spans, total, err := c.spreadSpans(from, to, count)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make([]int64, count)
+	for i := range offsets {
+		if count > 1 {
+			offsets[i] = int64(i) * (total - 1) / int64(count-1)
+		}
+	}
+	return placeOffsets(spans, offsets), nil
+}
+
+// SpreadRandomly returns count dates drawn at random from the free time of
+// the calendar between from and to, both included. The same seed gives the
+// same dates. The dates are whole seconds and strictly increasing.
+func (c Calendar) SpreadRandomly(from, to time.Time, count int, seed uint64) ([]time.Time, error) {
+	spans, total, err := c.spreadSpans(from, to, count)
+	if err != nil {
+		return nil, err
+	}
+
+	// Floyd's algorithm draws count distinct seconds out of total
+	random := rand.New(rand.NewPCG(seed, seed))
+	chosen := make(map[int64]bool, count)
+	for j := total - int64(count); j < total; j++ {
+		offset := random.Int64N(j + 1)
+		if chosen[offset] {
+			offset = j
+		}
+		chosen[offset] = true
+	}
+
+	offsets := make([]int64, 0, count)
+	for offset := range chosen {
+		offsets = append(offsets, offset)
+	}
+	slices.Sort(offsets)
+	return placeOffsets(spans, offsets), nil
+}
+
+// spreadSpans returns the free spans between from and to, truncated to whole
+// seconds, and their total length in seconds, which must hold count dates.
+func (c Calendar) spreadSpans(from, to time.Time, count int) ([]span, int64, error) {
+	if truncated := from.Truncate(time.Second); truncated.Before(from) {
+		from = truncated.Add(time.Second)
+	}
+	to = to.Truncate(time.Second)
+
+	spans := c.freeSpans(from, to)
+	var total int64
+	for _, s := range spans {
+		total += int64(s.end.Sub(s.start) / time.Second)
+	}
+	if total < int64(count) {
+		return nil, 0, fmt.Errorf("%w: %d free second(s) for %d date(s)", ErrWindowTooSmall, total, count)
+	}
+	return spans, total, nil
+}
+
+// placeOffsets converts increasing offsets, in seconds of free time, to dates.
+func placeOffsets(spans []span, offsets []int64) []time.Time {
+	dates := make([]time.Time, len(offsets))
+	current, skipped := 0, int64(0)
+	for i, offset := range offsets {
+		for {
+			length := int64(spans[current].end.Sub(spans[current].start) / time.Second)
+			if offset < skipped+length {
+				break
+			}
+			skipped += length
+			current++
+		}
+		dates[i] = spans[current].start.Add(time.Duration(offset-skipped) * time.Second)
+	}
+	return dates
+}
+
+// startOfDay returns midnight of the day of t, in the location of t.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// nextDay returns midnight of the day after day.
+func nextDay(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location())
+}
+
+// clockTime returns the time offset from midnight on day, as read on a wall
+// clock, so that working hours hold across daylight saving changes.
+func clockTime(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(offset/time.Minute), 0, 0, day.Location())
+}
+
+// earliest returns the earlier of a and b.
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// latest returns the later of a and b.
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
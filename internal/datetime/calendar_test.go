@@ -0,0 +1,155 @@
+package datetime
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// workingCalendar returns a Calendar of 09:00-17:00, Monday to Friday.
+func workingCalendar(t *testing.T) Calendar {
+	t.Helper()
+	start, end, err := ParseWorkingHours(DefaultWorkingHours)
+	if err != nil {
+		t.Fatalf("ParseWorkingHours() unexpected error: %v", err)
+	}
+	days, err := ParseWorkingDays(DefaultWorkingDays)
+	if err != nil {
+		t.Fatalf("ParseWorkingDays() unexpected error: %v", err)
+	}
+	return Calendar{WorkingHours: true, DayStart: start, DayEnd: end, WorkingDays: days}
+}
+
+// TestParseWorkingHours tests parsing of working hours.
+func TestParseWorkingHours(t *testing.T) {
+	start, end, err := ParseWorkingHours("08:30 - 18:00")
+	if err != nil {
+		t.Fatalf("ParseWorkingHours() unexpected error: %v", err)
+	}
+	if start != 8*time.Hour+30*time.Minute || end != 18*time.Hour {
+		t.Errorf("ParseWorkingHours() = %v, %v", start, end)
+	}
+
+	for _, invalid := range []string{"", "09:00", "9h-17h", "17:00-09:00", "09:00-09:00", "25:00-26:00"} {
+		if _, _, err := ParseWorkingHours(invalid); !errors.Is(err, ErrInvalidWorkingHours) {
+			t.Errorf("ParseWorkingHours(%q) error = %v, expected ErrInvalidWorkingHours", invalid, err)
+		}
+	}
+}
+
+// TestParseWorkingDays tests lists and ranges of weekdays.
+func TestParseWorkingDays(t *testing.T) {
+	tests := map[string][]time.Weekday{
+		"mon-fri":     {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		"Mon,Wed,fri": {time.Monday, time.Wednesday, time.Friday},
+		"sun-thu":     {time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday},
+		"fri-mon":     {time.Sunday, time.Monday, time.Friday, time.Saturday},
+		"sat":         {time.Saturday},
+	}
+
+	for input, expected := range tests {
+		days, err := ParseWorkingDays(input)
+		if err != nil {
+			t.Fatalf("ParseWorkingDays(%q) unexpected error: %v", input, err)
+		}
+		var got []time.Weekday
+		for day, worked := range days {
+			if worked {
+				got = append(got, time.Weekday(day))
+			}
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("ParseWorkingDays(%q) = %v, expected %v", input, got, expected)
+		}
+	}
+
+	for _, invalid := range []string{"", "monday", "mon-", "mon,,fri"} {
+		if _, err := ParseWorkingDays(invalid); !errors.Is(err, ErrInvalidWorkingDays) {
+			t.Errorf("ParseWorkingDays(%q) error = %v, expected ErrInvalidWorkingDays", invalid, err)
+		}
+	}
+}
+
+// TestSpreadEvenly tests evenly spaced dates over whole days and working hours.
+func TestSpreadEvenly(t *testing.T) {
+	from := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC) // a Monday
+	to := time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+
+	dates, err := Calendar{}.SpreadEvenly(from, to, 3)
+	if err != nil {
+		t.Fatalf("SpreadEvenly() unexpected error: %v", err)
+	}
+	expected := []time.Time{from, from.Add(6 * time.Hour), to}
+	if !slices.EqualFunc(dates, expected, time.Time.Equal) {
+		t.Errorf("SpreadEvenly() = %v, expected %v", dates, expected)
+	}
+
+	// Over a week of working hours, with Wednesday off: 32 hours of work,
+	// so one date every 8 hours of work, at 09:00 the next working day
+	calendar := workingCalendar(t)
+	calendar.Holidays, err = ParseHolidays([]string{"2025-01-08"})
+	if err != nil {
+		t.Fatalf("ParseHolidays() unexpected error: %v", err)
+	}
+	dates, err = calendar.SpreadEvenly(from, time.Date(2025, 1, 12, 23, 0, 0, 0, time.UTC), 4)
+	if err != nil {
+		t.Fatalf("SpreadEvenly() unexpected error: %v", err)
+	}
+	expected = []time.Time{
+		time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 7, 11, 39, 59, 0, time.UTC),
+		time.Date(2025, 1, 9, 14, 19, 59, 0, time.UTC),
+		time.Date(2025, 1, 10, 16, 59, 59, 0, time.UTC),
+	}
+	if !slices.EqualFunc(dates, expected, time.Time.Equal) {
+		t.Errorf("SpreadEvenly() = %v, expected %v", dates, expected)
+	}
+}
+
+// TestSpreadRandomly tests that random dates are reproducible, increasing and free.
+func TestSpreadRandomly(t *testing.T) {
+	calendar := workingCalendar(t)
+	from := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 12, 23, 0, 0, 0, time.UTC)
+
+	dates, err := calendar.SpreadRandomly(from, to, 20, 42)
+	if err != nil {
+		t.Fatalf("SpreadRandomly() unexpected error: %v", err)
+	}
+	again, _ := calendar.SpreadRandomly(from, to, 20, 42)
+	if !slices.EqualFunc(dates, again, time.Time.Equal) {
+		t.Error("SpreadRandomly() gave different dates for the same seed")
+	}
+	other, _ := calendar.SpreadRandomly(from, to, 20, 43)
+	if slices.EqualFunc(dates, other, time.Time.Equal) {
+		t.Error("SpreadRandomly() gave the same dates for another seed")
+	}
+
+	if i := FirstNonIncreasing(dates); i != -1 {
+		t.Errorf("dates[%d] = %v does not follow %v", i, dates[i], dates[i-1])
+	}
+	for _, date := range dates {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday || date.Hour() < 9 || date.Hour() >= 17 {
+			t.Errorf("Date %v is outside working hours", date)
+		}
+	}
+}
+
+// TestSpreadWindowTooSmall tests that a window must hold one second per date.
+func TestSpreadWindowTooSmall(t *testing.T) {
+	from := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+
+	if _, err := (Calendar{}).SpreadEvenly(from, from.Add(time.Second), 3); !errors.Is(err, ErrWindowTooSmall) {
+		t.Errorf("SpreadEvenly() error = %v, expected ErrWindowTooSmall", err)
+	}
+	if dates, err := (Calendar{}).SpreadRandomly(from, from.Add(2*time.Second), 3, 1); err != nil || len(dates) != 3 {
+		t.Errorf("SpreadRandomly() = %v, %v, expected the three seconds", dates, err)
+	}
+
+	// A weekend holds no working hours
+	saturday := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)
+	if _, err := workingCalendar(t).SpreadEvenly(saturday, saturday.Add(47*time.Hour), 1); !errors.Is(err, ErrWindowTooSmall) {
+		t.Errorf("SpreadEvenly() error = %v, expected ErrWindowTooSmall", err)
+	}
+}
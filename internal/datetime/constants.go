@@ -12,7 +12,7 @@ const (
 	// Example: "Wed 5 Feb 2025 20:19:19 CEST".
 	GitDateLayout = "Mon 2 Jan 2006 15:04:05 MST"
 
-	// GitLogDateLayout is the format git log returns when using --format=%cI (ISO 8601).
-	// This is used to parse the last commit date from git log output.
+	// GitLogDateLayout is the format git log returns when using --format=%aI or %cI (strict ISO 8601).
+	// This is used to parse the author and committer dates of the last commit from git log output.
 	GitLogDateLayout = time.RFC3339
 )
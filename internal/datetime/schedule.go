@@ -0,0 +1,34 @@
+package datetime
+
+import "time"
+
+// ScheduleByInterval returns count dates starting at start, each interval
+// after the previous one.
+func ScheduleByInterval(start time.Time, count int, interval time.Duration) []time.Time {
+	dates := make([]time.Time, count)
+	for i := range dates {
+		dates[i] = start.Add(time.Duration(i) * interval)
+	}
+	return dates
+}
+
+// ScheduleByGaps returns one date per original date, keeping the gaps between
+// the originals but shifted so that the first one falls on start.
+func ScheduleByGaps(start time.Time, originals []time.Time) []time.Time {
+	dates := make([]time.Time, len(originals))
+	for i, original := range originals {
+		dates[i] = start.Add(original.Sub(originals[0]))
+	}
+	return dates
+}
+
+// FirstNonIncreasing returns the index of the first date that is not strictly
+// after the date before it, or -1 if the dates are strictly increasing.
+func FirstNonIncreasing(dates []time.Time) int {
+	for i := 1; i < len(dates); i++ {
+		if !dates[i].After(dates[i-1]) {
+			return i
+		}
+	}
+	return -1
+}
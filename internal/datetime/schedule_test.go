@@ -0,0 +1,66 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+)
+
+// TestScheduleByInterval tests evenly spaced dates.
+func TestScheduleByInterval(t *testing.T) {
+	start := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+
+	dates := ScheduleByInterval(start, 3, 30*time.Minute)
+
+	expected := []time.Time{start, start.Add(30 * time.Minute), start.Add(time.Hour)}
+	if len(dates) != len(expected) {
+		t.Fatalf("ScheduleByInterval() returned %d dates, expected %d", len(dates), len(expected))
+	}
+	for i := range expected {
+		if !dates[i].Equal(expected[i]) {
+			t.Errorf("dates[%d] = %v, expected %v", i, dates[i], expected[i])
+		}
+	}
+}
+
+// TestScheduleByGaps tests that original gaps are kept from the new start.
+func TestScheduleByGaps(t *testing.T) {
+	start := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+	originals := []time.Time{
+		time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 1, 9, 45, 0, 0, time.UTC),
+		time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC),
+	}
+
+	dates := ScheduleByGaps(start, originals)
+
+	expected := []time.Time{start, start.Add(45 * time.Minute), start.Add(48 * time.Hour)}
+	for i := range expected {
+		if !dates[i].Equal(expected[i]) {
+			t.Errorf("dates[%d] = %v, expected %v", i, dates[i], expected[i])
+		}
+	}
+}
+
+// TestFirstNonIncreasing tests detection of dates out of order.
+func TestFirstNonIncreasing(t *testing.T) {
+	base := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		dates    []time.Time
+		expected int
+	}{
+		{name: "empty", dates: nil, expected: -1},
+		{name: "increasing", dates: []time.Time{base, base.Add(time.Second), base.Add(time.Hour)}, expected: -1},
+		{name: "equal", dates: []time.Time{base, base.Add(time.Hour), base.Add(time.Hour)}, expected: 2},
+		{name: "decreasing", dates: []time.Time{base, base.Add(-time.Hour)}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FirstNonIncreasing(tt.dates); got != tt.expected {
+				t.Errorf("FirstNonIncreasing() = %d, expected %d", got, tt.expected)
+			}
+		})
+	}
+}
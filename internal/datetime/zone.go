@@ -0,0 +1,65 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// secondsPerMinute and minutesPerHour convert zone offsets to seconds.
+	secondsPerMinute = 60
+	minutesPerHour   = 60
+
+	// maxOffsetHours bounds fixed zone offsets, as git does.
+	maxOffsetHours = 14
+)
+
+var (
+	// ErrInvalidZone is returned when a zone is neither a fixed offset nor a known location.
+	ErrInvalidZone = errors.New("invalid zone")
+)
+
+// ParseZone parses a zone given as a fixed offset ("+0100", "-05:30"), as
+// "UTC" or "Z", or as an IANA location ("Europe/Paris"), whose offset then
+// depends on the date, daylight saving included.
+func ParseZone(s string) (*time.Location, error) {
+	switch {
+	case s == "UTC" || s == "Z":
+		return time.UTC, nil
+	case strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-"):
+		return parseFixedZone(s)
+	case s == "" || strings.EqualFold(s, "local"):
+		// time.LoadLocation would silently give UTC or the local zone
+		return nil, fmt.Errorf("%w: %q", ErrInvalidZone, s)
+	}
+
+	location, err := time.LoadLocation(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidZone, s)
+	}
+	return location, nil
+}
+
+// parseFixedZone parses an offset such as "+0100", "+01:00" or "-05".
+func parseFixedZone(s string) (*time.Location, error) {
+	digits := strings.ReplaceAll(s[1:], ":", "")
+	hoursStr, minutesStr := digits, "0"
+	if len(digits) == len("hhmm") {
+		hoursStr, minutesStr = digits[:2], digits[2:]
+	}
+
+	hours, hoursErr := strconv.Atoi(hoursStr)
+	minutes, minutesErr := strconv.Atoi(minutesStr)
+	if hoursErr != nil || minutesErr != nil || len(hoursStr) != 2 || hours > maxOffsetHours || minutes >= minutesPerHour {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidZone, s)
+	}
+
+	offset := (hours*minutesPerHour + minutes) * secondsPerMinute
+	if s[0] == '-' {
+		offset = -offset
+	}
+	return time.FixedZone("", offset), nil
+}
@@ -0,0 +1,45 @@
+package datetime
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestParseZone tests fixed offsets and locations, and the offset they give a date.
+func TestParseZone(t *testing.T) {
+	winter := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		zone           string
+		winter, summer string
+	}{
+		{"UTC", "+0000", "+0000"},
+		{"Z", "+0000", "+0000"},
+		{"+0100", "+0100", "+0100"},
+		{"-05:30", "-0530", "-0530"},
+		{"+02", "+0200", "+0200"},
+		{"Europe/Paris", "+0100", "+0200"},
+		{"America/New_York", "-0500", "-0400"},
+	}
+
+	for _, tt := range tests {
+		location, err := ParseZone(tt.zone)
+		if err != nil {
+			t.Fatalf("ParseZone(%q) unexpected error: %v", tt.zone, err)
+		}
+		if got := winter.In(location).Format("-0700"); got != tt.winter {
+			t.Errorf("ParseZone(%q) in winter = %s, expected %s", tt.zone, got, tt.winter)
+		}
+		if got := summer.In(location).Format("-0700"); got != tt.summer {
+			t.Errorf("ParseZone(%q) in summer = %s, expected %s", tt.zone, got, tt.summer)
+		}
+	}
+
+	for _, invalid := range []string{"", "Local", "+1", "+123", "+2500", "+01:60", "Mars/Olympus", "CEST+1"} {
+		if _, err := ParseZone(invalid); !errors.Is(err, ErrInvalidZone) {
+			t.Errorf("ParseZone(%q) error = %v, expected ErrInvalidZone", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// BackupRefPrefix is the namespace of backups: each one is a directory
+	// named by its ID holding the saved refs, without their "refs/" prefix.
+	BackupRefPrefix = "refs/gitcommit/backup/"
+
+	// BackupIDLayout formats backup IDs from their creation time, in UTC.
+	// IDs of the same width sort in creation order.
+	BackupIDLayout = "20060102T150405.000000Z"
+
+	// backupTagName names the tag objects that wrap the saved refs of a
+	// backup, whose message is the reason of the backup.
+	backupTagName = "gitcommit-backup"
+
+	// refsPrefix starts the full name of every ref but HEAD.
+	refsPrefix = "refs/"
+)
+
+var (
+	// ErrBackupNotFound is returned when no backup matches an ID.
+	ErrBackupNotFound = errors.New("backup not found")
+
+	// ErrAmbiguousBackup is returned when an ID prefix matches several backups.
+	ErrAmbiguousBackup = errors.New("ambiguous backup ID")
+)
+
+// SavedRef is a ref as a backup recorded it.
+type SavedRef struct {
+	// Name is the full name of the ref, such as refs/heads/main, or HEAD when detached.
+	Name string
+
+	// Hash is the object the ref pointed to, or empty when it did not exist.
+	Hash string
+}
+
+// Backup is a set of refs saved before an operation moved them.
+type Backup struct {
+	// ID names the backup under BackupRefPrefix, from its creation time.
+	ID string
+
+	// Created is when the backup was made.
+	Created time.Time
+
+	// Reason describes the operation, as recorded in the backup.
+	Reason string
+
+	// Refs are the refs saved.
+	Refs []SavedRef
+}
+
+// CreateBackup saves the current value of refs under a new backup. Each
+// saved ref points to a tag object, on the value saved, whose message is
+// reason; reason is also recorded in the reflog of each saved ref. A ref
+// that does not exist is saved as the empty blob, so that restoring it
+// deletes it.
+func CreateBackup(reason string, refs []string) (Backup, error) {
+	emptyBlob, err := writeEmptyBlob()
+	if err != nil {
+		return Backup{}, err
+	}
+
+	saved := make([]SavedRef, len(refs))
+	for i, ref := range refs {
+		saved[i] = SavedRef{Name: ref, Hash: ResolveRef(ref)}
+	}
+
+	// Two backups in the same microsecond cannot both be created: retry
+	for {
+		now := time.Now().UTC()
+		backup := Backup{ID: now.Format(BackupIDLayout), Created: now, Reason: reason, Refs: saved}
+
+		var commands strings.Builder
+		for _, ref := range saved {
+			value := ref.Hash
+			if value == "" {
+				value = emptyBlob
+			}
+			wrapper, err := writeBackupTag(value, reason, now)
+			if err != nil {
+				return Backup{}, err
+			}
+			fmt.Fprintf(&commands, "create %s %s\n", backupRefName(backup.ID, ref.Name), wrapper)
+		}
+
+		cmd := exec.CommandContext(context.Background(),
+			"git", "update-ref", "--create-reflog", "-m", reason, "--stdin")
+		cmd.Stdin = strings.NewReader(commands.String())
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			return backup, nil
+		}
+		if !strings.Contains(string(output), "reference already exists") {
+			return Backup{}, fmt.Errorf("git update-ref failed: %s: %w", GetGitError(output), err)
+		}
+	}
+}
+
+// ListBackups returns every backup, newest first.
+func ListBackups() ([]Backup, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "for-each-ref",
+		"--format=%(refname) %(objectname) %(objecttype) %(tag) %(*objectname) %(*objecttype) %(contents:subject)",
+		BackupRefPrefix)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+
+	byID := make(map[string]*Backup)
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		// Fields are separated by single spaces, and may be empty
+		fields := strings.SplitN(line, " ", 7)
+		if len(fields) != 7 {
+			continue
+		}
+		refName, hash, objectType, reason := fields[0], fields[1], fields[2], ""
+		if objectType == "tag" && fields[3] == backupTagName {
+			hash, objectType, reason = fields[4], fields[5], fields[6]
+		}
+
+		id, name, found := strings.Cut(strings.TrimPrefix(refName, BackupRefPrefix), "/")
+		created, err := time.Parse(BackupIDLayout, id)
+		if !found || err != nil {
+			continue // not made by gitcommit
+		}
+
+		backup, ok := byID[id]
+		if !ok {
+			backup = &Backup{ID: id, Created: created}
+			byID[id] = backup
+			ids = append(ids, id)
+		}
+		if backup.Reason == "" {
+			backup.Reason = reason
+		}
+
+		saved := SavedRef{Name: name, Hash: hash}
+		if name != "HEAD" {
+			saved.Name = refsPrefix + name
+		}
+		if objectType == "blob" {
+			saved.Hash = "" // the ref did not exist
+		}
+		backup.Refs = append(backup.Refs, saved)
+	}
+
+	slices.Sort(ids)
+	slices.Reverse(ids)
+	backups := make([]Backup, len(ids))
+	for i, id := range ids {
+		backups[i] = *byID[id]
+	}
+	return backups, nil
+}
+
+// FindBackup returns the backup whose ID starts with prefix.
+func FindBackup(prefix string) (Backup, error) {
+	backups, err := ListBackups()
+	if err != nil {
+		return Backup{}, err
+	}
+
+	var matches []Backup
+	for _, backup := range backups {
+		if backup.ID == prefix {
+			return backup, nil
+		}
+		if strings.HasPrefix(backup.ID, prefix) {
+			matches = append(matches, backup)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Backup{}, fmt.Errorf("%w: %s", ErrBackupNotFound, prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return Backup{}, fmt.Errorf("%w: %s matches %d backups", ErrAmbiguousBackup, prefix, len(matches))
+	}
+}
+
+// DeleteBackup removes every ref of backup.
+func DeleteBackup(backup Backup) error {
+	var commands strings.Builder
+	for _, ref := range backup.Refs {
+		fmt.Fprintf(&commands, "delete %s\n", backupRefName(backup.ID, ref.Name))
+	}
+
+	cmd := exec.CommandContext(context.Background(), "git", "update-ref", "--stdin")
+	cmd.Stdin = strings.NewReader(commands.String())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git update-ref failed: %s: %w", GetGitError(output), err)
+	}
+	return nil
+}
+
+// ResolveRef returns the object ref points to, without peeling tags, or an
+// empty string when it does not exist.
+func ResolveRef(ref string) string {
+	cmd := exec.CommandContext(context.Background(), "git", "rev-parse", "--verify", "--quiet", ref)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// DeleteRef deletes ref, and its reflog, provided it still points at oldValue.
+func DeleteRef(ref, oldValue string) error {
+	cmd := exec.CommandContext(context.Background(), "git", "update-ref", "-d", ref, oldValue)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if current := ResolveRef(ref); current != oldValue {
+			return fmt.Errorf("%w: %s now points at %s", ErrRefChanged, ref, ShortHash(current))
+		}
+		return fmt.Errorf("git update-ref failed: %s: %w", GetGitError(output), err)
+	}
+	return nil
+}
+
+// ResetKeep moves the current branch, or HEAD when detached, to rev with
+// git reset --keep, which updates the index and working tree but refuses
+// to overwrite local changes. reason is recorded in the reflog.
+func ResetKeep(rev, reason string) error {
+	cmd := exec.CommandContext(context.Background(), "git", "reset", "--quiet", "--keep", rev)
+	cmd.Env = append(cmd.Environ(), "GIT_REFLOG_ACTION="+reason)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git reset --keep failed: %s: %w", GetGitError(output), err)
+	}
+	return nil
+}
+
+// backupRefName returns the name under which backup id saves ref.
+func backupRefName(id, ref string) string {
+	return BackupRefPrefix + id + "/" + strings.TrimPrefix(ref, refsPrefix)
+}
+
+// writeBackupTag writes the tag object a backup saves object as, with
+// reason as its message, and returns its hash.
+func writeBackupTag(object, reason string, created time.Time) (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "cat-file", "-t", object)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git cat-file failed for %s: %w", object, err)
+	}
+
+	return WriteTag(TagObject{
+		Object:     object,
+		Type:       strings.TrimSpace(string(output)),
+		Name:       backupTagName,
+		Tagger:     Identity{Name: "gitcommit"},
+		TaggerDate: created,
+		Message:    reason + "\n",
+	})
+}
+
+// writeEmptyBlob stores the empty blob, which marks refs that did not
+// exist, and returns its hash.
+func writeEmptyBlob() (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "hash-object", "-w", "--stdin")
+	cmd.Stdin = strings.NewReader("")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git hash-object failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -2,46 +2,150 @@
 package git
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
 )
 
+// CommitOptions holds optional settings for ExecuteCommit.
+type CommitOptions struct {
+	// AllowEmpty permits a commit that records no changes (git commit --allow-empty).
+	AllowEmpty bool
+
+	// All stages every modified and deleted tracked file (git commit --all).
+	All bool
+
+	// IndexFile commits from this index file instead of the repository's index.
+	IndexFile string
+
+	// Amend replaces HEAD instead of creating a new commit (git commit --amend).
+	// The author date is reset to the new date as well.
+	Amend bool
+
+	// CherryPick concludes a cherry-pick in progress, whose author git takes
+	// from CHERRY_PICK_HEAD. The author date is reset to the new date as well.
+	CherryPick bool
+
+	// KeepContent, with Amend, records HEAD's tree unchanged and ignores the
+	// index (git commit --amend --only).
+	KeepContent bool
+
+	// Author overrides the author name and email when not nil.
+	Author *Identity
+
+	// Committer overrides the committer name and email when not nil.
+	Committer *Identity
+
+	// Sign signs the commit (git commit --gpg-sign), with SignKey if set.
+	Sign bool
+
+	// SignKey selects the signing key instead of user.signingkey.
+	SignKey string
+
+	// NoSign disables signing even when commit.gpgsign is set (git commit --no-gpg-sign).
+	NoSign bool
+
+	// ShowOutput echoes git's output to the terminal as it runs, including on failure.
+	// Otherwise output is captured and only git's summary is printed on success.
+	ShowOutput bool
+}
+
 // ExecuteCommit executes a git commit with the provided date and message.
 // It sets the GIT_AUTHOR_DATE and GIT_COMMITTER_DATE environment variables
-// to the provided gitFormattedDate before executing the commit.
+// to the provided gitFormattedDate before executing the commit, and the
+// GIT_AUTHOR_NAME/EMAIL and GIT_COMMITTER_NAME/EMAIL variables when the
+// options override the identity.
 //
 // Parameters:
 //   - gitFormattedDate: Date in Git format (e.g., "Wed 5 Feb 2025 20:19:19 CEST")
-//   - message: The commit message
+//   - message: The commit message; with options.Amend, empty keeps HEAD's message
+//   - options: Optional settings such as AllowEmpty, All, IndexFile and Amend
 //
 // Returns an error if the git commit command fails.
-func ExecuteCommit(gitFormattedDate, message string) error {
+func ExecuteCommit(gitFormattedDate, message string, options CommitOptions) error {
 	// Prepare git commit command
-	cmd := exec.CommandContext(context.Background(), "git", "commit", "-m", message)
+	args := []string{"commit"}
+	switch {
+	case message != "":
+		args = append(args, "-m", message)
+	case options.Amend:
+		args = append(args, "--no-edit")
+	}
+	if options.Amend {
+		args = append(args, "--amend")
+	}
+	keepsOriginalAuthor := options.Amend || options.CherryPick
+	if keepsOriginalAuthor {
+		// git keeps the original author date unless --date is given
+		args = append(args, "--date="+gitFormattedDate)
+	}
+	if keepsOriginalAuthor && options.Author != nil {
+		// git keeps the original author unless --author is given
+		args = append(args, "--author="+options.Author.String())
+	}
+	if options.KeepContent {
+		args = append(args, "--only")
+	}
+	if options.AllowEmpty {
+		args = append(args, "--allow-empty")
+	}
+	if options.All {
+		args = append(args, "--all")
+	}
+	switch {
+	case options.Sign && options.SignKey != "":
+		args = append(args, "--gpg-sign="+options.SignKey)
+	case options.Sign:
+		args = append(args, "--gpg-sign")
+	case options.NoSign:
+		args = append(args, "--no-gpg-sign")
+	}
+	cmd := exec.CommandContext(context.Background(), "git", args...)
 
 	// Set environment variables for commit dates
 	cmd.Env = append(os.Environ(),
 		"GIT_AUTHOR_DATE="+gitFormattedDate,
 		"GIT_COMMITTER_DATE="+gitFormattedDate,
 	)
+	if options.IndexFile != "" {
+		cmd.Env = append(cmd.Env, "GIT_INDEX_FILE="+options.IndexFile)
+	}
 
-	// Capture output for error reporting
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	// Set environment variables for identity overrides
+	if options.Author != nil {
+		cmd.Env = append(cmd.Env,
+			"GIT_AUTHOR_NAME="+options.Author.Name,
+			"GIT_AUTHOR_EMAIL="+options.Author.Email,
+		)
+	}
+	if options.Committer != nil {
+		cmd.Env = append(cmd.Env,
+			"GIT_COMMITTER_NAME="+options.Committer.Name,
+			"GIT_COMMITTER_EMAIL="+options.Committer.Email,
+		)
+	}
+
+	// Capture output for error classification, echoing it live when asked
+	var stdout, stderr bytes.Buffer
+	if options.ShowOutput {
+		cmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	} else {
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+	}
 
 	// Execute the commit
 	if err := cmd.Run(); err != nil {
-		// Try to get more details about the error
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			// Git command failed, return a more informative error
-			return fmt.Errorf("git commit failed with exit code %d: %w", exitErr.ExitCode(), err)
-		}
-		return fmt.Errorf("git commit failed: %w", err)
+		return newCommitError(err, stdout.String(), stderr.String())
+	}
+
+	if !options.ShowOutput {
+		fmt.Fprint(os.Stdout, stdout.String())
 	}
 
 	return nil
@@ -0,0 +1,58 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GetConfig returns the value of a git config key, or "" when it is not set.
+func GetConfig(key string) (string, error) {
+	return getConfig("", key)
+}
+
+// GetConfigPath returns the value of a git config key holding a path, with
+// a leading ~ expanded, or "" when it is not set.
+func GetConfigPath(key string) (string, error) {
+	return getConfig("--path", key)
+}
+
+// getConfig returns the value of a git config key, or "" when it is not set.
+// typeFlag is an optional type such as "--bool".
+func getConfig(typeFlag, key string) (string, error) {
+	args := []string{"config"}
+	if typeFlag != "" {
+		args = append(args, typeFlag)
+	}
+	args = append(args, "--get", key)
+
+	return readConfig(key, args)
+}
+
+// GetConfigAll returns every value of a multi-valued git config key, or nil
+// when it is not set.
+func GetConfigAll(key string) ([]string, error) {
+	values, err := readConfig(key, []string{"config", "--get-all", key})
+	if err != nil || values == "" {
+		return nil, err
+	}
+	return strings.Split(values, "\n"), nil
+}
+
+// readConfig runs a git config query for key, returning "" when it is not set.
+func readConfig(key string, args []string) (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit code 1 means the key is not set
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("git config %s failed: %w", key, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
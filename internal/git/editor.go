@@ -0,0 +1,70 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// GetEditor returns the editor git would use, honouring GIT_EDITOR,
+// core.editor, VISUAL and EDITOR in that order.
+func GetEditor() (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "var", "GIT_EDITOR")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine editor: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RunEditor opens path in editor, attached to the terminal, and waits for it to exit.
+// Like git, the editor string is interpreted by the shell so it may contain arguments.
+func RunEditor(editor, path string) error {
+	// ":" is git's conventional no-op editor
+	if editor == ":" {
+		return nil
+	}
+
+	cmd := exec.CommandContext(context.Background(), "sh", "-c", editor+` "$@"`, editor, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+// StripSpace cleans up a commit message the way git does: trailing whitespace
+// and surplus blank lines are removed and, when stripComments is set, so are
+// lines starting with the comment character.
+func StripSpace(message string, stripComments bool) (string, error) {
+	args := []string{"stripspace"}
+	if stripComments {
+		args = append(args, "--strip-comments")
+	}
+
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	cmd.Stdin = strings.NewReader(message)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git stripspace failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
+// GetCommitMessage returns the full message of the commit named by rev.
+func GetCommitMessage(rev string) (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "log", "-1", "--format=%B", rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read message of %s: %w", rev, err)
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,160 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// FailureKind classifies why a git commit failed.
+type FailureKind string
+
+const (
+	// FailureUnknown is a failure that matched no known pattern.
+	FailureUnknown FailureKind = "unknown"
+	// FailureNothingToCommit means the commit would record no changes.
+	FailureNothingToCommit FailureKind = "nothing_to_commit"
+	// FailureUnknownIdentity means user.name or user.email is not configured.
+	FailureUnknownIdentity FailureKind = "unknown_identity"
+	// FailureHookRejected means a pre-commit, prepare-commit-msg or commit-msg hook exited non-zero.
+	FailureHookRejected FailureKind = "hook_rejected"
+	// FailureIndexLocked means another git process holds index.lock, or a stale one was left behind.
+	FailureIndexLocked FailureKind = "index_locked"
+	// FailureDetachedHead means the operation requires a branch but HEAD is detached.
+	FailureDetachedHead FailureKind = "detached_head"
+	// FailureUnmergedPaths means the index still contains unresolved conflicts.
+	FailureUnmergedPaths FailureKind = "unmerged_paths"
+	// FailureSigningFailed means the commit could not be signed (missing key, agent or program).
+	FailureSigningFailed FailureKind = "signing_failed"
+)
+
+// commitHooks are the hooks run by git commit that can reject a commit.
+var commitHooks = []string{"pre-commit", "prepare-commit-msg", "commit-msg"}
+
+// failurePatterns maps fragments of git's output to failure kinds, checked in order.
+var failurePatterns = []struct {
+	kind      FailureKind
+	fragments []string
+}{
+	{FailureIndexLocked, []string{"index.lock': File exists", "Another git process seems to be running"}},
+	{FailureUnmergedPaths, []string{
+		"you have unmerged files",
+		"you need to resolve your current index first",
+		"Unmerged paths",
+	}},
+	{FailureUnknownIdentity, []string{
+		"Please tell me who you are",
+		"unable to auto-detect email address",
+		"auto-detection is disabled",
+		"empty ident name",
+	}},
+	{FailureSigningFailed, []string{
+		"gpg failed to sign the data",
+		"user.signingkey or gpg.ssh.defaultKeyCommand needs to be configured",
+		"ssh-keygen -Y sign is needed for ssh signing",
+		"Couldn't load public key",
+		"No private key found for",
+	}},
+	// Before FailureDetachedHead: git status reports "HEAD detached at" above "nothing to commit"
+	{FailureNothingToCommit, []string{"nothing to commit", "nothing added to commit", "no changes added to commit"}},
+	{FailureDetachedHead, []string{"You are not currently on a branch", "HEAD detached"}},
+}
+
+// lockFilePattern extracts the lock file path from git's "Unable to create" message.
+var lockFilePattern = regexp.MustCompile(`Unable to create '([^']+)': File exists`)
+
+// CommitError describes a failed git commit, classified from git's output.
+type CommitError struct {
+	// Kind is the classified reason for the failure.
+	Kind FailureKind
+
+	// ExitCode is git's exit code, or -1 if git could not be started.
+	ExitCode int
+
+	// Output is everything git printed, stderr first.
+	Output string
+
+	// LockFile is the path of the index lock for FailureIndexLocked.
+	LockFile string
+
+	// Hooks lists the active commit hooks for FailureHookRejected.
+	Hooks []string
+
+	// Err is the underlying error from running git.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *CommitError) Error() string {
+	return fmt.Sprintf("git commit failed with exit code %d: %s", e.ExitCode, GetGitError([]byte(e.Output)))
+}
+
+// Unwrap returns the underlying error.
+func (e *CommitError) Unwrap() error {
+	return e.Err
+}
+
+// newCommitError classifies a failed git commit from its captured output.
+func newCommitError(err error, stdout, stderr string) *CommitError {
+	commitErr := &CommitError{
+		Kind:     FailureUnknown,
+		ExitCode: -1,
+		Output:   strings.TrimSpace(stderr + "\n" + stdout),
+		Err:      err,
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		commitErr.ExitCode = exitErr.ExitCode()
+	}
+
+	commitErr.Kind = ClassifyFailure(commitErr.Output)
+
+	if commitErr.Kind == FailureIndexLocked {
+		if match := lockFilePattern.FindStringSubmatch(commitErr.Output); match != nil {
+			commitErr.LockFile = match[1]
+		}
+	}
+
+	// Hooks fail without a message of git's own, so blame them only when one is installed
+	if commitErr.Kind == FailureUnknown && commitErr.ExitCode > 0 {
+		if hooks := ActiveCommitHooks(); len(hooks) > 0 {
+			commitErr.Kind = FailureHookRejected
+			commitErr.Hooks = hooks
+		}
+	}
+
+	return commitErr
+}
+
+// ClassifyFailure matches git's output against known failure messages.
+// Hook rejections cannot be recognized from output alone and yield FailureUnknown.
+func ClassifyFailure(output string) FailureKind {
+	for _, pattern := range failurePatterns {
+		for _, fragment := range pattern.fragments {
+			if strings.Contains(output, fragment) {
+				return pattern.kind
+			}
+		}
+	}
+	return FailureUnknown
+}
+
+// ActiveCommitHooks returns the names of the executable hooks that git commit
+// would run, honouring core.hooksPath.
+func ActiveCommitHooks() []string {
+	var hooks []string
+	for _, name := range commitHooks {
+		path, err := GetGitPath("hooks/" + name)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0o111 != 0 {
+			hooks = append(hooks, name)
+		}
+	}
+	return hooks
+}
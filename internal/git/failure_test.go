@@ -0,0 +1,97 @@
+package git
+
+import "testing"
+
+// TestClassifyFailure tests that git's failure messages map to the expected kinds.
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected FailureKind
+	}{
+		{
+			name:     "nothing to commit",
+			output:   "On branch main\nnothing to commit, working tree clean",
+			expected: FailureNothingToCommit,
+		},
+		{
+			name:     "untracked files only",
+			output:   "On branch main\nnothing added to commit but untracked files present (use \"git add\" to track)",
+			expected: FailureNothingToCommit,
+		},
+		{
+			name:     "unstaged changes only",
+			output:   "no changes added to commit (use \"git add\" and/or \"git commit -a\")",
+			expected: FailureNothingToCommit,
+		},
+		{
+			name: "unknown identity",
+			output: "Author identity unknown\n\n*** Please tell me who you are.\n\n" +
+				"fatal: no email was given and auto-detection is disabled",
+			expected: FailureUnknownIdentity,
+		},
+		{
+			name: "index lock",
+			output: "fatal: Unable to create '/repo/.git/index.lock': File exists.\n\n" +
+				"Another git process seems to be running in this repository",
+			expected: FailureIndexLocked,
+		},
+		{
+			name: "unmerged paths",
+			output: "error: Committing is not possible because you have unmerged files.\n" +
+				"fatal: Exiting because of an unresolved conflict.",
+			expected: FailureUnmergedPaths,
+		},
+		{
+			name:     "gpg signing failed",
+			output:   "error: gpg failed to sign the data\nfatal: failed to write commit object",
+			expected: FailureSigningFailed,
+		},
+		{
+			name:     "ssh signing key missing",
+			output:   "fatal: either user.signingkey or gpg.ssh.defaultKeyCommand needs to be configured",
+			expected: FailureSigningFailed,
+		},
+		{
+			name:     "commit object not written",
+			output:   "error: insufficient permission for adding an object to repository database .git/objects\nfatal: failed to write commit object",
+			expected: FailureUnknown,
+		},
+		{
+			name:     "detached head",
+			output:   "fatal: You are not currently on a branch.",
+			expected: FailureDetachedHead,
+		},
+		{
+			name:     "nothing to commit on a detached head",
+			output:   "HEAD detached at 1a2b3c4\nnothing to commit, working tree clean",
+			expected: FailureNothingToCommit,
+		},
+		{
+			name:     "unrecognized output",
+			output:   "lint failed: 3 problems",
+			expected: FailureUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyFailure(tt.output); got != tt.expected {
+				t.Errorf("ClassifyFailure() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCommitErrorLockFile tests that the lock file path is extracted from git's message.
+func TestCommitErrorLockFile(t *testing.T) {
+	stderr := "fatal: Unable to create '/repo/.git/index.lock': File exists.\n"
+	commitErr := newCommitError(nil, "", stderr)
+
+	if commitErr.Kind != FailureIndexLocked {
+		t.Fatalf("Kind = %q, expected %q", commitErr.Kind, FailureIndexLocked)
+	}
+	if commitErr.LockFile != "/repo/.git/index.lock" {
+		t.Errorf("LockFile = %q, expected /repo/.git/index.lock", commitErr.LockFile)
+	}
+}
@@ -0,0 +1,121 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// AuthorIdentVar is the git var holding the default author identity.
+	AuthorIdentVar = "GIT_AUTHOR_IDENT"
+
+	// CommitterIdentVar is the git var holding the default committer identity.
+	CommitterIdentVar = "GIT_COMMITTER_IDENT"
+)
+
+var (
+	// ErrInvalidIdentity is returned when an identity is not in "Name <email>" form.
+	ErrInvalidIdentity = errors.New(`identity must be in the form "Name <email>"`)
+)
+
+// Identity is the name and email recorded as a commit's author or committer.
+type Identity struct {
+	// Name is the person's display name.
+	Name string
+
+	// Email is the person's email address, without angle brackets.
+	Email string
+}
+
+// String formats the identity the way git displays it: "Name <email>".
+func (i Identity) String() string {
+	return i.Name + " <" + i.Email + ">"
+}
+
+// ParseIdentity parses an identity in "Name <email>" form, as accepted by
+// git commit --author.
+func ParseIdentity(s string) (Identity, error) {
+	s = strings.TrimSpace(s)
+
+	open := strings.Index(s, "<")
+	if open < 0 || !strings.HasSuffix(s, ">") || strings.Count(s, "<") != 1 || strings.Count(s, ">") != 1 {
+		return Identity{}, fmt.Errorf("%w: %q", ErrInvalidIdentity, s)
+	}
+
+	identity := Identity{
+		Name:  strings.TrimSpace(s[:open]),
+		Email: strings.TrimSpace(s[open+1 : len(s)-1]),
+	}
+
+	if identity.Name == "" {
+		return Identity{}, fmt.Errorf("%w: name is empty in %q", ErrInvalidIdentity, s)
+	}
+	if !strings.Contains(identity.Email, "@") || strings.ContainsAny(identity.Email, " \t") {
+		return Identity{}, fmt.Errorf("%w: invalid email in %q", ErrInvalidIdentity, s)
+	}
+
+	return identity, nil
+}
+
+// parseRecordedIdentity parses an identity as git reports or records it,
+// "Name <email>", without validating the email: git accepts any configured
+// value, including addresses without "@" and empty ones.
+func parseRecordedIdentity(s string) (Identity, error) {
+	s = strings.TrimSpace(s)
+
+	open := strings.LastIndex(s, "<")
+	if open < 0 || !strings.HasSuffix(s, ">") {
+		return Identity{}, fmt.Errorf("%w: %q", ErrInvalidIdentity, s)
+	}
+
+	return Identity{
+		Name:  strings.TrimSpace(s[:open]),
+		Email: strings.TrimSpace(s[open+1 : len(s)-1]),
+	}, nil
+}
+
+// GetDefaultIdentity returns the identity git would use from its environment
+// and user.* configuration, for identVar AuthorIdentVar or CommitterIdentVar.
+// Fails when the identity is not configured and cannot be guessed.
+func GetDefaultIdentity(identVar string) (Identity, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "var", identVar)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return Identity{}, fmt.Errorf("%s: %w", GetGitError(output), err)
+	}
+
+	// The value is "Name <email> timestamp offset"; drop the timestamp
+	ident := strings.TrimSpace(string(output))
+	if end := strings.LastIndex(ident, ">"); end >= 0 {
+		ident = ident[:end+1]
+	}
+
+	return parseRecordedIdentity(ident)
+}
+
+// GetCommitAuthor returns the author recorded in the commit named by rev.
+func GetCommitAuthor(rev string) (Identity, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "log", "-1", "--format=%an%x00%ae", rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return Identity{}, fmt.Errorf("failed to read author of %s: %w", rev, err)
+	}
+
+	name, email, _ := strings.Cut(strings.TrimSpace(string(output)), "\x00")
+	return Identity{Name: name, Email: email}, nil
+}
+
+// MapIdentity returns the canonical identity for i according to .mailmap
+// (and mailmap.file / mailmap.blob). Unmapped identities are returned unchanged.
+func MapIdentity(i Identity) (Identity, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "check-mailmap", i.String())
+	output, err := cmd.Output()
+	if err != nil {
+		return Identity{}, fmt.Errorf("git check-mailmap failed: %w", err)
+	}
+
+	return parseRecordedIdentity(string(output))
+}
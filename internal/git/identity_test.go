@@ -0,0 +1,79 @@
+package git
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseIdentity tests parsing of "Name <email>" identities.
+func TestParseIdentity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Identity
+		wantErr  bool
+	}{
+		{input: "Jane Doe <jane@example.com>", expected: Identity{Name: "Jane Doe", Email: "jane@example.com"}},
+		{input: "  Jane Doe   < jane@example.com >  ", expected: Identity{Name: "Jane Doe", Email: "jane@example.com"}},
+		{input: "jane@example.com", wantErr: true},
+		{input: "<jane@example.com>", wantErr: true},
+		{input: "Jane Doe <jane>", wantErr: true},
+		{input: "Jane Doe <jane@example.com", wantErr: true},
+		{input: "Jane <a@b> <jane@example.com>", wantErr: true},
+		{input: "Jane Doe <jane doe@example.com>", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			identity, err := ParseIdentity(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidIdentity) {
+					t.Errorf("ParseIdentity(%q) error = %v, want ErrInvalidIdentity", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIdentity(%q) unexpected error: %v", tt.input, err)
+			}
+			if identity != tt.expected {
+				t.Errorf("ParseIdentity(%q) = %+v, want %+v", tt.input, identity, tt.expected)
+			}
+			if identity.String() != tt.expected.Name+" <"+tt.expected.Email+">" {
+				t.Errorf("String() = %q", identity.String())
+			}
+		})
+	}
+}
+
+// TestParseRecordedIdentity tests that identities reported by git are taken as is.
+func TestParseRecordedIdentity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Identity
+		wantErr  bool
+	}{
+		{input: "Jane Doe <jane@example.com>", expected: Identity{Name: "Jane Doe", Email: "jane@example.com"}},
+		{input: "CI Bot <ci>", expected: Identity{Name: "CI Bot", Email: "ci"}},
+		{input: "CI Bot <>", expected: Identity{Name: "CI Bot", Email: ""}},
+		{input: "Jane <a> <jane@example.com>", expected: Identity{Name: "Jane <a>", Email: "jane@example.com"}},
+		{input: "Jane Doe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			identity, err := parseRecordedIdentity(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidIdentity) {
+					t.Errorf("parseRecordedIdentity(%q) error = %v, want ErrInvalidIdentity", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRecordedIdentity(%q) unexpected error: %v", tt.input, err)
+			}
+			if identity != tt.expected {
+				t.Errorf("parseRecordedIdentity(%q) = %+v, want %+v", tt.input, identity, tt.expected)
+			}
+		})
+	}
+}
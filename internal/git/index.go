@@ -0,0 +1,79 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// TemporaryIndex is an index file outside the repository used to build a
+// commit in isolation, leaving the real index untouched until the commit succeeds.
+type TemporaryIndex struct {
+	// Path is the absolute path of the index file, suitable for GIT_INDEX_FILE.
+	Path string
+
+	// dir is the temporary directory holding the index file.
+	dir string
+}
+
+// NewPathspecIndex creates a temporary index holding the tree of HEAD (or an
+// empty tree before the first commit) plus the working tree state of the files
+// matched by pathspecs, including new and deleted files.
+func NewPathspecIndex(pathspecs []string) (*TemporaryIndex, error) {
+	dir, err := os.MkdirTemp("", "gitcommit-index-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary index directory: %w", err)
+	}
+
+	index := &TemporaryIndex{Path: filepath.Join(dir, "index"), dir: dir}
+
+	readTree := []string{"read-tree", "--empty"}
+	if HasCommits() {
+		readTree = []string{"read-tree", "HEAD"}
+	}
+	if err := index.run(readTree...); err != nil {
+		index.Remove()
+		return nil, err
+	}
+
+	if err := index.run(append([]string{"add", "--all", "--"}, pathspecs...)...); err != nil {
+		index.Remove()
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// Env returns the environment variable that points git at this index.
+func (t *TemporaryIndex) Env() string {
+	return "GIT_INDEX_FILE=" + t.Path
+}
+
+// Remove deletes the temporary index. Failures are ignored since the
+// directory lives under the system temporary directory.
+func (t *TemporaryIndex) Remove() {
+	_ = os.RemoveAll(t.dir)
+}
+
+// run executes a git command against the temporary index.
+func (t *TemporaryIndex) run(args ...string) error {
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	cmd.Env = append(os.Environ(), t.Env())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s failed: %s: %w", args[0], GetGitError(output), err)
+	}
+	return nil
+}
+
+// StagePaths updates the real index for the files matched by pathspecs,
+// including new and deleted files.
+func StagePaths(pathspecs []string) error {
+	args := append([]string{"add", "--all", "--"}, pathspecs...)
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git add failed: %s: %w", GetGitError(output), err)
+	}
+	return nil
+}
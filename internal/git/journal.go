@@ -0,0 +1,161 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// JournalPath is the location of the journal inside the git directory.
+	JournalPath = "gitcommit/journal.jsonl"
+
+	// maxJournalLine bounds the length of a journal entry, which grows with
+	// the number of commits an operation wrote.
+	maxJournalLine = 16 * 1024 * 1024
+)
+
+// JournalEntry records one operation of gitcommit in the journal, one JSON
+// object per line.
+type JournalEntry struct {
+	// Time is when the operation ran.
+	Time time.Time `json:"time"`
+
+	// Operation names the command, such as "commit", "amend" or "redate".
+	Operation string `json:"operation"`
+
+	// Range is the range of commits a rewrite applied to.
+	Range string `json:"range,omitempty"`
+
+	// InputDate is the date, or what the dates came from, exactly as given.
+	InputDate string `json:"inputDate,omitempty"`
+
+	// Date is the date InputDate resolved to, when it is a single date.
+	Date time.Time `json:"date,omitzero"`
+
+	// Refs are the refs the operation moved.
+	Refs []JournalRef `json:"refs,omitempty"`
+
+	// Commits are the commits the operation wrote.
+	Commits []JournalCommit `json:"commits,omitempty"`
+
+	// Identity is the committer identity the operation recorded.
+	Identity string `json:"identity"`
+
+	// Author is the author identity recorded in the commit, for a commit or amend.
+	Author string `json:"author,omitempty"`
+
+	// Version is the gitcommit version.
+	Version string `json:"version"`
+
+	// Backup is the ID of the backup of the refs made before the operation.
+	Backup string `json:"backup,omitempty"`
+
+	// Restored is the ID of the backup an undo restored.
+	Restored string `json:"restored,omitempty"`
+}
+
+// JournalRef is a ref moved by an operation. An empty ID means the ref did
+// not exist, before or after.
+type JournalRef struct {
+	Name string `json:"name"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// JournalCommit is a commit written by an operation, with its dates.
+type JournalCommit struct {
+	// Old is the commit it replaces or copies, if any.
+	Old string `json:"old,omitempty"`
+
+	// New is the commit written.
+	New string `json:"new"`
+
+	AuthorDate    time.Time `json:"authorDate"`
+	CommitterDate time.Time `json:"committerDate"`
+}
+
+// AppendJournal adds entry at the end of the journal of the repository.
+func AppendJournal(entry JournalEntry) error {
+	path, err := GetGitPath(JournalPath)
+	if err != nil {
+		return err
+	}
+	return appendJournalFile(path, entry)
+}
+
+// ReadJournal returns the entries of the journal of the repository, oldest
+// first, and the numbers of the lines that could not be read. A repository
+// without a journal has no entries.
+func ReadJournal() ([]JournalEntry, []int, error) {
+	path, err := GetGitPath(JournalPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return readJournalFile(path)
+}
+
+// appendJournalFile writes entry as one line at the end of the file at
+// path, creating it and its directory if needed.
+func appendJournalFile(path string, entry JournalEntry) error {
+	var line bytes.Buffer
+	encoder := json.NewEncoder(&line)
+	encoder.SetEscapeHTML(false) // keep identities such as "Name <email>" readable
+	if err := encoder.Encode(entry); err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	// A single write keeps concurrent appends from interleaving
+	if _, err := file.Write(line.Bytes()); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// readJournalFile reads the entries of the journal at path, skipping the
+// lines that are not entries, such as one cut short by a crash.
+func readJournalFile(path string) ([]JournalEntry, []int, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	var entries []JournalEntry
+	var invalid []int
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxJournalLine)
+	for number := 1; scanner.Scan(); number++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Operation == "" {
+			invalid = append(invalid, number)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return entries, invalid, nil
+}
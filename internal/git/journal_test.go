@@ -0,0 +1,65 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestJournalFile tests that entries round-trip through the journal and
+// that unreadable lines are skipped.
+func TestJournalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gitcommit", "journal.jsonl")
+
+	if entries, invalid, err := readJournalFile(path); err != nil || entries != nil || invalid != nil {
+		t.Fatalf("readJournalFile() of a missing journal = %v, %v, %v", entries, invalid, err)
+	}
+
+	date := time.Date(2025, 2, 5, 20, 19, 19, 0, time.FixedZone("", 3600))
+	first := JournalEntry{
+		Time:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Operation: "commit",
+		InputDate: "2025-02-05 20:19:19",
+		Date:      date,
+		Refs:      []JournalRef{{Name: "refs/heads/main", New: "abc"}},
+		Commits:   []JournalCommit{{New: "abc", AuthorDate: date, CommitterDate: date}},
+		Identity:  "Jane Doe <jane@example.com>",
+		Version:   "1.2.3",
+		Backup:    "20260102T030405.000000Z",
+	}
+	second := JournalEntry{Time: first.Time.Add(time.Hour), Operation: "undo", Restored: first.Backup}
+
+	if err := appendJournalFile(path, first); err != nil {
+		t.Fatalf("appendJournalFile() unexpected error: %v", err)
+	}
+	// A line cut short by a crash
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	_, _ = file.WriteString("{\"time\":\"2026\n")
+	file.Close()
+	if err := appendJournalFile(path, second); err != nil {
+		t.Fatalf("appendJournalFile() unexpected error: %v", err)
+	}
+
+	entries, invalid, err := readJournalFile(path)
+	if err != nil {
+		t.Fatalf("readJournalFile() unexpected error: %v", err)
+	}
+	if !slices.Equal(invalid, []int{2}) {
+		t.Errorf("invalid lines = %v, expected [2]", invalid)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("readJournalFile() returned %d entries, expected 2", len(entries))
+	}
+	if got := entries[0]; got.Operation != "commit" || !got.Date.Equal(date) || got.Date.Format("-0700") != "+0100" ||
+		got.Refs[0] != first.Refs[0] || got.Commits[0].New != "abc" || got.Backup != first.Backup {
+		t.Errorf("entries[0] = %+v, expected %+v", got, first)
+	}
+	if got := entries[1]; got.Operation != "undo" || !got.Date.IsZero() || got.Restored != first.Backup {
+		t.Errorf("entries[1] = %+v, expected %+v", got, second)
+	}
+}
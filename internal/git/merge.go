@@ -0,0 +1,128 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// MergeMode selects how git merge records the result.
+type MergeMode string
+
+const (
+	// MergeFastForward fast-forwards when possible, else creates a merge commit (git merge --ff).
+	MergeFastForward MergeMode = "ff"
+	// MergeNoFastForward always creates a merge commit (git merge --no-ff).
+	MergeNoFastForward MergeMode = "no-ff"
+	// MergeFastForwardOnly refuses anything but a fast-forward (git merge --ff-only).
+	MergeFastForwardOnly MergeMode = "ff-only"
+	// MergeSquash stages the merged changes for a single ordinary commit (git merge --squash).
+	MergeSquash MergeMode = "squash"
+)
+
+// ErrMergeConflict is returned when git merge stops to let the user resolve conflicts.
+var ErrMergeConflict = errors.New("merge stopped on conflicts")
+
+// MergeOptions holds optional settings for ExecuteMerge.
+type MergeOptions struct {
+	// Mode selects fast-forward, merge commit or squash behaviour.
+	Mode MergeMode
+
+	// Messages are the paragraphs of the merge commit message; empty uses git's default.
+	Messages []string
+
+	// Sign signs the merge commit, with SignKey if set.
+	Sign bool
+
+	// SignKey selects the signing key instead of user.signingkey.
+	SignKey string
+
+	// NoSign disables signing even when commit.gpgsign is set.
+	NoSign bool
+
+	// ShowOutput echoes git's output to the terminal as it runs.
+	ShowOutput bool
+}
+
+// ExecuteMerge runs git merge with the author and committer dates of any
+// merge commit set to gitFormattedDate. With MergeSquash nothing is committed.
+// Returns an error wrapping ErrMergeConflict when git stops on conflicts.
+func ExecuteMerge(gitFormattedDate string, branches []string, options MergeOptions) error {
+	args := []string{"merge", "--" + string(options.Mode)}
+	if options.Mode != MergeSquash {
+		args = append(args, "--no-edit")
+		for _, message := range options.Messages {
+			args = append(args, "-m", message)
+		}
+	}
+	switch {
+	case options.Sign && options.SignKey != "":
+		args = append(args, "--gpg-sign="+options.SignKey)
+	case options.Sign:
+		args = append(args, "--gpg-sign")
+	case options.NoSign:
+		args = append(args, "--no-gpg-sign")
+	}
+	args = append(args, branches...)
+
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_DATE="+gitFormattedDate,
+		"GIT_COMMITTER_DATE="+gitFormattedDate,
+		"GIT_MERGE_AUTOEDIT=no",
+	)
+
+	var output bytes.Buffer
+	if options.ShowOutput {
+		cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+		cmd.Stderr = io.MultiWriter(os.Stderr, &output)
+	} else {
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+	}
+
+	if err := cmd.Run(); err != nil {
+		conflicts, conflictErr := GetConflictedPaths()
+		if conflictErr == nil && len(conflicts) > 0 {
+			return fmt.Errorf("%w: %s", ErrMergeConflict, strings.Join(conflicts, ", "))
+		}
+		return fmt.Errorf("git merge failed: %s: %w", GetGitError(output.Bytes()), err)
+	}
+
+	return nil
+}
+
+// GetConflictedPaths returns the paths with unresolved conflicts in the index.
+func GetConflictedPaths() ([]string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "diff", "--name-only", "--diff-filter=U", "-z")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicted paths: %w", err)
+	}
+
+	var paths []string
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// IsAncestor reports whether ancestor is reachable from rev (or is rev itself).
+func IsAncestor(ancestor, rev string) (bool, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "merge-base", "--is-ancestor", ancestor, rev)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("git merge-base failed: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// AddNote attaches message as the note of rev under the notes ref notesRef,
+// replacing any existing note.
+func AddNote(notesRef, rev, message string) error {
+	cmd := exec.CommandContext(context.Background(), "git", "notes", "--ref="+notesRef, "add", "-f", "-m", message, rev)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git notes add failed: %s: %w", GetGitError(output), err)
+	}
+	return nil
+}
+
+// GetNote returns the note of rev under notesRef, and whether there is one.
+func GetNote(notesRef, rev string) (string, bool, error) {
+	// git notes list exits 1 when the object has no note
+	list := exec.CommandContext(context.Background(), "git", "notes", "--ref="+notesRef, "list", rev)
+	if output, err := list.CombinedOutput(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("git notes list failed: %s: %w", GetGitError(output), err)
+	}
+
+	cmd := exec.CommandContext(context.Background(), "git", "notes", "--ref="+notesRef, "show", rev)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", false, fmt.Errorf("git notes show failed: %w", err)
+	}
+
+	return string(output), true, nil
+}
+
+// CopyNotes copies the notes under notesRef of the commits rewritten maps
+// from, onto the commits they map to, in one notes commit. Commits without a
+// note are skipped. It returns the number of notes copied.
+func CopyNotes(notesRef string, rewritten map[string]string) (int, error) {
+	// Each line of git notes list is "<note blob> <annotated object>"
+	list := exec.CommandContext(context.Background(), "git", "notes", "--ref="+notesRef, "list")
+	output, err := list.Output()
+	if err != nil {
+		return 0, fmt.Errorf("git notes list failed: %w", err)
+	}
+
+	var pairs strings.Builder
+	copied := 0
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if to, ok := rewritten[fields[1]]; ok {
+			fmt.Fprintf(&pairs, "%s %s\n", fields[1], to)
+			copied++
+		}
+	}
+	if copied == 0 {
+		return 0, nil
+	}
+
+	cmd := exec.CommandContext(context.Background(), "git", "notes", "--ref="+notesRef, "copy", "-f", "--stdin")
+	cmd.Stdin = strings.NewReader(pairs.String())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return 0, fmt.Errorf("git notes copy failed: %s: %w", GetGitError(output), err)
+	}
+	return copied, nil
+}
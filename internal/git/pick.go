@@ -0,0 +1,45 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CherryPickNoCommit applies the changes of rev to the index and working tree
+// without committing (git cherry-pick --no-commit).
+// Returns an error wrapping ErrMergeConflict when git stops on conflicts.
+func CherryPickNoCommit(rev string) error {
+	cmd := exec.CommandContext(context.Background(), "git", "cherry-pick", "--no-commit", rev)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		conflicts, conflictErr := GetConflictedPaths()
+		if conflictErr == nil && len(conflicts) > 0 {
+			return fmt.Errorf("%w: %s", ErrMergeConflict, strings.Join(conflicts, ", "))
+		}
+		return fmt.Errorf("git cherry-pick failed: %s: %w", GetGitError(output), err)
+	}
+	return nil
+}
+
+// ListRange returns the commits selected by a revision range such as
+// "main..feature", oldest first.
+func ListRange(revisionRange string) ([]string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "rev-list", "--reverse", "--topo-order", revisionRange, "--")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrUnknownRevision, revisionRange, GetGitError(output))
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// GetCommitSubject returns the first line of the message of the commit named by rev.
+func GetCommitSubject(rev string) (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "log", "-1", "--format=%s", rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read subject of %s: %w", rev, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
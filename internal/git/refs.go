@@ -0,0 +1,122 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// ShortHashLength is the number of hex digits shown for abbreviated commit hashes.
+	ShortHashLength = 7
+
+	// refTargetFields is the number of fields ListRefTargets reads per ref.
+	refTargetFields = 5
+)
+
+var (
+	// ErrUnknownRevision is returned when a revision does not name a commit.
+	ErrUnknownRevision = errors.New("unknown revision")
+)
+
+// chronologyRefNamespaces are the ref namespaces considered by ListRefs.
+// Notes, stashes and the backups under BackupRefPrefix are deliberately excluded:
+// their commits carry wall-clock dates unrelated to project history.
+var chronologyRefNamespaces = []string{"refs/heads", "refs/remotes", "refs/tags"}
+
+// ResolveCommit resolves rev to the full hash of the commit it names.
+// Annotated tags are peeled to the commit they point to.
+// Returns ErrUnknownRevision if rev does not name a commit.
+func ResolveCommit(rev string) (string, error) {
+	cmd := exec.CommandContext(context.Background(),
+		"git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownRevision, rev)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ListRefs returns the full names of all local branches, remote-tracking
+// branches and tags in the repository.
+func ListRefs() ([]string, error) {
+	args := append([]string{"for-each-ref", "--format=%(refname)"}, chronologyRefNamespaces...)
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs: %w", err)
+	}
+
+	// Ref names cannot contain whitespace, so one ref per field
+	return strings.Fields(string(output)), nil
+}
+
+// RefTarget is a ref and the object it points to.
+type RefTarget struct {
+	// Name is the full name of the ref, such as refs/tags/v1.0.
+	Name string
+
+	// Hash is the object the ref points to.
+	Hash string
+
+	// Type is the type of that object: "commit", or "tag" for an annotated tag.
+	Type string
+
+	// Commit is the commit the ref resolves to once annotated tags are
+	// peeled, or empty when it names no commit.
+	Commit string
+}
+
+// ListRefTargets returns the refs under the given namespaces, such as
+// refs/tags, with the objects they point to.
+func ListRefTargets(namespaces ...string) ([]RefTarget, error) {
+	args := append([]string{
+		"for-each-ref", "--format=%(refname)%00%(objectname)%00%(objecttype)%00%(*objectname)%00%(*objecttype)",
+	}, namespaces...)
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs: %w", err)
+	}
+
+	var targets []RefTarget
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != refTargetFields {
+			continue
+		}
+		target := RefTarget{Name: fields[0], Hash: fields[1], Type: fields[2]}
+		switch {
+		case target.Type == "commit":
+			target.Commit = target.Hash
+		case target.Type == "tag" && fields[4] == "commit":
+			target.Commit = fields[3]
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// ShortHash abbreviates a full commit hash for display.
+func ShortHash(hash string) string {
+	if len(hash) <= ShortHashLength {
+		return hash
+	}
+	return hash[:ShortHashLength]
+}
+
+// RemoteBranchesContaining returns the short names of the remote-tracking
+// branches from which rev is reachable, i.e. where it has been published.
+func RemoteBranchesContaining(rev string) ([]string, error) {
+	cmd := exec.CommandContext(context.Background(),
+		"git", "for-each-ref", "--contains", rev, "--format=%(refname:short)", "refs/remotes")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find remote branches containing %s: %w", rev, err)
+	}
+
+	return strings.Fields(string(output)), nil
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+// TestResolveCommitAndListRefs tests ref listing and resolution.
+func TestResolveCommitAndListRefs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	commands := [][]string{
+		{"init", "--initial-branch=main"},
+		{"config", "--local", "user.name", "Test User"},
+		{"config", "--local", "user.email", "test@example.com"},
+		{"commit", "--allow-empty", "-m", "first"},
+		{"tag", "-a", "v1.0.0", "-m", "release"},
+		{"branch", "feature"},
+		{"notes", "add", "-m", "note"},
+	}
+	for _, args := range commands {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to change to test directory: %v", err)
+	}
+
+	refs, err := ListRefs()
+	if err != nil {
+		t.Fatalf("ListRefs() unexpected error: %v", err)
+	}
+	expected := []string{"refs/heads/feature", "refs/heads/main", "refs/tags/v1.0.0"}
+	if !slices.Equal(refs, expected) {
+		t.Errorf("ListRefs() = %v, expected %v", refs, expected)
+	}
+
+	head, err := ResolveCommit("HEAD")
+	if err != nil {
+		t.Fatalf("ResolveCommit(HEAD) unexpected error: %v", err)
+	}
+
+	// Annotated tags are peeled to their commit
+	tagged, err := ResolveCommit("v1.0.0")
+	if err != nil {
+		t.Fatalf("ResolveCommit(v1.0.0) unexpected error: %v", err)
+	}
+	if tagged != head {
+		t.Errorf("ResolveCommit(v1.0.0) = %s, expected %s", tagged, head)
+	}
+
+	if _, err := ResolveCommit("does-not-exist"); !errors.Is(err, ErrUnknownRevision) {
+		t.Errorf("ResolveCommit(does-not-exist) error = %v, expected ErrUnknownRevision", err)
+	}
+
+	if got := ShortHash(head); len(got) != ShortHashLength {
+		t.Errorf("ShortHash(%s) = %q, expected %d characters", head, got, ShortHashLength)
+	}
+}
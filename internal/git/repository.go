@@ -14,6 +14,12 @@ import (
 const (
 	// GitExitCodeNoCommits is the exit code returned by git log when there are no commits.
 	GitExitCodeNoCommits = 128
+
+	// commitDatesFormat prints a commit's hash, author date, committer date and parents on one line.
+	commitDatesFormat = "%H %aI %cI %P"
+
+	// commitDatesFields is the number of fields printed by commitDatesFormat before the parents.
+	commitDatesFields = 3
 )
 
 var (
@@ -23,6 +29,8 @@ var (
 	ErrNotGitRepository = errors.New("not a git repository")
 	// ErrGitDirNotFound is returned when the .git directory cannot be found.
 	ErrGitDirNotFound = errors.New(".git directory not found")
+	// ErrUnexpectedOutput is returned when git prints output that cannot be interpreted.
+	ErrUnexpectedOutput = errors.New("unexpected git output")
 )
 
 // Repository represents a Git repository and its properties.
@@ -45,33 +53,110 @@ func IsGitRepository() bool {
 	return true
 }
 
-// GetLastCommitDate retrieves the date of the last commit in the repository.
+// CommitDates holds the identifying hash, parents and both dates of a single commit.
+type CommitDates struct {
+	// Hash is the full object name of the commit.
+	Hash string
+
+	// AuthorDate is the date the change was originally authored (%aI).
+	AuthorDate time.Time
+
+	// CommitterDate is the date the commit object was created (%cI).
+	// It differs from AuthorDate after rebases, amends and cherry-picks.
+	CommitterDate time.Time
+
+	// Parents holds the full object names of the commit's parents, in order.
+	Parents []string
+}
+
+// GetLastCommitDate retrieves the author date of the last commit in the repository.
 // Returns an error if there are no commits or if not in a Git repository.
 func GetLastCommitDate() (time.Time, error) {
-	// Use git log to get the last commit date in RFC3339 format
-	cmd := exec.CommandContext(context.Background(), "git", "log", "-1", "--format=%aI")
+	dates, err := GetLastCommitDates()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dates.AuthorDate, nil
+}
+
+// GetLastCommitDates retrieves the author and committer dates of the last commit.
+// Returns ErrNoCommits if the repository has no commits yet.
+func GetLastCommitDates() (CommitDates, error) {
+	return GetCommitDates("HEAD")
+}
+
+// GetCommitDates retrieves the author and committer dates of the commit named by rev.
+// Returns ErrNoCommits if rev cannot be resolved because the repository has no commits.
+func GetCommitDates(rev string) (CommitDates, error) {
+	// Use git log to get the hash, both dates in strict ISO 8601 format, and the parents
+	cmd := exec.CommandContext(context.Background(),
+		"git", "log", "-1", "--format="+commitDatesFormat, rev, "--")
 	output, err := cmd.Output()
 	if err != nil {
 		// Check if it's because there are no commits
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) && exitErr.ExitCode() == GitExitCodeNoCommits {
-			return time.Time{}, ErrNoCommits
+			return CommitDates{}, ErrNoCommits
+		}
+		return CommitDates{}, fmt.Errorf("failed to get commit dates of %s: %w", rev, err)
+	}
+
+	return parseCommitDates(string(output))
+}
+
+// GetCommitsDates retrieves the dates of several commits with a single git invocation.
+// Results are returned in the same order as hashes.
+func GetCommitsDates(hashes []string) ([]CommitDates, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	args := append([]string{"log", "--no-walk=unsorted", "--format=" + commitDatesFormat}, hashes...)
+	cmd := exec.CommandContext(context.Background(), "git", append(args, "--")...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit dates: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	commits := make([]CommitDates, 0, len(lines))
+	for _, line := range lines {
+		dates, err := parseCommitDates(line)
+		if err != nil {
+			return nil, err
 		}
-		return time.Time{}, fmt.Errorf("failed to get last commit date: %w", err)
+		commits = append(commits, dates)
 	}
 
-	// Parse the date
-	dateStr := strings.TrimSpace(string(output))
-	if dateStr == "" {
-		return time.Time{}, ErrNoCommits
+	return commits, nil
+}
+
+// parseCommitDates parses one line of commitDatesFormat output from git log.
+func parseCommitDates(output string) (CommitDates, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return CommitDates{}, ErrNoCommits
+	}
+	if len(fields) < commitDatesFields {
+		return CommitDates{}, fmt.Errorf("%w: %q", ErrUnexpectedOutput, output)
+	}
+
+	authorDate, err := time.Parse(time.RFC3339, fields[1])
+	if err != nil {
+		return CommitDates{}, fmt.Errorf("failed to parse author date %q: %w", fields[1], err)
 	}
 
-	commitDate, err := time.Parse(time.RFC3339, dateStr)
+	committerDate, err := time.Parse(time.RFC3339, fields[2])
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to parse commit date %q: %w", dateStr, err)
+		return CommitDates{}, fmt.Errorf("failed to parse committer date %q: %w", fields[2], err)
 	}
 
-	return commitDate, nil
+	return CommitDates{
+		Hash:          fields[0],
+		AuthorDate:    authorDate,
+		CommitterDate: committerDate,
+		Parents:       fields[commitDatesFields:],
+	}, nil
 }
 
 // NewRepository creates a Repository instance for the current directory.
@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // TestIsGitRepository tests the Git repository detection logic.
@@ -145,3 +146,61 @@ func TestGetLastCommitDate(t *testing.T) {
 		})
 	}
 }
+
+// TestGetLastCommitDates tests that author and committer dates are read separately.
+func TestGetLastCommitDates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Initialize a repository with a commit whose author date predates its committer date
+	commands := [][]string{
+		{"init"},
+		{"config", "--local", "user.name", "Test User"},
+		{"config", "--local", "user.email", "test@example.com"},
+		{"commit", "--allow-empty", "-m", "rebased commit"},
+	}
+	for _, args := range commands {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_DATE=2025-01-10T09:00:00+00:00",
+			"GIT_COMMITTER_DATE=2025-03-01T18:30:00+00:00",
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to change to test directory: %v", err)
+	}
+
+	dates, err := GetLastCommitDates()
+	if err != nil {
+		t.Fatalf("GetLastCommitDates() unexpected error: %v", err)
+	}
+
+	if dates.Hash == "" {
+		t.Errorf("GetLastCommitDates() returned an empty hash")
+	}
+	if got := dates.AuthorDate.UTC().Format(time.RFC3339); got != "2025-01-10T09:00:00Z" {
+		t.Errorf("AuthorDate = %s, expected 2025-01-10T09:00:00Z", got)
+	}
+	if got := dates.CommitterDate.UTC().Format(time.RFC3339); got != "2025-03-01T18:30:00Z" {
+		t.Errorf("CommitterDate = %s, expected 2025-03-01T18:30:00Z", got)
+	}
+
+	// GetLastCommitDate keeps reporting the author date
+	authorDate, err := GetLastCommitDate()
+	if err != nil {
+		t.Fatalf("GetLastCommitDate() unexpected error: %v", err)
+	}
+	if !authorDate.Equal(dates.AuthorDate) {
+		t.Errorf("GetLastCommitDate() = %v, expected author date %v", authorDate, dates.AuthorDate)
+	}
+}
@@ -0,0 +1,432 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// rawDateZoneLayout formats the zone offset of git's raw date format.
+	rawDateZoneLayout = "-0700"
+
+	// signatureHeader is the commit header holding a GPG, SSH or X.509 signature.
+	signatureHeader = "gpgsig"
+
+	// encodingHeader is the commit header naming the encoding of the message.
+	encodingHeader = "encoding"
+
+	// mergeTagHeader is the commit header holding a signed tag that a merge
+	// commit merged, with the tag object as its continuation lines.
+	mergeTagHeader = "mergetag"
+)
+
+var (
+	// ErrMalformedCommit is returned when a commit object cannot be parsed.
+	ErrMalformedCommit = errors.New("malformed commit object")
+
+	// ErrRefChanged is returned when a ref no longer has the value it was read with.
+	ErrRefChanged = errors.New("ref changed concurrently")
+
+	// ErrCommitSigningFailed is returned when git could not sign a rewritten commit.
+	ErrCommitSigningFailed = errors.New("commit could not be signed")
+
+	// ErrHeadersNotSignable is returned when a commit to be signed carries
+	// headers, such as mergetag, that git commit-tree cannot write.
+	ErrHeadersNotSignable = errors.New("commit headers cannot be kept when signing")
+)
+
+// CommitObject holds the parts of a commit that a rewrite carries over.
+type CommitObject struct {
+	// Hash is the full object name of the commit.
+	Hash string
+
+	// Tree is the object name of the commit's tree.
+	Tree string
+
+	// Parents holds the full object names of the commit's parents, in order.
+	Parents []string
+
+	// Author is who wrote the change.
+	Author Identity
+
+	// AuthorDate is when the change was written, in the author's recorded zone.
+	AuthorDate time.Time
+
+	// Committer is who created the commit object.
+	Committer Identity
+
+	// CommitterDate is when the commit object was created, in the committer's recorded zone.
+	CommitterDate time.Time
+
+	// Signed reports whether the commit carries a signature.
+	Signed bool
+
+	// Headers holds the other headers, such as encoding and mergetag, in
+	// order, each with its continuation lines, as they appear in the object.
+	Headers []string
+
+	// Message is the commit message, byte for byte.
+	Message string
+}
+
+// MergeTag is a signed tag recorded by a merge commit in a mergetag header.
+type MergeTag struct {
+	// Object is the commit the tag points to, one of the merged parents.
+	Object string
+
+	// Name is the tag name.
+	Name string
+}
+
+// MergeTags returns the tags recorded in the commit's mergetag headers.
+func (c CommitObject) MergeTags() []MergeTag {
+	var tags []MergeTag
+	for _, header := range c.Headers {
+		if tag, ok := parseMergeTag(header); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// WithoutMergeTags returns a copy of the commit without the mergetag
+// headers for which drop reports true.
+func (c CommitObject) WithoutMergeTags(drop func(MergeTag) bool) CommitObject {
+	headers := make([]string, 0, len(c.Headers))
+	for _, header := range c.Headers {
+		if tag, ok := parseMergeTag(header); ok && drop(tag) {
+			continue
+		}
+		headers = append(headers, header)
+	}
+	c.Headers = headers
+	return c
+}
+
+// parseMergeTag reads the object and name of the tag in a mergetag header.
+func parseMergeTag(header string) (MergeTag, bool) {
+	key, value, _ := strings.Cut(header, " ")
+	if key != mergeTagHeader {
+		return MergeTag{}, false
+	}
+
+	// The tag's own headers end at its first blank line, before its message
+	var tag MergeTag
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimPrefix(line, " ")
+		if line == "" {
+			break
+		}
+		field, content, _ := strings.Cut(line, " ")
+		switch field {
+		case "object":
+			tag.Object = content
+		case "tag":
+			tag.Name = content
+		}
+	}
+	return tag, true
+}
+
+// Subject returns the first line of the commit message.
+func (c CommitObject) Subject() string {
+	subject, _, _ := strings.Cut(strings.TrimLeft(c.Message, "\n"), "\n")
+	return subject
+}
+
+// ReadCommit reads the commit named by rev.
+func ReadCommit(rev string) (CommitObject, error) {
+	hash, err := ResolveCommit(rev)
+	if err != nil {
+		return CommitObject{}, err
+	}
+
+	cmd := exec.CommandContext(context.Background(), "git", "cat-file", "commit", hash)
+	output, err := cmd.Output()
+	if err != nil {
+		return CommitObject{}, fmt.Errorf("failed to read commit %s: %w", hash, err)
+	}
+
+	return parseCommitObject(hash, string(output))
+}
+
+// parseCommitObject parses the raw content of a commit object.
+func parseCommitObject(hash, raw string) (CommitObject, error) {
+	headers, message, found := strings.Cut(raw, "\n\n")
+	if !found {
+		headers = strings.TrimSuffix(raw, "\n")
+	}
+
+	commit := CommitObject{Hash: hash, Message: message}
+	var hasAuthor, hasCommitter, extra bool
+	for _, line := range strings.Split(headers, "\n") {
+		// Continuation lines of multi-line headers such as gpgsig start with a space
+		if strings.HasPrefix(line, " ") {
+			if extra {
+				commit.Headers[len(commit.Headers)-1] += "\n" + line
+			}
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		var err error
+		extra = false
+		switch key {
+		case "tree":
+			commit.Tree = value
+		case "parent":
+			commit.Parents = append(commit.Parents, value)
+		case "author":
+			commit.Author, commit.AuthorDate, err = parseSignatureLine(value)
+			hasAuthor = true
+		case "committer":
+			commit.Committer, commit.CommitterDate, err = parseSignatureLine(value)
+			hasCommitter = true
+		case signatureHeader, signatureHeader + "-sha256":
+			commit.Signed = true
+		default:
+			commit.Headers = append(commit.Headers, line)
+			extra = true
+		}
+		if err != nil {
+			return CommitObject{}, fmt.Errorf("%w %s: %w", ErrMalformedCommit, hash, err)
+		}
+	}
+
+	if commit.Tree == "" || !hasAuthor || !hasCommitter {
+		return CommitObject{}, fmt.Errorf("%w %s: missing tree, author or committer", ErrMalformedCommit, hash)
+	}
+	return commit, nil
+}
+
+// parseSignatureLine parses the value of an author or committer header:
+// "Name <email> <unix-seconds> <+hhmm>". The identity is taken as recorded,
+// since git accepts emails that ParseIdentity would reject.
+func parseSignatureLine(value string) (Identity, time.Time, error) {
+	end := strings.LastIndex(value, ">")
+	if end < 0 {
+		return Identity{}, time.Time{}, fmt.Errorf("%w: %q", ErrInvalidIdentity, value)
+	}
+
+	identity, err := parseRecordedIdentity(value[:end+1])
+	if err != nil {
+		return Identity{}, time.Time{}, err
+	}
+
+	date, err := ParseRawDate(strings.TrimSpace(value[end+1:]))
+	if err != nil {
+		return Identity{}, time.Time{}, err
+	}
+	return identity, date, nil
+}
+
+// ParseRawDate parses git's raw date format, "<unix-seconds> <+hhmm>", into
+// a time in the recorded zone offset.
+func ParseRawDate(raw string) (time.Time, error) {
+	seconds, zone, found := strings.Cut(raw, " ")
+	if !found {
+		return time.Time{}, fmt.Errorf("%w: date %q", ErrUnexpectedOutput, raw)
+	}
+
+	unix, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date %q", ErrUnexpectedOutput, raw)
+	}
+
+	offset, err := time.Parse(rawDateZoneLayout, zone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: zone %q", ErrUnexpectedOutput, zone)
+	}
+	_, offsetSeconds := offset.Zone()
+
+	return time.Unix(unix, 0).In(time.FixedZone("", offsetSeconds)), nil
+}
+
+// FormatRawDate formats t in git's raw date format, keeping its zone offset,
+// so that dates read from a commit are written back unchanged.
+func FormatRawDate(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10) + " " + t.Format(rawDateZoneLayout)
+}
+
+// WriteCommit creates a commit object from commit's tree, parents,
+// identities, dates, headers and message, and returns its hash. commit.Hash
+// and commit.Signed are ignored: the new commit is signed with the configured
+// key (user.signingkey, gpg.format) when sign is set, and not signed
+// otherwise. Returns an error wrapping ErrCommitSigningFailed if git could
+// not sign it, or ErrHeadersNotSignable if it carries headers other than
+// encoding, which git commit-tree cannot write.
+func WriteCommit(commit CommitObject, sign bool) (string, error) {
+	encoding := ""
+	for _, header := range commit.Headers {
+		key, value, _ := strings.Cut(header, " ")
+		if key != encodingHeader {
+			if sign {
+				return "", fmt.Errorf("%w: %s header of %s", ErrHeadersNotSignable, key, ShortHash(commit.Hash))
+			}
+			return writeCommitObject(commit)
+		}
+		encoding = value
+	}
+
+	args := []string{"commit-tree", commit.Tree}
+	if encoding != "" {
+		// commit-tree records the configured encoding in the encoding header
+		args = append([]string{"-c", "i18n.commitEncoding=" + encoding}, args...)
+	}
+	for _, parent := range commit.Parents {
+		args = append(args, "-p", parent)
+	}
+	if sign {
+		args = append(args, "--gpg-sign")
+	} else {
+		args = append(args, "--no-gpg-sign")
+	}
+	args = append(args, "-F", "-")
+
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME="+commit.Author.Name,
+		"GIT_AUTHOR_EMAIL="+commit.Author.Email,
+		"GIT_AUTHOR_DATE="+FormatRawDate(commit.AuthorDate),
+		"GIT_COMMITTER_NAME="+commit.Committer.Name,
+		"GIT_COMMITTER_EMAIL="+commit.Committer.Email,
+		"GIT_COMMITTER_DATE="+FormatRawDate(commit.CommitterDate),
+	)
+	cmd.Stdin = strings.NewReader(commit.Message)
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git commit-tree failed: %w", err)
+		}
+		if sign && ClassifyFailure(string(exitErr.Stderr)) == FailureSigningFailed {
+			return "", fmt.Errorf("%w: %s", ErrCommitSigningFailed, GetGitError(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git commit-tree failed: %s: %w", GetGitError(exitErr.Stderr), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// writeCommitObject writes commit as a raw, unsigned object, keeping its
+// headers in order, and returns its hash.
+func writeCommitObject(commit CommitObject) (string, error) {
+	var raw strings.Builder
+	fmt.Fprintf(&raw, "tree %s\n", commit.Tree)
+	for _, parent := range commit.Parents {
+		fmt.Fprintf(&raw, "parent %s\n", parent)
+	}
+	fmt.Fprintf(&raw, "author %s %s\n", commit.Author, FormatRawDate(commit.AuthorDate))
+	fmt.Fprintf(&raw, "committer %s %s\n", commit.Committer, FormatRawDate(commit.CommitterDate))
+	for _, header := range commit.Headers {
+		raw.WriteString(header + "\n")
+	}
+	raw.WriteString("\n" + commit.Message)
+
+	cmd := exec.CommandContext(context.Background(), "git", "hash-object", "-t", "commit", "-w", "--stdin")
+	cmd.Stdin = strings.NewReader(raw.String())
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git hash-object failed: %s: %w", GetGitError(exitErr.Stderr), err)
+		}
+		return "", fmt.Errorf("git hash-object failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// UpdateRef points ref at newValue, provided it still points at oldValue,
+// and records reason in its reflog.
+// Returns an error wrapping ErrRefChanged if ref moved in the meantime.
+func UpdateRef(ref, newValue, oldValue, reason string) error {
+	cmd := exec.CommandContext(context.Background(),
+		"git", "update-ref", "--create-reflog", "-m", reason, ref, newValue, oldValue)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		current, resolveErr := ResolveCommit(ref)
+		if resolveErr == nil && current != oldValue {
+			return fmt.Errorf("%w: %s now points at %s", ErrRefChanged, ref, ShortHash(current))
+		}
+		return fmt.Errorf("git update-ref failed: %s: %w", GetGitError(output), err)
+	}
+	return nil
+}
+
+// RefUpdate moves a ref from Old to New. An empty New deletes the ref.
+type RefUpdate struct {
+	Name     string
+	Old, New string
+}
+
+// UpdateRefs applies updates in one transaction, recording reason in the
+// reflog of each ref: either every ref moves or none does.
+// Returns an error wrapping ErrRefChanged if a ref moved in the meantime.
+func UpdateRefs(updates []RefUpdate, reason string) error {
+	var commands strings.Builder
+	for _, update := range updates {
+		if update.New == "" {
+			fmt.Fprintf(&commands, "delete %s %s\n", update.Name, update.Old)
+		} else {
+			fmt.Fprintf(&commands, "update %s %s %s\n", update.Name, update.New, update.Old)
+		}
+	}
+
+	cmd := exec.CommandContext(context.Background(),
+		"git", "update-ref", "--create-reflog", "-m", reason, "--stdin")
+	cmd.Stdin = strings.NewReader(commands.String())
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		for _, update := range updates {
+			if current := ResolveRef(update.Name); current != update.Old {
+				return fmt.Errorf("%w: %s now points at %s", ErrRefChanged, update.Name, ShortHash(current))
+			}
+		}
+		return fmt.Errorf("git update-ref failed: %s: %w", GetGitError(output), err)
+	}
+	return nil
+}
+
+// GetCurrentBranch returns the full name of the branch HEAD points to, or
+// an empty string when HEAD is detached.
+func GetCurrentBranch() (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "symbolic-ref", "--quiet", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetFullRefName returns the full name of the ref rev names, such as
+// refs/heads/main for main, or an empty string if rev is not a ref.
+func GetFullRefName(rev string) (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "rev-parse", "--symbolic-full-name", rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownRevision, rev)
+	}
+	name, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return name, nil
+}
+
+// ListUnpublished returns the commits of a revision range that no
+// remote-tracking branch contains.
+func ListUnpublished(revisionRange string) ([]string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "rev-list", revisionRange, "--not", "--remotes", "--")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrUnknownRevision, revisionRange, GetGitError(output))
+	}
+	return strings.Fields(string(output)), nil
+}
@@ -0,0 +1,120 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestParseCommitObject tests parsing of raw commit objects.
+func TestParseCommitObject(t *testing.T) {
+	raw := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"parent 1111111111111111111111111111111111111111\n" +
+		"parent 2222222222222222222222222222222222222222\n" +
+		"author Jane Doe <jane@example.com> 1738783159 +0100\n" +
+		"committer John Roe <john@example.com> 1738786759 -0230\n" +
+		"gpgsig -----BEGIN SSH SIGNATURE-----\n" +
+		" U1NIU0lHAAAAAQ==\n" +
+		" -----END SSH SIGNATURE-----\n" +
+		"\n" +
+		"Merge branch 'side'\n\nWith a body.\n"
+
+	commit, err := parseCommitObject("abc", raw)
+	if err != nil {
+		t.Fatalf("parseCommitObject() unexpected error: %v", err)
+	}
+
+	if commit.Tree != "4b825dc642cb6eb9a060e54bf8d69288fbee4904" {
+		t.Errorf("Tree = %q", commit.Tree)
+	}
+	if !slices.Equal(commit.Parents, []string{
+		"1111111111111111111111111111111111111111", "2222222222222222222222222222222222222222",
+	}) {
+		t.Errorf("Parents = %v", commit.Parents)
+	}
+	if commit.Author.String() != "Jane Doe <jane@example.com>" || commit.Committer.String() != "John Roe <john@example.com>" {
+		t.Errorf("Author = %v, Committer = %v", commit.Author, commit.Committer)
+	}
+	if got := FormatRawDate(commit.AuthorDate); got != "1738783159 +0100" {
+		t.Errorf("AuthorDate = %s", got)
+	}
+	if got := FormatRawDate(commit.CommitterDate); got != "1738786759 -0230" {
+		t.Errorf("CommitterDate = %s", got)
+	}
+	if !commit.Signed {
+		t.Error("Signed = false, expected true")
+	}
+	if commit.Message != "Merge branch 'side'\n\nWith a body.\n" || commit.Subject() != "Merge branch 'side'" {
+		t.Errorf("Message = %q, Subject = %q", commit.Message, commit.Subject())
+	}
+}
+
+// TestParseCommitObjectRecorded tests that identities are taken as recorded
+// and that the other headers are kept in order.
+func TestParseCommitObjectRecorded(t *testing.T) {
+	raw := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"author T <build> 1738783159 +0100\n" +
+		"committer Bot <> 1738783159 +0100\n" +
+		"encoding ISO-8859-1\n" +
+		"mergetag object 1111111111111111111111111111111111111111\n" +
+		" type commit\n" +
+		" tag v1.0\n" +
+		"gpgsig-sha256 -----BEGIN PGP SIGNATURE-----\n" +
+		" -----END PGP SIGNATURE-----\n" +
+		"\n" +
+		"Caf\xe9\n"
+
+	commit, err := parseCommitObject("abc", raw)
+	if err != nil {
+		t.Fatalf("parseCommitObject() unexpected error: %v", err)
+	}
+
+	if commit.Author != (Identity{Name: "T", Email: "build"}) || commit.Committer != (Identity{Name: "Bot"}) {
+		t.Errorf("Author = %+v, Committer = %+v", commit.Author, commit.Committer)
+	}
+	expected := []string{
+		"encoding ISO-8859-1",
+		"mergetag object 1111111111111111111111111111111111111111\n type commit\n tag v1.0",
+	}
+	if !slices.Equal(commit.Headers, expected) {
+		t.Errorf("Headers = %q, want %q", commit.Headers, expected)
+	}
+	if !commit.Signed {
+		t.Error("Signed = false, expected true")
+	}
+	if commit.Message != "Caf\xe9\n" {
+		t.Errorf("Message = %q", commit.Message)
+	}
+}
+
+// TestParseCommitObjectErrors tests that incomplete commit objects are rejected.
+func TestParseCommitObjectErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing tree":    "author A <a@example.com> 1 +0000\ncommitter A <a@example.com> 1 +0000\n\nmsg\n",
+		"bad date":        "tree t\nauthor A <a@example.com> soon +0000\ncommitter A <a@example.com> 1 +0000\n\nmsg\n",
+		"missing email":   "tree t\nauthor A 1 +0000\ncommitter A <a@example.com> 1 +0000\n\nmsg\n",
+		"missing zone":    "tree t\nauthor A <a@example.com> 1\ncommitter A <a@example.com> 1 +0000\n\nmsg\n",
+		"missing author":  "tree t\ncommitter A <a@example.com> 1 +0000\n\nmsg\n",
+		"missing headers": "",
+	}
+
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseCommitObject("abc", raw); err == nil {
+				t.Errorf("parseCommitObject(%q) expected an error", raw)
+			}
+		})
+	}
+}
+
+// TestParseRawDate tests that raw dates keep their instant and zone offset.
+func TestParseRawDate(t *testing.T) {
+	for _, raw := range []string{"0 +0000", "1738783159 +0100", "1738783159 -0930", "1738783159 +1345"} {
+		date, err := ParseRawDate(raw)
+		if err != nil {
+			t.Fatalf("ParseRawDate(%q) unexpected error: %v", raw, err)
+		}
+		if got := FormatRawDate(date); got != raw {
+			t.Errorf("FormatRawDate(ParseRawDate(%q)) = %q", raw, got)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// SignatureFormatOpenPGP is git's default signature format (gpg.format).
+	SignatureFormatOpenPGP = "openpgp"
+
+	// SignatureFormatSSH signs with an SSH key (gpg.format=ssh).
+	SignatureFormatSSH = "ssh"
+
+	// SignatureFormatX509 signs with an X.509 certificate (gpg.format=x509).
+	SignatureFormatX509 = "x509"
+
+	// literalKeyPrefix marks an SSH public key given in user.signingkey itself.
+	literalKeyPrefix = "key::"
+
+	// signatureCreated is the status line gpg and gpgsm print once they signed.
+	signatureCreated = "\n[GNUPG:] SIG_CREATED "
+
+	// signatureFormat reads the verification status, signer and key of a commit.
+	signatureFormat = "%G?%x00%GS%x00%GK"
+	signatureFields = 3
+)
+
+// ErrSigningFailed is returned when the signing program could not sign.
+var ErrSigningFailed = errors.New("signing failed")
+
+// SigningConfig holds the repository's commit signing configuration.
+type SigningConfig struct {
+	// Sign is commit.gpgsign: whether commits are signed by default.
+	Sign bool
+
+	// Format is gpg.format: openpgp, x509 or ssh.
+	Format string
+
+	// Key is user.signingkey, or empty when git picks the key itself.
+	Key string
+}
+
+// GetSigningConfig reads commit.gpgsign, gpg.format and user.signingkey.
+func GetSigningConfig() (SigningConfig, error) {
+	sign, err := getConfig("--bool", "commit.gpgsign")
+	if err != nil {
+		return SigningConfig{}, err
+	}
+
+	format, err := getConfig("", "gpg.format")
+	if err != nil {
+		return SigningConfig{}, err
+	}
+	if format == "" {
+		format = SignatureFormatOpenPGP
+	}
+
+	key, err := getConfig("", "user.signingkey")
+	if err != nil {
+		return SigningConfig{}, err
+	}
+
+	return SigningConfig{Sign: sign == "true", Format: format, Key: key}, nil
+}
+
+// Signature describes the signature of a commit as verified by git.
+type Signature struct {
+	// Present reports whether the commit object carries a signature at all.
+	Present bool
+
+	// Status is git's %G? verification code: G, B, U, X, Y, R, E or N.
+	Status string
+
+	// Signer is the name of the signer (%GS), if known.
+	Signer string
+
+	// Key is the key or fingerprint used to sign (%GK), if known.
+	Key string
+}
+
+// Verified reports whether git found a good signature. A good signature from
+// a key of unknown validity (U) counts, since trust is a local GPG setting.
+func (s Signature) Verified() bool {
+	return s.Status == "G" || s.Status == "U"
+}
+
+// Describe returns a human-readable description of the verification status.
+func (s Signature) Describe() string {
+	switch s.Status {
+	case "G":
+		return "good signature"
+	case "U":
+		return "good signature, key of unknown validity"
+	case "B":
+		return "bad signature"
+	case "X":
+		return "good signature that has expired"
+	case "Y":
+		return "good signature made by an expired key"
+	case "R":
+		return "good signature made by a revoked key"
+	case "E":
+		return "signature cannot be checked"
+	default:
+		if s.Present {
+			return "signature cannot be checked"
+		}
+		return "no signature"
+	}
+}
+
+// VerifyCommitSignature checks the signature of the commit named by rev.
+// Verification problems are reported through the Signature, not as errors.
+func VerifyCommitSignature(rev string) (Signature, error) {
+	raw, err := exec.CommandContext(context.Background(), "git", "cat-file", "commit", rev).Output()
+	if err != nil {
+		return Signature{}, fmt.Errorf("failed to read commit %s: %w", rev, err)
+	}
+	header, _, _ := bytes.Cut(raw, []byte("\n\n"))
+
+	signature := Signature{Present: bytes.Contains(header, []byte("\ngpgsig"))}
+
+	// git prints verification problems (e.g. no allowed signers file) on stderr
+	cmd := exec.CommandContext(context.Background(), "git", "log", "-1", "--format="+signatureFormat, rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return Signature{}, fmt.Errorf("failed to verify signature of %s: %w", rev, err)
+	}
+
+	fields := strings.Split(strings.TrimRight(string(output), "\n"), "\x00")
+	if len(fields) != signatureFields {
+		return Signature{}, fmt.Errorf("%w: %q", ErrUnexpectedOutput, output)
+	}
+	signature.Status, signature.Signer, signature.Key = fields[0], fields[1], fields[2]
+
+	return signature, nil
+}
+
+// SignPayload signs payload with the configured key (user.signingkey,
+// gpg.format) and program (gpg.<format>.program), as git does for commits
+// and tags, and returns the detached signature. signer is the key to use
+// with OpenPGP and X.509 when user.signingkey is not set, as git uses the
+// committer. Returns an error wrapping ErrSigningFailed if the program fails.
+func SignPayload(payload string, signer Identity) (string, error) {
+	config, err := GetSigningConfig()
+	if err != nil {
+		return "", err
+	}
+	program, err := signingProgram(config.Format)
+	if err != nil {
+		return "", err
+	}
+
+	if config.Format == SignatureFormatSSH {
+		return signSSH(program, config.Key, payload)
+	}
+
+	key := config.Key
+	if key == "" {
+		key = signer.String()
+	}
+	cmd := exec.CommandContext(context.Background(), program, "--status-fd=2", "-bsau", key)
+	cmd.Stdin = strings.NewReader(payload)
+	var signature, status bytes.Buffer
+	cmd.Stdout = &signature
+	cmd.Stderr = &status
+	if err := cmd.Run(); err != nil || !strings.Contains("\n"+status.String(), signatureCreated) {
+		return "", fmt.Errorf("%w: %s failed to sign the data: %s", ErrSigningFailed, program, GetGitError(status.Bytes()))
+	}
+	return signature.String(), nil
+}
+
+// signingProgram returns the program git signs with for format: gpg.program
+// or gpg.openpgp.program, gpg.x509.program or gpg.ssh.program.
+func signingProgram(format string) (string, error) {
+	defaults := map[string]string{
+		SignatureFormatOpenPGP: "gpg",
+		SignatureFormatX509:    "gpgsm",
+		SignatureFormatSSH:     "ssh-keygen",
+	}
+	program, known := defaults[format]
+	if !known {
+		return "", fmt.Errorf("%w: unknown gpg.format %q", ErrSigningFailed, format)
+	}
+
+	keys := []string{"gpg." + format + ".program"}
+	if format == SignatureFormatOpenPGP {
+		keys = append(keys, "gpg.program")
+	}
+	for _, key := range keys {
+		configured, err := getConfig("", key)
+		if err != nil {
+			return "", err
+		}
+		if configured != "" {
+			return configured, nil
+		}
+	}
+	return program, nil
+}
+
+// signSSH signs payload with ssh-keygen -Y sign, as git does, with key a
+// file, a literal public key, or empty for gpg.ssh.defaultKeyCommand.
+func signSSH(program, key, payload string) (string, error) {
+	if key == "" {
+		command, err := getConfig("", "gpg.ssh.defaultKeyCommand")
+		if err != nil {
+			return "", err
+		}
+		if command == "" {
+			return "", fmt.Errorf("%w: user.signingkey or gpg.ssh.defaultKeyCommand needs to be configured", ErrSigningFailed)
+		}
+		output, err := exec.CommandContext(context.Background(), "sh", "-c", command).Output()
+		if err != nil {
+			return "", fmt.Errorf("%w: gpg.ssh.defaultKeyCommand failed: %w", ErrSigningFailed, err)
+		}
+		key, _, _ = strings.Cut(strings.TrimSpace(string(output)), "\n")
+	}
+
+	scratch, err := os.MkdirTemp("", "gitcommit-sign-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a signing directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	args := []string{"-Y", "sign", "-n", "git", "-f"}
+	literal, isLiteral := strings.CutPrefix(key, literalKeyPrefix)
+	if isLiteral || strings.HasPrefix(key, "ssh-") {
+		// A literal key is written to a file, and its private key is in the agent
+		keyFile := filepath.Join(scratch, "key.pub")
+		if err := os.WriteFile(keyFile, []byte(literal+"\n"), 0o600); err != nil {
+			return "", fmt.Errorf("failed to write the signing key: %w", err)
+		}
+		args = append(args, keyFile, "-U")
+	} else {
+		if rest, ok := strings.CutPrefix(key, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to expand %s: %w", key, err)
+			}
+			key = filepath.Join(home, rest)
+		}
+		args = append(args, key)
+	}
+
+	buffer := filepath.Join(scratch, "payload")
+	if err := os.WriteFile(buffer, []byte(payload), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write the data to sign: %w", err)
+	}
+	cmd := exec.CommandContext(context.Background(), program, append(args, buffer)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%w: %s -Y sign failed: %s", ErrSigningFailed, program, GetGitError(output))
+	}
+
+	signature, err := os.ReadFile(buffer + ".sig")
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read the signature: %w", ErrSigningFailed, err)
+	}
+	return string(signature), nil
+}
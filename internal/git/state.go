@@ -0,0 +1,148 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// GetGitPath resolves a path inside the git directory, such as "MERGE_HEAD".
+// Unlike GetGitDirectory it honours worktrees and GIT_DIR.
+func GetGitPath(name string) (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "rev-parse", "--git-path", name)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", ErrNotGitRepository
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMergeHeads returns the commits being merged when a merge is in progress.
+// There is more than one for an octopus merge.
+// Returns nil if no merge is in progress.
+func GetMergeHeads() ([]string, error) {
+	path, err := GetGitPath("MERGE_HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read MERGE_HEAD: %w", err)
+	}
+
+	return strings.Fields(string(content)), nil
+}
+
+// Operation is a multi-step git command that stopped to let the user commit.
+type Operation string
+
+const (
+	// OperationNone means no operation is in progress.
+	OperationNone Operation = ""
+	// OperationMerge is a merge waiting for its merge commit (MERGE_HEAD).
+	OperationMerge Operation = "merge"
+	// OperationCherryPick is a cherry-pick waiting to be committed (CHERRY_PICK_HEAD).
+	OperationCherryPick Operation = "cherry-pick"
+	// OperationRevert is a revert waiting to be committed (REVERT_HEAD).
+	OperationRevert Operation = "revert"
+	// OperationRebase is an interactive or apply-based rebase.
+	OperationRebase Operation = "rebase"
+	// OperationApplyMailbox is git am applying patches.
+	OperationApplyMailbox Operation = "am"
+)
+
+// operationMarkers maps paths in the git directory to the operation they
+// reveal, checked in order: a rebase may leave CHERRY_PICK_HEAD behind too.
+var operationMarkers = []struct {
+	path      string
+	operation Operation
+}{
+	{"rebase-merge", OperationRebase},
+	{"rebase-apply/applying", OperationApplyMailbox},
+	{"rebase-apply", OperationRebase},
+	{"MERGE_HEAD", OperationMerge},
+	{"CHERRY_PICK_HEAD", OperationCherryPick},
+	{"REVERT_HEAD", OperationRevert},
+}
+
+// Head returns the pseudo-ref naming the commit the operation is applying,
+// or "" for operations that have none.
+func (o Operation) Head() string {
+	switch o {
+	case OperationMerge:
+		return "MERGE_HEAD"
+	case OperationCherryPick:
+		return "CHERRY_PICK_HEAD"
+	case OperationRevert:
+		return "REVERT_HEAD"
+	case OperationNone, OperationRebase, OperationApplyMailbox:
+		return ""
+	default:
+		return ""
+	}
+}
+
+// GetOperationInProgress detects a merge, cherry-pick, revert, rebase or am
+// in progress from the files it leaves in the git directory.
+func GetOperationInProgress() (Operation, error) {
+	for _, marker := range operationMarkers {
+		path, err := GetGitPath(marker.path)
+		if err != nil {
+			return OperationNone, err
+		}
+		if _, err := os.Stat(path); err == nil {
+			return marker.operation, nil
+		}
+	}
+	return OperationNone, nil
+}
+
+// HasSequencer reports whether a multi-commit cherry-pick or revert is in
+// progress, which continues with --continue after each commit.
+func HasSequencer() bool {
+	path, err := GetGitPath("sequencer")
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// GetPreparedMessage returns the message git prepared in MERGE_MSG for a
+// merge, cherry-pick or revert, without comment lines such as "# Conflicts:".
+// Returns "" when there is none.
+func GetPreparedMessage() (string, error) {
+	return readPreparedMessage("MERGE_MSG")
+}
+
+// GetSquashMessage returns the message git merge --squash prepared in
+// SQUASH_MSG, without comment lines. Returns "" when there is none.
+func GetSquashMessage() (string, error) {
+	return readPreparedMessage("SQUASH_MSG")
+}
+
+// readPreparedMessage reads a message file from the git directory and strips comments.
+func readPreparedMessage(name string) (string, error) {
+	path, err := GetGitPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	return StripSpace(string(content), true)
+}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// gitExitCodeDifferences is the exit code of git diff --quiet when differences exist.
+	gitExitCodeDifferences = 1
+)
+
+// WorkingTreeStatus summarizes changes that are not part of the next commit.
+type WorkingTreeStatus struct {
+	// Unstaged is the number of tracked files modified in the working tree but not staged.
+	Unstaged int
+
+	// Untracked is the number of untracked files that are not ignored.
+	Untracked int
+}
+
+// HasStagedChanges reports whether the index differs from HEAD, or from the
+// empty tree when the repository has no commits yet.
+func HasStagedChanges() (bool, error) {
+	return HasIndexChanges("")
+}
+
+// HasIndexChanges is like HasStagedChanges but inspects the given index file
+// instead of the repository's index when indexFile is not empty.
+func HasIndexChanges(indexFile string) (bool, error) {
+	base := "HEAD"
+	if !HasCommits() {
+		emptyTree, err := GetEmptyTree()
+		if err != nil {
+			return false, err
+		}
+		base = emptyTree
+	}
+
+	cmd := exec.CommandContext(context.Background(), "git", "diff", "--cached", "--quiet", base, "--")
+	if indexFile != "" {
+		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+indexFile)
+	}
+	err := cmd.Run()
+	if err == nil {
+		return false, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == gitExitCodeDifferences {
+		return true, nil
+	}
+	return false, fmt.Errorf("failed to compare index with %s: %w", base, err)
+}
+
+// GetEmptyTree returns the object name of the empty tree in the repository's hash format.
+func GetEmptyTree() (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "hash-object", "-t", "tree", "--stdin")
+	cmd.Stdin = &bytes.Buffer{}
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to compute empty tree: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetWorkingTreeStatus counts unstaged and untracked files.
+func GetWorkingTreeStatus() (WorkingTreeStatus, error) {
+	unstaged, err := countPaths("diff", "--name-only", "-z")
+	if err != nil {
+		return WorkingTreeStatus{}, err
+	}
+
+	// ls-files is limited to the current directory unless given the top-level pathspec
+	untracked, err := countPaths("ls-files", "--others", "--exclude-standard", "-z", "--", ":/")
+	if err != nil {
+		return WorkingTreeStatus{}, err
+	}
+
+	return WorkingTreeStatus{Unstaged: unstaged, Untracked: untracked}, nil
+}
+
+// countPaths runs a git command printing NUL-terminated paths and counts them.
+func countPaths(args ...string) (int, error) {
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+
+	return bytes.Count(output, []byte{0}), nil
+}
@@ -0,0 +1,252 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// tagSignatureMarkers start the signature git appends to the message of a
+// signed tag, for OpenPGP, SSH and X.509 keys.
+var tagSignatureMarkers = []string{
+	"-----BEGIN PGP SIGNATURE-----",
+	"-----BEGIN SSH SIGNATURE-----",
+	"-----BEGIN SIGNED MESSAGE-----",
+}
+
+var (
+	// ErrTagExists is returned when creating a tag whose name is already taken.
+	ErrTagExists = errors.New("tag already exists")
+
+	// ErrInvalidTagName is returned when a name is not valid for a tag.
+	ErrInvalidTagName = errors.New("invalid tag name")
+
+	// ErrTagSigningFailed is returned when git could not sign a tag.
+	ErrTagSigningFailed = errors.New("tag could not be signed")
+
+	// ErrMalformedTag is returned when a tag object cannot be parsed.
+	ErrMalformedTag = errors.New("malformed tag object")
+)
+
+// TagObject holds the parts of an annotated tag that a rewrite carries over.
+type TagObject struct {
+	// Hash is the full object name of the tag.
+	Hash string
+
+	// Object is the full object name of the object tagged.
+	Object string
+
+	// Type is the type of the object tagged, usually "commit".
+	Type string
+
+	// Name is the tag name recorded in the object.
+	Name string
+
+	// Tagger is who created the tag.
+	Tagger Identity
+
+	// TaggerDate is when the tag was created, in the tagger's recorded zone.
+	TaggerDate time.Time
+
+	// Signed reports whether the tag carries a signature.
+	Signed bool
+
+	// Message is the tag message, byte for byte, without its signature.
+	Message string
+}
+
+// TagOptions holds optional settings for CreateTag.
+type TagOptions struct {
+	// Sign signs the tag, with SignKey if set (git tag -s / -u).
+	Sign bool
+
+	// SignKey selects the signing key instead of user.signingkey.
+	SignKey string
+
+	// NoSign disables signing even when tag.gpgSign is set.
+	NoSign bool
+
+	// Force replaces an existing tag of the same name.
+	Force bool
+
+	// ShowOutput echoes git's output to the terminal as it runs.
+	ShowOutput bool
+}
+
+// CreateTag creates the annotated tag name on rev with the given message,
+// dated gitFormattedDate through GIT_COMMITTER_DATE, which git uses for the
+// tagger date. Returns ErrInvalidTagName, ErrTagExists (unless options.Force)
+// or an error wrapping ErrTagSigningFailed.
+func CreateTag(gitFormattedDate, name, rev, message string, options TagOptions) error {
+	if !IsValidTagName(name) {
+		return fmt.Errorf("%w: %s", ErrInvalidTagName, name)
+	}
+	if !options.Force && TagExists(name) {
+		return fmt.Errorf("%w: %s", ErrTagExists, name)
+	}
+
+	args := []string{"tag", "--annotate", "--cleanup=strip", "-F", "-"}
+	switch {
+	case options.SignKey != "":
+		args = append(args, "--local-user="+options.SignKey)
+	case options.Sign:
+		args = append(args, "--sign")
+	case options.NoSign:
+		args = append(args, "--no-sign")
+	}
+	if options.Force {
+		args = append(args, "--force")
+	}
+	args = append(args, name, rev)
+
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+gitFormattedDate)
+	cmd.Stdin = strings.NewReader(message)
+
+	var output bytes.Buffer
+	if options.ShowOutput {
+		cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+		cmd.Stderr = io.MultiWriter(os.Stderr, &output)
+	} else {
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ClassifyFailure(output.String()) == FailureSigningFailed {
+			return fmt.Errorf("%w: %s", ErrTagSigningFailed, GetGitError(output.Bytes()))
+		}
+		return fmt.Errorf("git tag failed: %s: %w", GetGitError(output.Bytes()), err)
+	}
+
+	return nil
+}
+
+// TagExists reports whether refs/tags/name exists.
+func TagExists(name string) bool {
+	cmd := exec.CommandContext(context.Background(), "git", "rev-parse", "--verify", "--quiet", "refs/tags/"+name)
+	return cmd.Run() == nil
+}
+
+// IsValidTagName reports whether name can be used as a tag name.
+func IsValidTagName(name string) bool {
+	if strings.HasPrefix(name, "-") {
+		return false
+	}
+	cmd := exec.CommandContext(context.Background(), "git", "check-ref-format", "refs/tags/"+name)
+	return cmd.Run() == nil
+}
+
+// ReadTag reads the annotated tag object hash.
+func ReadTag(hash string) (TagObject, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "cat-file", "tag", hash)
+	output, err := cmd.Output()
+	if err != nil {
+		return TagObject{}, fmt.Errorf("failed to read tag %s: %w", hash, err)
+	}
+
+	return parseTagObject(hash, string(output))
+}
+
+// parseTagObject parses the raw content of a tag object.
+func parseTagObject(hash, raw string) (TagObject, error) {
+	headers, message, found := strings.Cut(raw, "\n\n")
+	if !found {
+		headers = strings.TrimSuffix(raw, "\n")
+	}
+
+	tag := TagObject{Hash: hash, Message: message}
+	for _, line := range strings.Split(headers, "\n") {
+		if strings.HasPrefix(line, " ") {
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "object":
+			tag.Object = value
+		case "type":
+			tag.Type = value
+		case "tag":
+			tag.Name = value
+		case "tagger":
+			var err error
+			if tag.Tagger, tag.TaggerDate, err = parseSignatureLine(value); err != nil {
+				return TagObject{}, fmt.Errorf("%w %s: %w", ErrMalformedTag, hash, err)
+			}
+		case signatureHeader, signatureHeader + "-sha256":
+			tag.Signed = true
+		}
+	}
+	if tag.Object == "" || tag.Type == "" || tag.Name == "" {
+		return TagObject{}, fmt.Errorf("%w %s: missing object, type or tag name", ErrMalformedTag, hash)
+	}
+
+	// The signature follows the message, starting on a line of its own
+	for _, marker := range tagSignatureMarkers {
+		if strings.HasPrefix(tag.Message, marker) {
+			tag.Message, tag.Signed = "", true
+			break
+		}
+		if i := strings.LastIndex(tag.Message, "\n"+marker); i >= 0 {
+			tag.Message, tag.Signed = tag.Message[:i+1], true
+			break
+		}
+	}
+	return tag, nil
+}
+
+// WriteTag creates an annotated tag object from tag's object, type, name,
+// tagger, date and message, and returns its hash. tag.Hash and tag.Signed
+// are ignored: the new tag is not signed. No ref is created or moved.
+func WriteTag(tag TagObject) (string, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "mktag")
+	cmd.Stdin = strings.NewReader(tagPayload(tag))
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git mktag failed: %s: %w", GetGitError(exitErr.Stderr), err)
+		}
+		return "", fmt.Errorf("git mktag failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// tagPayload returns the raw tag object holding tag.
+func tagPayload(tag TagObject) string {
+	var raw strings.Builder
+	fmt.Fprintf(&raw, "object %s\ntype %s\ntag %s\n", tag.Object, tag.Type, tag.Name)
+	if !tag.TaggerDate.IsZero() {
+		fmt.Fprintf(&raw, "tagger %s %s\n", tag.Tagger, FormatRawDate(tag.TaggerDate))
+	}
+	raw.WriteString("\n" + tag.Message)
+	return raw.String()
+}
+
+// WriteSignedTag creates an annotated tag object like WriteTag, signed with
+// the configured key (user.signingkey, gpg.format) as git tag -s would, and
+// returns its hash. No ref is created or moved. Returns an error wrapping
+// ErrTagSigningFailed if it could not be signed.
+func WriteSignedTag(tag TagObject) (string, error) {
+	// The signature covers the headers and message, and follows the message
+	if !strings.HasSuffix(tag.Message, "\n") {
+		tag.Message += "\n"
+	}
+	signature, err := SignPayload(tagPayload(tag), tag.Tagger)
+	if errors.Is(err, ErrSigningFailed) {
+		return "", fmt.Errorf("%w: %w", ErrTagSigningFailed, err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	tag.Message += signature
+	return WriteTag(tag)
+}
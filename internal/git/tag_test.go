@@ -0,0 +1,62 @@
+package git
+
+import (
+	"testing"
+)
+
+// TestParseTagObject tests parsing of raw tag objects, signed or not.
+func TestParseTagObject(t *testing.T) {
+	headers := "object 1111111111111111111111111111111111111111\n" +
+		"type commit\n" +
+		"tag v1.0.0\n" +
+		"tagger Jane Doe <jane@example.com> 1738783159 +0100\n" +
+		"\n"
+
+	tests := map[string]struct {
+		raw     string
+		message string
+		signed  bool
+	}{
+		"unsigned": {
+			raw:     headers + "Release 1.0.0\n\nFirst stable release.\n",
+			message: "Release 1.0.0\n\nFirst stable release.\n",
+		},
+		"pgp": {
+			raw: headers + "Release 1.0.0\n-----BEGIN PGP SIGNATURE-----\n\niQEzBAABCAAdFiEE\n" +
+				"-----END PGP SIGNATURE-----\n",
+			message: "Release 1.0.0\n",
+			signed:  true,
+		},
+		"ssh": {
+			raw:     headers + "Release 1.0.0\n-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n-----END SSH SIGNATURE-----\n",
+			message: "Release 1.0.0\n",
+			signed:  true,
+		},
+		"marker inside the message": {
+			raw:     headers + "Mentions -----BEGIN PGP SIGNATURE----- inline\n",
+			message: "Mentions -----BEGIN PGP SIGNATURE----- inline\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tag, err := parseTagObject("abc", tt.raw)
+			if err != nil {
+				t.Fatalf("parseTagObject() unexpected error: %v", err)
+			}
+			if tag.Object != "1111111111111111111111111111111111111111" || tag.Type != "commit" || tag.Name != "v1.0.0" {
+				t.Errorf("Object = %q, Type = %q, Name = %q", tag.Object, tag.Type, tag.Name)
+			}
+			if tag.Tagger.String() != "Jane Doe <jane@example.com>" || FormatRawDate(tag.TaggerDate) != "1738783159 +0100" {
+				t.Errorf("Tagger = %v, TaggerDate = %s", tag.Tagger, FormatRawDate(tag.TaggerDate))
+			}
+			if tag.Message != tt.message || tag.Signed != tt.signed {
+				t.Errorf("Message = %q, Signed = %v", tag.Message, tag.Signed)
+			}
+		})
+	}
+
+	if _, err := parseTagObject("abc", "type commit\ntag v1\n\nmsg\n"); err == nil {
+		t.Error("parseTagObject() expected an error for a tag without object")
+	}
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SignedOffByKey is the trailer added by --signoff.
+	SignedOffByKey = "Signed-off-by"
+
+	// CoAuthoredByKey is the trailer GitHub and GitLab use to credit co-authors.
+	CoAuthoredByKey = "Co-authored-by"
+)
+
+// ErrInvalidTrailer is returned when a trailer is not in "key=value" or "key: value" form.
+var ErrInvalidTrailer = errors.New(`trailer must be in the form "key=value" or "key: value"`)
+
+// trailerKeyPattern matches the keys git interpret-trailers recognizes.
+var trailerKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// Trailer is a "Key: value" line at the end of a commit message.
+type Trailer struct {
+	// Key is the trailer token, e.g. "Signed-off-by".
+	Key string
+
+	// Value is the text after the separator.
+	Value string
+}
+
+// String formats the trailer the way it appears in a commit message.
+func (t Trailer) String() string {
+	return t.Key + ": " + t.Value
+}
+
+// ParseTrailer parses a trailer given as "key=value" or "key: value",
+// as accepted by git interpret-trailers --trailer.
+func ParseTrailer(s string) (Trailer, error) {
+	separator := strings.IndexAny(s, "=:")
+	if separator < 0 {
+		return Trailer{}, fmt.Errorf("%w: %q", ErrInvalidTrailer, s)
+	}
+
+	trailer := Trailer{
+		Key:   strings.TrimSpace(s[:separator]),
+		Value: strings.TrimSpace(s[separator+1:]),
+	}
+
+	if !trailerKeyPattern.MatchString(trailer.Key) {
+		return Trailer{}, fmt.Errorf("%w: invalid key in %q", ErrInvalidTrailer, s)
+	}
+	if trailer.Value == "" || strings.ContainsAny(trailer.Value, "\r\n") {
+		return Trailer{}, fmt.Errorf("%w: invalid value in %q", ErrInvalidTrailer, s)
+	}
+
+	return trailer, nil
+}
+
+// AddTrailers appends trailers to message using git interpret-trailers, so
+// that they join an existing trailer block or start a new paragraph.
+// A trailer whose key and value already appear in the block (keys compared
+// case-insensitively) is not added again, including repeats within trailers.
+func AddTrailers(message string, trailers []Trailer) (string, error) {
+	if len(trailers) == 0 {
+		return message, nil
+	}
+
+	args := []string{"interpret-trailers", "--if-exists", "addIfDifferent", "--if-missing", "add"}
+	for _, trailer := range trailers {
+		args = append(args, "--trailer", trailer.String())
+	}
+
+	// Without a final newline the subject line would be taken as part of the trailer block
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	cmd.Stdin = strings.NewReader(message)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git interpret-trailers failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
+// ParseTrailers returns the trailers at the end of message, as recognized by
+// git interpret-trailers --parse.
+func ParseTrailers(message string) ([]Trailer, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "interpret-trailers", "--parse")
+	cmd.Stdin = strings.NewReader(message)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git interpret-trailers failed: %w", err)
+	}
+
+	var trailers []Trailer
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, found := strings.Cut(line, ": ")
+		if found {
+			trailers = append(trailers, Trailer{Key: key, Value: value})
+		}
+	}
+	return trailers, nil
+}
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseTrailer tests parsing of --trailer values.
+func TestParseTrailer(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Trailer
+		wantErr  bool
+	}{
+		{input: "Refs=#123", expected: Trailer{Key: "Refs", Value: "#123"}},
+		{input: "Refs: #123", expected: Trailer{Key: "Refs", Value: "#123"}},
+		{input: "Reviewed-by = Jane <jane@example.com>", expected: Trailer{Key: "Reviewed-by", Value: "Jane <jane@example.com>"}},
+		{input: "See-also: https://example.com/a=b", expected: Trailer{Key: "See-also", Value: "https://example.com/a=b"}},
+		{input: "Refs", wantErr: true},
+		{input: "Refs=", wantErr: true},
+		{input: "Bad Key=value", wantErr: true},
+		{input: "=value", wantErr: true},
+		{input: "Refs=a\nb", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			trailer, err := ParseTrailer(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTrailer) {
+					t.Errorf("ParseTrailer(%q) error = %v, want ErrInvalidTrailer", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTrailer(%q) unexpected error: %v", tt.input, err)
+			}
+			if trailer != tt.expected {
+				t.Errorf("ParseTrailer(%q) = %+v, want %+v", tt.input, trailer, tt.expected)
+			}
+		})
+	}
+}
+
+// TestAddTrailers tests trailer placement and de-duplication.
+func TestAddTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		trailers []Trailer
+		expected string
+	}{
+		{
+			name:     "subject only",
+			message:  "Add feature",
+			trailers: []Trailer{{Key: "Refs", Value: "#1"}},
+			expected: "Add feature\n\nRefs: #1\n",
+		},
+		{
+			name:     "joins existing trailer block",
+			message:  "Add feature\n\nBody.\n\nRefs: #1\n",
+			trailers: []Trailer{{Key: "Refs", Value: "#2"}},
+			expected: "Add feature\n\nBody.\n\nRefs: #1\nRefs: #2\n",
+		},
+		{
+			name:    "duplicates are dropped",
+			message: "Add feature\n\nCo-authored-by: Jane <jane@example.com>\n",
+			trailers: []Trailer{
+				{Key: "co-authored-by", Value: "Jane <jane@example.com>"},
+				{Key: "Refs", Value: "#1"},
+				{Key: "Refs", Value: "#1"},
+			},
+			expected: "Add feature\n\nCo-authored-by: Jane <jane@example.com>\nRefs: #1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AddTrailers(tt.message, tt.trailers)
+			if err != nil {
+				t.Fatalf("AddTrailers() unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("AddTrailers() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
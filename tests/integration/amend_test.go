@@ -0,0 +1,189 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupAmendRepo creates a repository with two commits, the last one dated 2025-03-01.
+func setupAmendRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := setupTestRepo(t)
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-01T10:00:00", "GIT_COMMITTER_DATE=2025-01-01T10:00:00"},
+		"commit", "--allow-empty", "-m", "First")
+
+	if err := os.WriteFile(filepath.Join(repoDir, "last.txt"), []byte("last"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "last.txt")
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-03-01T10:00:00", "GIT_COMMITTER_DATE=2025-03-01T10:00:00"},
+		"commit", "-m", "Last commit")
+
+	return repoDir
+}
+
+// TestAmendRedatesHead tests that --amend re-dates HEAD against its parent, keeping the message.
+func TestAmendRedatesHead(t *testing.T) {
+	repoDir := setupAmendRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	// Earlier than HEAD itself, but after its parent
+	cmd := exec.Command(getBinaryPath(t), "--amend", "2025-02-01 12:00:00")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(string(output), "Commit amended") {
+		t.Errorf("Expected amend success message, got: %s", output)
+	}
+
+	dates := runGit(t, repoDir, nil, "log", "-1", "--format=%aI %cI %s")
+	if !strings.HasPrefix(dates, "2025-02-01T12:00:00") || !strings.Contains(dates, " 2025-02-01T12:00:00") {
+		t.Errorf("Expected author and committer dates 2025-02-01T12:00:00, got: %s", dates)
+	}
+	if !strings.HasSuffix(dates, "Last commit") {
+		t.Errorf("Expected message to be kept, got: %s", dates)
+	}
+	if count := runGit(t, repoDir, nil, "rev-list", "--count", "HEAD"); count != "2" {
+		t.Errorf("Expected HEAD to be replaced, got %s commits", count)
+	}
+}
+
+// TestAmendOptions tests message replacement and --keep-content.
+func TestAmendOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedFiles string
+		expectedMsg   string
+	}{
+		{
+			name:          "staged changes are included",
+			args:          []string{"--amend", "2025-03-02 09:00:00", "Reworded"},
+			expectedFiles: "last.txt\nstaged.txt",
+			expectedMsg:   "Reworded",
+		},
+		{
+			name:          "keep content ignores staged changes",
+			args:          []string{"--amend", "--keep-content", "2025-03-02 09:00:00"},
+			expectedFiles: "last.txt",
+			expectedMsg:   "Last commit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupAmendRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			if err := os.WriteFile(filepath.Join(repoDir, "staged.txt"), []byte("staged"), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			runGit(t, repoDir, nil, "add", "staged.txt")
+
+			cmd := exec.Command(getBinaryPath(t), tt.args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+
+			if files := runGit(t, repoDir, nil, "show", "--name-only", "--format=", "HEAD"); files != tt.expectedFiles {
+				t.Errorf("Expected files %q, got %q", tt.expectedFiles, files)
+			}
+			if msg := runGit(t, repoDir, nil, "log", "-1", "--format=%s"); msg != tt.expectedMsg {
+				t.Errorf("Expected message %q, got %q", tt.expectedMsg, msg)
+			}
+		})
+	}
+}
+
+// TestAmendErrors tests that amending is refused when unsafe or out of order.
+func TestAmendErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		publish       bool
+		args          []string
+		expectError   bool
+		expectedError string
+	}{
+		{
+			name:          "before the parent",
+			args:          []string{"--amend", "2024-12-31 23:59:59"},
+			expectError:   true,
+			expectedError: "HEAD^1",
+		},
+		{
+			name:          "published commit",
+			publish:       true,
+			args:          []string{"--amend", "2025-03-02 09:00:00"},
+			expectError:   true,
+			expectedError: "Commit already published",
+		},
+		{
+			name:        "published commit with force",
+			publish:     true,
+			args:        []string{"--amend", "--force", "2025-03-02 09:00:00"},
+			expectError: false,
+		},
+		{
+			name:          "keep content without amend",
+			args:          []string{"--keep-content", "2025-03-02 09:00:00", "Message"},
+			expectError:   true,
+			expectedError: "Conflicting options",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupAmendRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			if tt.publish {
+				// Simulate a pushed branch with a remote-tracking ref at HEAD
+				runGit(t, repoDir, nil, "update-ref", "refs/remotes/origin/main", "HEAD")
+			}
+
+			cmd := exec.Command(getBinaryPath(t), tt.args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+
+			if !tt.expectError {
+				if err != nil {
+					t.Fatalf("Expected success, got: %v\nOutput: %s", err, output)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected error, but command succeeded. Output: %s", output)
+			}
+			if !strings.Contains(string(output), tt.expectedError) {
+				t.Errorf("Expected error message to contain %q, got: %s", tt.expectedError, output)
+			}
+		})
+	}
+}
+
+// TestAmendEmptyRepository tests that --amend without any commit is rejected.
+func TestAmendEmptyRepository(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	cmd := exec.Command(getBinaryPath(t), "--amend", "2025-03-02 09:00:00")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Fatalf("Expected error, but command succeeded. Output: %s", output)
+	}
+	if !strings.Contains(string(output), "Nothing to amend") {
+		t.Errorf("Expected error message to contain %q, got: %s", "Nothing to amend", output)
+	}
+}
@@ -0,0 +1,172 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runEditDates runs gitcommit edit-dates in repoDir with script as the
+// editor and returns its combined output.
+func runEditDates(t *testing.T, repoDir, script string, args ...string) (string, error) {
+	t.Helper()
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write editor script: %v", err)
+	}
+
+	cmd := exec.Command(getBinaryPath(t), append([]string{"edit-dates"}, args...)...)
+	cmd.Dir = repoDir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR="+editor)
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// TestEditDates tests that the edited dates are applied to the commits.
+func TestEditDates(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	shape := historyShape(t, repoDir)
+
+	// Both dates of the second commit, and the author date only of the third
+	script := `sed -i -e 's/2025-01-03T10:00:00/2025-01-03T18:30:00/g' -e 's/2025-01-04T10:00:00/2025-01-04T11:00:00/' "$1"`
+	output, err := runEditDates(t, repoDir, script, "HEAD~3")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "✓ Rewrote 2 commit(s) on main with gitcommit edit-dates") {
+		t.Errorf("Expected success message, got: %s", output)
+	}
+
+	dates := strings.Split(runGit(t, repoDir, nil, "log", "-3", "--reverse", "--format=%aI|%cI"), "\n")
+	expected := []string{
+		"2025-01-02T10:00:00|2025-01-02T10:00:00",
+		"2025-01-03T18:30:00|2025-01-03T18:30:00",
+		"2025-01-04T11:00:00|2025-01-04T10:00:00",
+	}
+	for i, line := range dates {
+		author, committer, _ := strings.Cut(line, "|")
+		expectedAuthor, expectedCommitter, _ := strings.Cut(expected[i], "|")
+		if !strings.HasPrefix(author, expectedAuthor) || !strings.HasPrefix(committer, expectedCommitter) {
+			t.Errorf("Commit %d: expected %s, got %s", i+1, expected[i], line)
+		}
+	}
+	if newShape := historyShape(t, repoDir); newShape != shape {
+		t.Errorf("Expected trees, identities and subjects to be kept\nbefore:\n%s\nafter:\n%s", shape, newShape)
+	}
+	if reflog := runGit(t, repoDir, nil, "reflog", "-1", "--format=%gs", "main"); !strings.HasPrefix(reflog, "gitcommit edit-dates: ") {
+		t.Errorf("Expected a reflog entry, got %q", reflog)
+	}
+}
+
+// TestEditDatesReopensEditor tests that unreadable lines send the user back
+// to the editor with the errors, and that an unchanged list gives up.
+func TestEditDatesReopensEditor(t *testing.T) {
+	t.Run("fixed on the second attempt", func(t *testing.T) {
+		repoDir := setupRedateRepo(t)
+		defer os.RemoveAll(repoDir)
+
+		state := filepath.Join(t.TempDir(), "attempted")
+		script := `if [ -f ` + state + ` ]; then
+  grep -q '^#   line 2: invalid author date "tomorrow' "$1" || exit 1
+  sed -i 's/tomorrow/2025-01-03T12:00:00/' "$1"
+else
+  touch ` + state + `
+  sed -i '2s/^\([0-9a-f]*\) 2025-01-03T10:00:00/\1 tomorrow/' "$1"
+fi`
+		output, err := runEditDates(t, repoDir, script, "HEAD~3")
+		if err != nil {
+			t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+		}
+		if !strings.Contains(output, `error: line 2: invalid author date "tomorrow`) {
+			t.Errorf("Expected the error to be shown, got: %s", output)
+		}
+		if date := runGit(t, repoDir, nil, "log", "-1", "--format=%aI", "HEAD~1"); !strings.HasPrefix(date, "2025-01-03T12:00:00") {
+			t.Errorf("Expected the fixed date, got %s", date)
+		}
+	})
+
+	t.Run("left unchanged", func(t *testing.T) {
+		repoDir := setupRedateRepo(t)
+		defer os.RemoveAll(repoDir)
+		head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+		script := `sed -i 's/^\([0-9a-f]*\) 2025-01-03T[^ ]*/\1 tomorrow/' "$1"`
+		output, err := runEditDates(t, repoDir, script, "HEAD~3")
+		if err == nil {
+			t.Fatalf("Expected command to fail, got: %s", output)
+		}
+		if !strings.Contains(output, "Invalid todo list") || !strings.Contains(output, "line 2: invalid author date") {
+			t.Errorf("Expected the unreadable lines, got: %s", output)
+		}
+		if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+			t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+		}
+	})
+}
+
+// TestEditDatesChronology tests that every chronology violation is reported
+// by line before anything is rewritten.
+func TestEditDatesChronology(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	script := `sed -i -e 's/2025-01-02T10:00:00/2024-12-31T10:00:00/g' -e 's/2025-01-03T10:00:00/2025-01-05T10:00:00/g' "$1"`
+	output, err := runEditDates(t, repoDir, script, "HEAD~3")
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+	for _, expected := range []string{"put 2 commit(s) before a parent", "line 1:", "(outside the range)", "line 3:", "at line 2"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in: %s", expected, output)
+		}
+	}
+	if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+		t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+	}
+}
+
+// TestEditDatesNothingRewritten tests aborting by emptying the list, and --dry-run.
+func TestEditDatesNothingRewritten(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "emptied list",
+			script:   `sed -i '/^[0-9a-f]/d' "$1"`,
+			args:     []string{"HEAD~3"},
+			expected: "Nothing to do",
+		},
+		{
+			name:     "dry run",
+			script:   `sed -i 's/2025-01-04T10:00:00/2025-01-04T16:00:00/g' "$1"`,
+			args:     []string{"HEAD~3", "--dry-run"},
+			expected: "Sat 4 Jan 2025 16:00:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupRedateRepo(t)
+			defer os.RemoveAll(repoDir)
+			head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+			output, err := runEditDates(t, repoDir, tt.script, tt.args...)
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+			if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+				t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+			}
+		})
+	}
+}
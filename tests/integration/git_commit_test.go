@@ -43,6 +43,21 @@ func setupTestRepo(t *testing.T) string {
 	return tmpDir
 }
 
+// runGit runs a git command in dir with extra environment variables and returns its trimmed output.
+func runGit(t *testing.T, dir string, env []string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
 // getMainPath returns the absolute path to the main.go file (for go run).
 func getMainPath(t *testing.T) string {
 	t.Helper()
@@ -82,6 +97,15 @@ func getBinaryPath(t *testing.T) string {
 	return binaryPath
 }
 
+// runGitcommit runs gitcommit with args in dir and returns its combined output.
+func runGitcommit(t *testing.T, dir string, args ...string) (string, error) {
+	t.Helper()
+	cmd := exec.Command(getBinaryPath(t), args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
 // TestGitCommitExecution tests that the tool can execute git commit successfully.
 func TestGitCommitExecution(t *testing.T) {
 	// Setup test repository
@@ -0,0 +1,138 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGitFailureClassification tests that common git commit failures produce tailored errors.
+func TestGitFailureClassification(t *testing.T) {
+	tests := []struct {
+		name            string
+		setup           func(t *testing.T, repoDir string)
+		flags           []string
+		expectedStrings []string
+	}{
+		{
+			name: "hook rejection",
+			setup: func(t *testing.T, repoDir string) {
+				hook := filepath.Join(repoDir, ".git", "hooks", "pre-commit")
+				script := "#!/bin/sh\necho 'lint failed: trailing whitespace' >&2\nexit 1\n"
+				if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+					t.Fatalf("Failed to install hook: %v", err)
+				}
+			},
+			expectedStrings: []string{"Commit rejected by a Git hook", "pre-commit", "lint failed: trailing whitespace"},
+		},
+		{
+			name: "index lock present",
+			setup: func(t *testing.T, repoDir string) {
+				lock := filepath.Join(repoDir, ".git", "index.lock")
+				if err := os.WriteFile(lock, nil, 0644); err != nil {
+					t.Fatalf("Failed to create index.lock: %v", err)
+				}
+			},
+			expectedStrings: []string{"Git index is locked", "index.lock", "rm "},
+		},
+		{
+			name: "unknown identity",
+			setup: func(t *testing.T, repoDir string) {
+				runGit(t, repoDir, nil, "config", "--local", "--unset", "user.email")
+				runGit(t, repoDir, nil, "config", "--local", "user.useConfigOnly", "true")
+			},
+			expectedStrings: []string{"Git identity not configured", "git config --global user.email"},
+		},
+		{
+			name: "git output echoed when asked",
+			setup: func(t *testing.T, repoDir string) {
+				hook := filepath.Join(repoDir, ".git", "hooks", "commit-msg")
+				script := "#!/bin/sh\necho 'message rejected by policy' >&2\nexit 1\n"
+				if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+					t.Fatalf("Failed to install hook: %v", err)
+				}
+			},
+			flags:           []string{"--show-git-output"},
+			expectedStrings: []string{"Commit rejected by a Git hook", "commit-msg", "message rejected by policy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTestRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			if err := os.WriteFile(filepath.Join(repoDir, "test.txt"), []byte("content"), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			runGit(t, repoDir, nil, "add", "test.txt")
+			tt.setup(t, repoDir)
+
+			args := append(tt.flags, "2025-02-05 20:19:19", "Test commit")
+			cmd := exec.Command(getBinaryPath(t), args...)
+			cmd.Dir = repoDir
+			// Keep global configuration from supplying an identity
+			cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+			output, err := cmd.CombinedOutput()
+			outputStr := string(output)
+
+			if err == nil {
+				t.Fatalf("Expected error, but command succeeded. Output: %s", outputStr)
+			}
+			if strings.Contains(outputStr, "Stage your changes") {
+				t.Errorf("Expected a tailored hint instead of the generic one, got: %s", outputStr)
+			}
+			for _, expected := range tt.expectedStrings {
+				if !strings.Contains(outputStr, expected) {
+					t.Errorf("Expected output to contain %q, got: %s", expected, outputStr)
+				}
+			}
+		})
+	}
+}
+
+// TestUnmergedPathsError tests that committing with unresolved conflicts reports them.
+func TestUnmergedPathsError(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	writeConflict := func(content string) {
+		if err := os.WriteFile(filepath.Join(repoDir, "conflict.txt"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write conflict.txt: %v", err)
+		}
+		runGit(t, repoDir, nil, "add", "conflict.txt")
+	}
+
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+	writeConflict("base\n")
+	runGit(t, repoDir, nil, "commit", "-q", "-m", "Base")
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "side")
+	writeConflict("side\n")
+	runGit(t, repoDir, nil, "commit", "-q", "-m", "Side")
+	runGit(t, repoDir, nil, "checkout", "-q", "main")
+	writeConflict("main\n")
+	runGit(t, repoDir, nil, "commit", "-q", "-m", "Main")
+
+	// The merge stops with a conflict
+	merge := exec.Command("git", "merge", "side")
+	merge.Dir = repoDir
+	if err := merge.Run(); err == nil {
+		t.Fatalf("Expected merge to conflict")
+	}
+
+	cmd := exec.Command(getBinaryPath(t), "2099-01-01 00:00:00", "Finish merge")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	outputStr := string(output)
+
+	if err == nil {
+		t.Fatalf("Expected error, but command succeeded. Output: %s", outputStr)
+	}
+	for _, expected := range []string{"Unresolved merge conflicts", "--diff-filter=U"} {
+		if !strings.Contains(outputStr, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, outputStr)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestIdentityOverrides tests that --author and --committer are recorded and reported.
+func TestIdentityOverrides(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "default identities",
+			args:     nil,
+			expected: "Test User <test@example.com>|Test User <test@example.com>",
+		},
+		{
+			name:     "author only",
+			args:     []string{"--author", "Jane Doe <jane@example.com>"},
+			expected: "Jane Doe <jane@example.com>|Test User <test@example.com>",
+		},
+		{
+			name:     "author and committer",
+			args:     []string{"--author", "Jane Doe <jane@example.com>", "--committer", "Bot <bot@example.com>"},
+			expected: "Jane Doe <jane@example.com>|Bot <bot@example.com>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTestRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			if err := os.WriteFile(filepath.Join(repoDir, "work.txt"), []byte("work"), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			runGit(t, repoDir, nil, "add", "work.txt")
+
+			args := append(tt.args, "2025-02-01 10:00:00", "Backfilled work")
+			cmd := exec.Command(getBinaryPath(t), args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+
+			recorded := runGit(t, repoDir, nil, "log", "-1", "--format=%an <%ae>|%cn <%ce>")
+			if recorded != tt.expected {
+				t.Errorf("Expected identities %q, got %q", tt.expected, recorded)
+			}
+
+			author, committer, _ := strings.Cut(tt.expected, "|")
+			if !strings.Contains(string(output), "Author:    "+author) ||
+				!strings.Contains(string(output), "Committer: "+committer) {
+				t.Errorf("Expected identities in success message, got: %s", output)
+			}
+		})
+	}
+}
+
+// TestInvalidIdentity tests that malformed identities are rejected before committing.
+func TestInvalidIdentity(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	cmd := exec.Command(getBinaryPath(t), "--allow-empty", "--author", "jane@example.com",
+		"2025-02-01 10:00:00", "Backfilled work")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+
+	if !strings.Contains(string(output), "Invalid identity") || !strings.Contains(string(output), "--author") {
+		t.Errorf("Expected invalid identity error naming --author, got: %s", output)
+	}
+	if out, _ := exec.Command("git", "-C", repoDir, "rev-parse", "--verify", "--quiet", "HEAD").Output(); len(out) > 0 {
+		t.Error("Expected no commit to be created")
+	}
+}
+
+// TestConfiguredIdentityWithoutAt tests that a configured email git accepts,
+// such as a bare user name, is used as is rather than rejected.
+func TestConfiguredIdentityWithoutAt(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	runGit(t, repoDir, nil, "config", "--local", "user.name", "CI Bot")
+	runGit(t, repoDir, nil, "config", "--local", "user.email", "ci")
+
+	cmd := exec.Command(getBinaryPath(t), "--allow-empty", "2025-01-01 10:00:00", "first")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	recorded := runGit(t, repoDir, nil, "log", "-1", "--format=%an <%ae>|%cn <%ce>")
+	if recorded != "CI Bot <ci>|CI Bot <ci>" {
+		t.Errorf("Expected configured identity to be recorded, got %q", recorded)
+	}
+}
+
+// TestIdentityMailmapWarning tests that an identity mapped by .mailmap is reported.
+func TestIdentityMailmapWarning(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	mailmap := "Jane Doe <jane@example.com> <jane@old.example.com>\n"
+	if err := os.WriteFile(filepath.Join(repoDir, ".mailmap"), []byte(mailmap), 0644); err != nil {
+		t.Fatalf("Failed to create .mailmap: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", ".mailmap")
+
+	cmd := exec.Command(getBinaryPath(t), "--author", "Jane <jane@old.example.com>",
+		"2025-02-01 10:00:00", "Add mailmap")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	expected := "Warning:   --author Jane <jane@old.example.com> is Jane Doe <jane@example.com> in .mailmap"
+	if !strings.Contains(string(output), expected) {
+		t.Errorf("Expected %q in the output, got: %s", expected, output)
+	}
+}
+
+// TestIdentityConfiguredWarning tests that overrides disagreeing with the
+// configured identity are reported.
+func TestIdentityConfiguredWarning(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "author with the configured name and another email",
+			args:     []string{"--author", "Test User <test@old.example.com>"},
+			expected: "Warning:   --author Test User <test@old.example.com> differs from your configured identity Test User <test@example.com>",
+		},
+		{
+			name:     "committer other than the configured identity",
+			args:     []string{"--committer", "Bot <bot@example.com>"},
+			expected: "Warning:   --committer Bot <bot@example.com> differs from your configured identity Test User <test@example.com>",
+		},
+		{
+			name: "author of someone else",
+			args: []string{"--author", "Jane Doe <jane@example.com>"},
+		},
+		{
+			name: "committer matching the configured identity",
+			args: []string{"--committer", "Test User <TEST@example.com>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTestRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			args := append([]string{"--allow-empty"}, tt.args...)
+			output, err := runGitcommit(t, repoDir, append(args, "2025-02-01 10:00:00", "Backfilled work")...)
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+
+			if tt.expected == "" {
+				if strings.Contains(output, "Warning:") {
+					t.Errorf("Expected no warning, got: %s", output)
+				}
+			} else if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q in the output, got: %s", tt.expected, output)
+			}
+		})
+	}
+}
+
+// TestAmendKeepsOrOverridesAuthor tests that --amend keeps HEAD's author unless --author is given.
+func TestAmendKeepsOrOverridesAuthor(t *testing.T) {
+	repoDir := setupAmendRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-03-01T10:00:00", "GIT_COMMITTER_DATE=2025-03-01T10:00:00"},
+		"commit", "--amend", "--no-edit", "--author=Original <original@example.com>")
+
+	cmd := exec.Command(getBinaryPath(t), "--amend", "2025-03-02 10:00:00")
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	if author := runGit(t, repoDir, nil, "log", "-1", "--format=%an <%ae>"); author != "Original <original@example.com>" {
+		t.Errorf("Expected original author to be kept, got %q", author)
+	}
+
+	cmd = exec.Command(getBinaryPath(t), "--amend", "--author", "Jane Doe <jane@example.com>", "2025-03-03 10:00:00")
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	if author := runGit(t, repoDir, nil, "log", "-1", "--format=%an <%ae> %aI"); !strings.HasPrefix(author, "Jane Doe <jane@example.com> 2025-03-03T10:00:00") {
+		t.Errorf("Expected overridden author with new date, got %q", author)
+	}
+}
@@ -0,0 +1,197 @@
+package integration
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// journalEntry is the part of a journal entry the tests check.
+type journalEntry struct {
+	Operation string    `json:"operation"`
+	Range     string    `json:"range"`
+	InputDate string    `json:"inputDate"`
+	Date      time.Time `json:"date"`
+	Refs      []struct {
+		Name, Old, New string
+	} `json:"refs"`
+	Commits []struct {
+		Old, New   string
+		AuthorDate time.Time `json:"authorDate"`
+	} `json:"commits"`
+	Identity string `json:"identity"`
+	Author   string `json:"author"`
+	Version  string `json:"version"`
+	Backup   string `json:"backup"`
+	Restored string `json:"restored"`
+}
+
+// readJournal returns the entries of gitcommit log --json, newest first.
+func readJournal(t *testing.T, repoDir string, args ...string) []journalEntry {
+	t.Helper()
+	output, err := runGitcommit(t, repoDir, append([]string{"log", "--json"}, args...)...)
+	if err != nil {
+		t.Fatalf("log --json failed: %v\nOutput: %s", err, output)
+	}
+
+	var entries []journalEntry
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var entry journalEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid journal line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestJournal tests that commits, rewrites and undo are recorded in order.
+func TestJournal(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	base := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	if err := os.WriteFile(filepath.Join(repoDir, "four.txt"), []byte("four\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "four.txt")
+	if output, err := runGitcommit(t, repoDir, "2025-01-05 10:00:00", "Add four.txt"); err != nil {
+		t.Fatalf("commit failed: %v\nOutput: %s", err, output)
+	}
+	committed := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	if output, err := runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-02-01 09:00:00"); err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	rewritten := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+	if output, err := runGitcommit(t, repoDir, "undo"); err != nil {
+		t.Fatalf("undo failed: %v\nOutput: %s", err, output)
+	}
+
+	if _, err := os.Stat(filepath.Join(repoDir, ".git", "gitcommit", "journal.jsonl")); err != nil {
+		t.Fatalf("Expected the journal in the git directory: %v", err)
+	}
+
+	entries := readJournal(t, repoDir)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+	undo, redate, commit := entries[0], entries[1], entries[2]
+
+	if commit.Operation != "commit" || commit.InputDate != "2025-01-05 10:00:00" || commit.Date.IsZero() ||
+		commit.Identity != "Test User <test@example.com>" || commit.Version == "" || commit.Backup == "" {
+		t.Errorf("Unexpected commit entry: %+v", commit)
+	}
+	if len(commit.Refs) != 1 || commit.Refs[0].Name != "refs/heads/main" ||
+		commit.Refs[0].Old != base || commit.Refs[0].New != committed {
+		t.Errorf("Expected main to move from %s to %s, got %+v", base, committed, commit.Refs)
+	}
+	if len(commit.Commits) != 1 || commit.Commits[0].New != committed || commit.Commits[0].Old != "" {
+		t.Errorf("Expected the new commit %s, got %+v", committed, commit.Commits)
+	}
+
+	if redate.Operation != "redate" || redate.Range != "HEAD~2" || redate.InputDate != "--start 2025-02-01 09:00:00" {
+		t.Errorf("Unexpected redate entry: %+v", redate)
+	}
+	if len(redate.Commits) != 2 || redate.Commits[1].Old != committed || redate.Commits[1].New != rewritten ||
+		redate.Commits[0].AuthorDate.Format("2006-01-02T15:04:05") != "2025-02-01T09:00:00" {
+		t.Errorf("Expected both commits rewritten, the last to %s, got %+v", rewritten, redate.Commits)
+	}
+
+	if undo.Operation != "undo" || undo.Restored != redate.Backup || undo.Refs[0].New != committed {
+		t.Errorf("Expected undo to restore backup %s to %s, got %+v", redate.Backup, committed, undo)
+	}
+
+	// Filters and the readable format
+	if filtered := readJournal(t, repoDir, "--operation", "redate"); len(filtered) != 1 || filtered[0].Operation != "redate" {
+		t.Errorf("Expected only the redate entry, got %+v", filtered)
+	}
+	if newest := readJournal(t, repoDir, "-n", "1"); len(newest) != 1 || newest[0].Operation != "undo" {
+		t.Errorf("Expected only the undo entry, got %+v", newest)
+	}
+	output, err := runGitcommit(t, repoDir, "log", "-n", "2")
+	if err != nil {
+		t.Fatalf("log failed: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{"undo", "redate HEAD~2: --start 2025-02-01 09:00:00", "By Test User <test@example.com>"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "commit: 2025-01-05") {
+		t.Errorf("Expected -n 2 to leave out the commit, got: %s", output)
+	}
+}
+
+// TestJournalIdentityOverrides tests that the journal records the identities
+// given with --author and --committer rather than the configured one.
+func TestJournalIdentityOverrides(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	output, err := runGitcommit(t, repoDir, "--allow-empty", "--author", "Ann <ann@example.com>",
+		"--committer", "Bot <bot@example.com>", "2025-01-01 10:00:00", "Backfilled work")
+	if err != nil {
+		t.Fatalf("commit failed: %v\nOutput: %s", err, output)
+	}
+
+	entries := readJournal(t, repoDir)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 journal entry, got %d", len(entries))
+	}
+	if entries[0].Identity != "Bot <bot@example.com>" || entries[0].Author != "Ann <ann@example.com>" {
+		t.Errorf("Expected committer Bot and author Ann, got %q and %q", entries[0].Identity, entries[0].Author)
+	}
+}
+
+// TestJournalFailedVerification tests that a commit is recorded even when
+// checking its signature fails afterwards.
+func TestJournalFailedVerification(t *testing.T) {
+	repoDir, key := setupSigningRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	// Sign with ssh-keygen, but report every signature as bad
+	program := filepath.Join(t.TempDir(), "ssh-program")
+	script := "#!/bin/sh\nfor arg in \"$@\"; do [ \"$arg\" = verify ] && exit 255; done\nexec ssh-keygen \"$@\"\n"
+	if err := os.WriteFile(program, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write signing program: %v", err)
+	}
+	runGit(t, repoDir, nil, "config", "gpg.ssh.program", program)
+
+	output, err := runGitcommit(t, repoDir, "-S"+key, "2025-02-01 10:00:00", "Signed work")
+	if err == nil {
+		t.Fatalf("Expected the verification to fail, got: %s", output)
+	}
+
+	entries := readJournal(t, repoDir)
+	if len(entries) != 1 || len(entries[0].Commits) != 1 {
+		t.Fatalf("Expected the commit in the journal, got: %+v", entries)
+	}
+	if head := runGit(t, repoDir, nil, "rev-parse", "HEAD"); entries[0].Commits[0].New != head {
+		t.Errorf("Expected the journal to record %s, got %s", head, entries[0].Commits[0].New)
+	}
+}
+
+// TestJournalDryRunAndEmpty tests that nothing is recorded without a change.
+func TestJournalDryRunAndEmpty(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	output, err := runGitcommit(t, repoDir, "log")
+	if err != nil || !strings.Contains(output, "No operations recorded") {
+		t.Errorf("Expected an empty journal, got: %v %s", err, output)
+	}
+
+	if output, err := runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-02-01 09:00:00", "--dry-run"); err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	if entries := readJournal(t, repoDir); len(entries) != 0 {
+		t.Errorf("Expected a dry run to record nothing, got %+v", entries)
+	}
+}
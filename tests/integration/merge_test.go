@@ -0,0 +1,219 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupDiverged creates a main branch and a side branch that change different
+// files, so that they merge without conflicts. With fastForward, main has no
+// commits of its own after the fork and merging side is a fast-forward.
+func setupDiverged(t *testing.T, fastForward bool) string {
+	t.Helper()
+
+	dated := func(date string) []string {
+		return []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}
+	}
+	commitFile := func(repoDir, name, date string) {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		runGit(t, repoDir, nil, "add", name)
+		runGit(t, repoDir, dated(date), "commit", "-m", "Add "+name)
+	}
+
+	repoDir := setupTestRepo(t)
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+	commitFile(repoDir, "base.txt", "2025-01-01T10:00:00")
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "side")
+	commitFile(repoDir, "side.txt", "2025-02-01T10:00:00")
+	runGit(t, repoDir, nil, "checkout", "-q", "main")
+	if !fastForward {
+		commitFile(repoDir, "main.txt", "2025-01-15T10:00:00")
+	}
+
+	return repoDir
+}
+
+// TestMergeModes tests dated merge commits, squash merges and fast-forwards.
+func TestMergeModes(t *testing.T) {
+	tests := []struct {
+		name            string
+		fastForward     bool
+		args            []string
+		expectedOutput  string
+		expectedParents int
+		expectedSubject string
+		expectDated     bool
+	}{
+		{
+			name:            "merge commit",
+			args:            []string{"2025-02-02 10:00:00", "side"},
+			expectedOutput:  "Merged side with date",
+			expectedParents: 2,
+			expectedSubject: "Merge branch 'side'",
+			expectDated:     true,
+		},
+		{
+			name:            "custom message",
+			args:            []string{"-m", "Bring in side work", "2025-02-02 10:00:00", "side"},
+			expectedOutput:  "Merged side with date",
+			expectedParents: 2,
+			expectedSubject: "Bring in side work",
+			expectDated:     true,
+		},
+		{
+			name:            "no-ff on a fast-forward",
+			fastForward:     true,
+			args:            []string{"--no-ff", "2025-02-02 10:00:00", "side"},
+			expectedOutput:  "Merged side with date",
+			expectedParents: 2,
+			expectedSubject: "Merge branch 'side'",
+			expectDated:     true,
+		},
+		{
+			name:            "squash",
+			args:            []string{"--squash", "2025-02-02 10:00:00", "side"},
+			expectedOutput:  "Squashed side into a commit with date",
+			expectedParents: 1,
+			expectedSubject: "Squashed commit of the following:",
+			expectDated:     true,
+		},
+		{
+			name:            "fast-forward",
+			fastForward:     true,
+			args:            []string{"2025-02-02 10:00:00", "side"},
+			expectedOutput:  "Fast-forwarded to side",
+			expectedParents: 1,
+			expectedSubject: "Add side.txt",
+			expectDated:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupDiverged(t, tt.fastForward)
+			defer os.RemoveAll(repoDir)
+
+			output, err := runGitcommit(t, repoDir, append([]string{"merge"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(output, tt.expectedOutput) {
+				t.Errorf("Expected %q in output, got: %s", tt.expectedOutput, output)
+			}
+
+			fields := strings.Split(runGit(t, repoDir, nil, "log", "-1", "--format=%aI|%cI|%P|%s"), "|")
+			dated := strings.HasPrefix(fields[0], "2025-02-02T10:00:00") && strings.HasPrefix(fields[1], "2025-02-02T10:00:00")
+			if dated != tt.expectDated {
+				t.Errorf("Expected dated commit %v, got author %s committer %s", tt.expectDated, fields[0], fields[1])
+			}
+			if parents := len(strings.Fields(fields[2])); parents != tt.expectedParents {
+				t.Errorf("Expected %d parents, got %d", tt.expectedParents, parents)
+			}
+			if fields[3] != tt.expectedSubject {
+				t.Errorf("Expected subject %q, got %q", tt.expectedSubject, fields[3])
+			}
+			if _, err := os.Stat(filepath.Join(repoDir, "side.txt")); err != nil {
+				t.Error("Expected side.txt to be merged")
+			}
+		})
+	}
+}
+
+// TestMergeChronology tests that the merge date must follow every merged branch.
+func TestMergeChronology(t *testing.T) {
+	repoDir := setupDiverged(t, false)
+	defer os.RemoveAll(repoDir)
+
+	// After main, but before side
+	output, err := runGitcommit(t, repoDir, "merge", "2025-01-20 10:00:00", "side")
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "Chronology violation") || !strings.Contains(output, "Floor set by:        side") {
+		t.Errorf("Expected chronology violation against side, got: %s", output)
+	}
+	if count := runGit(t, repoDir, nil, "rev-list", "--count", "HEAD"); count != "2" {
+		t.Errorf("Expected no merge to happen, got %s commits", count)
+	}
+}
+
+// TestMergeConflict tests that conflicts stop the merge and can be concluded with gitcommit.
+func TestMergeConflict(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(repoDir, "conflict.txt"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	dated := []string{"GIT_AUTHOR_DATE=2025-01-01T10:00:00", "GIT_COMMITTER_DATE=2025-01-01T10:00:00"}
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+	write("base\n")
+	runGit(t, repoDir, nil, "add", "conflict.txt")
+	runGit(t, repoDir, dated, "commit", "-m", "Base")
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "side")
+	write("side\n")
+	runGit(t, repoDir, dated, "commit", "-a", "-m", "Side")
+	runGit(t, repoDir, nil, "checkout", "-q", "main")
+	write("main\n")
+	runGit(t, repoDir, dated, "commit", "-a", "-m", "Main")
+
+	output, err := runGitcommit(t, repoDir, "merge", "2025-02-02 10:00:00", "side")
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "Merge stopped on conflicts") || !strings.Contains(output, "conflict.txt") ||
+		!strings.Contains(output, `gitcommit "2025-02-02 10:00:00"`) {
+		t.Errorf("Expected merge conflict error with hint, got: %s", output)
+	}
+
+	write("resolved\n")
+	runGit(t, repoDir, nil, "add", "conflict.txt")
+	cmd := exec.Command(getBinaryPath(t), "2025-02-02 10:00:00")
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Concluding the merge failed: %v\nOutput: %s", err, output)
+	}
+	if parents := runGit(t, repoDir, nil, "log", "-1", "--format=%P"); len(strings.Fields(parents)) != 2 {
+		t.Errorf("Expected a merge commit, got parents %q", parents)
+	}
+}
+
+// TestMergeErrors tests argument and option validation.
+func TestMergeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "missing branch", args: []string{"2025-02-02 10:00:00"}, expected: "Wrong number of arguments"},
+		{name: "unknown branch", args: []string{"2025-02-02 10:00:00", "nope"}, expected: "nope"},
+		{name: "invalid date", args: []string{"yesterday", "side"}, expected: "Invalid date format"},
+		{
+			name:     "conflicting modes",
+			args:     []string{"--no-ff", "--squash", "2025-02-02 10:00:00", "side"},
+			expected: "Conflicting options",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupDiverged(t, false)
+			defer os.RemoveAll(repoDir)
+
+			output, err := runGitcommit(t, repoDir, append([]string{"merge"}, tt.args...)...)
+			if err == nil {
+				t.Fatalf("Expected command to fail, got: %s", output)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupMessageRepo creates a repository with a staged file ready to commit.
+func setupMessageRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := setupTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repoDir, "test.txt"), []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "test.txt")
+
+	return repoDir
+}
+
+// TestMessageSources tests reading the commit message from -m, -F, standard input and the editor.
+func TestMessageSources(t *testing.T) {
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	// Succeeds only if the template shows the chosen date, then writes a message above the comments
+	script := "#!/bin/sh\ngrep -q '^# Commit date: Wed 5 Feb 2025 20:19:19' \"$1\" || exit 1\n" +
+		"{ printf 'Edited subject\\n\\nEdited body\\n'; cat \"$1\"; } > \"$1.new\" && mv \"$1.new\" \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write editor script: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		file     string
+		env      []string
+		expected string
+	}{
+		{
+			name:     "repeated -m paragraphs",
+			args:     []string{"-m", "Subject", "-m", "First paragraph.", "-m", "Second paragraph."},
+			expected: "Subject\n\nFirst paragraph.\n\nSecond paragraph.",
+		},
+		{
+			name:     "message file",
+			file:     "Subject from file\n\nBody from file.\n",
+			expected: "Subject from file\n\nBody from file.",
+		},
+		{
+			name:     "standard input",
+			args:     []string{"-F", "-"},
+			stdin:    "Subject from stdin\n\nBody from stdin.\n",
+			expected: "Subject from stdin\n\nBody from stdin.",
+		},
+		{
+			name:     "editor",
+			args:     []string{"--edit"},
+			env:      []string{"GIT_EDITOR=" + editor},
+			expected: "Edited subject\n\nEdited body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupMessageRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			args := tt.args
+			if tt.file != "" {
+				messageFile := filepath.Join(t.TempDir(), "message.txt")
+				if err := os.WriteFile(messageFile, []byte(tt.file), 0644); err != nil {
+					t.Fatalf("Failed to write message file: %v", err)
+				}
+				args = append(args, "-F", messageFile)
+			}
+
+			cmd := exec.Command(getBinaryPath(t), append(args, "2025-02-05 20:19:19")...)
+			cmd.Dir = repoDir
+			cmd.Env = append(os.Environ(), tt.env...)
+			cmd.Stdin = strings.NewReader(tt.stdin)
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+
+			if message := runGit(t, repoDir, nil, "log", "-1", "--format=%B"); message != tt.expected {
+				t.Errorf("Expected message %q, got %q", tt.expected, message)
+			}
+		})
+	}
+}
+
+// TestMessageErrors tests that empty and conflicting messages are rejected.
+func TestMessageErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		env           []string
+		expectedError string
+	}{
+		{
+			name:          "empty -m",
+			args:          []string{"-m", "  ", "2025-02-05 20:19:19"},
+			expectedError: "Empty commit message",
+		},
+		{
+			name:          "empty message argument",
+			args:          []string{"2025-02-05 20:19:19", ""},
+			expectedError: "Empty commit message",
+		},
+		{
+			name:          "editor leaves only comments",
+			args:          []string{"--edit", "2025-02-05 20:19:19"},
+			env:           []string{"GIT_EDITOR=:"},
+			expectedError: "Empty commit message",
+		},
+		{
+			name:          "missing message file",
+			args:          []string{"-F", "does-not-exist.txt", "2025-02-05 20:19:19"},
+			expectedError: "Cannot read commit message",
+		},
+		{
+			name:          "message argument and -m",
+			args:          []string{"-m", "Flag", "2025-02-05 20:19:19", "Argument"},
+			expectedError: "Conflicting options",
+		},
+		{
+			name:          "date alone",
+			args:          []string{"2025-02-05 20:19:19"},
+			expectedError: "Missing required arguments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupMessageRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			cmd := exec.Command(getBinaryPath(t), tt.args...)
+			cmd.Dir = repoDir
+			cmd.Env = append(os.Environ(), tt.env...)
+			output, err := cmd.CombinedOutput()
+
+			if err == nil {
+				t.Fatalf("Expected error, but command succeeded. Output: %s", output)
+			}
+			if !strings.Contains(string(output), tt.expectedError) {
+				t.Errorf("Expected error message to contain %q, got: %s", tt.expectedError, output)
+			}
+		})
+	}
+}
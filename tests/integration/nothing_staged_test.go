@@ -0,0 +1,139 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNothingStaged tests the pre-flight check that something is staged for commit.
+func TestNothingStaged(t *testing.T) {
+	tests := []struct {
+		name            string
+		withCommit      bool // whether HEAD exists before running gitcommit
+		detached        bool // whether HEAD is detached at that commit
+		flags           []string
+		expectError     bool
+		expectedStrings []string
+	}{
+		{
+			name:        "empty repository with nothing staged",
+			withCommit:  false,
+			expectError: true,
+			expectedStrings: []string{
+				"Nothing staged to commit",
+				"Unstaged changes: 0 file(s)",
+				"Untracked files:  2 file(s)",
+			},
+		},
+		{
+			name:        "nothing staged after a commit",
+			withCommit:  true,
+			expectError: true,
+			expectedStrings: []string{
+				"Nothing staged to commit",
+				"Unstaged changes: 1 file(s)",
+				"Untracked files:  2 file(s)",
+				"--allow-empty",
+			},
+		},
+		{
+			name:        "nothing staged on a detached HEAD",
+			withCommit:  true,
+			detached:    true,
+			expectError: true,
+			expectedStrings: []string{
+				"Nothing staged to commit",
+				"HEAD is detached: if your changes are on a branch, switch to it first",
+			},
+		},
+		{
+			name:        "allow empty commit",
+			withCommit:  true,
+			flags:       []string{"--allow-empty"},
+			expectError: false,
+		},
+		{
+			name:            "allow empty commit on a detached HEAD",
+			withCommit:      true,
+			detached:        true,
+			flags:           []string{"--allow-empty"},
+			expectError:     false,
+			expectedStrings: []string{"HEAD is detached, so no branch points to this commit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTestRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			if tt.withCommit {
+				if err := os.WriteFile(filepath.Join(repoDir, "tracked.txt"), []byte("v1"), 0644); err != nil {
+					t.Fatalf("Failed to create test file: %v", err)
+				}
+				runGit(t, repoDir, nil, "add", "tracked.txt")
+				runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-01T10:00:00", "GIT_COMMITTER_DATE=2025-01-01T10:00:00"},
+					"commit", "-m", "First")
+				if tt.detached {
+					runGit(t, repoDir, nil, "checkout", "-q", "--detach")
+				}
+
+				// Modify the tracked file without staging it
+				if err := os.WriteFile(filepath.Join(repoDir, "tracked.txt"), []byte("v2"), 0644); err != nil {
+					t.Fatalf("Failed to modify test file: %v", err)
+				}
+			}
+
+			// Untracked files, one of them in a subdirectory
+			if err := os.MkdirAll(filepath.Join(repoDir, "sub"), 0755); err != nil {
+				t.Fatalf("Failed to create subdirectory: %v", err)
+			}
+			for _, name := range []string{"new.txt", filepath.Join("sub", "nested.txt")} {
+				if err := os.WriteFile(filepath.Join(repoDir, name), []byte("new"), 0644); err != nil {
+					t.Fatalf("Failed to create untracked file: %v", err)
+				}
+			}
+
+			args := append(tt.flags, "2025-02-05 20:19:19", "Nothing staged")
+			cmd := exec.Command(getBinaryPath(t), args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			outputStr := string(output)
+
+			if !tt.expectError {
+				if err != nil {
+					t.Fatalf("Expected success, got: %v\nOutput: %s", err, outputStr)
+				}
+				if subject := runGit(t, repoDir, nil, "log", "-1", "--format=%s"); subject != "Nothing staged" {
+					t.Errorf("Expected empty commit to be created, last commit is %q", subject)
+				}
+				for _, expected := range tt.expectedStrings {
+					if !strings.Contains(outputStr, expected) {
+						t.Errorf("Expected output to contain %q, got: %s", expected, outputStr)
+					}
+				}
+				if !tt.detached && strings.Contains(outputStr, "HEAD is detached") {
+					t.Errorf("Expected no detached HEAD warning, got: %s", outputStr)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected error, but command succeeded")
+			}
+			for _, expected := range tt.expectedStrings {
+				if !strings.Contains(outputStr, expected) {
+					t.Errorf("Expected error message to contain %q, got: %s", expected, outputStr)
+				}
+			}
+
+			// The check must run before any commit is attempted
+			if strings.Contains(outputStr, "Git commit failed") {
+				t.Errorf("Expected pre-flight error instead of git failure, got: %s", outputStr)
+			}
+		})
+	}
+}
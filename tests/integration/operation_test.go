@@ -0,0 +1,233 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupConflict creates a main branch and a side branch that both change
+// conflict.txt, then runs "git <operation> side", which stops on the conflict.
+func setupConflict(t *testing.T, operation string) string {
+	t.Helper()
+
+	dated := func(date string) []string {
+		return []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}
+	}
+	write := func(repoDir, content string) {
+		if err := os.WriteFile(filepath.Join(repoDir, "conflict.txt"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	repoDir := setupTestRepo(t)
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+	write(repoDir, "base\n")
+	runGit(t, repoDir, nil, "add", "conflict.txt")
+	runGit(t, repoDir, dated("2025-01-01T10:00:00"), "commit", "-m", "Base")
+
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "side")
+	write(repoDir, "side\n")
+	runGit(t, repoDir, dated("2025-02-01T10:00:00"), "commit", "-a", "-m", "Side change",
+		"--author=Original Author <original@example.com>")
+
+	runGit(t, repoDir, nil, "checkout", "-q", "main")
+	write(repoDir, "main\n")
+	runGit(t, repoDir, dated("2025-01-15T10:00:00"), "commit", "-a", "-m", "Main change")
+
+	target := "side"
+	if operation == "revert" {
+		// Revert the main change on top of a further edit, which conflicts
+		write(repoDir, "main again\n")
+		runGit(t, repoDir, dated("2025-01-20T10:00:00"), "commit", "-a", "-m", "Main again")
+		target = "HEAD^"
+	}
+
+	cmd := exec.Command("git", operation, target)
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("Expected git %s to stop on a conflict, got: %s", operation, output)
+	}
+
+	write(repoDir, "resolved\n")
+	runGit(t, repoDir, nil, "add", "conflict.txt")
+	return repoDir
+}
+
+// TestConcludeOperation tests concluding a merge, cherry-pick or revert with a custom date.
+func TestConcludeOperation(t *testing.T) {
+	tests := []struct {
+		operation       string
+		date            string
+		expectedParents int
+		expectedAuthor  string
+		expectedSubject string
+	}{
+		{
+			operation:       "merge",
+			date:            "2025-02-02 10:00:00",
+			expectedParents: 2,
+			expectedAuthor:  "Test User",
+			expectedSubject: "Merge branch 'side'",
+		},
+		{
+			operation:       "cherry-pick",
+			date:            "2025-01-16 10:00:00",
+			expectedParents: 1,
+			expectedAuthor:  "Original Author",
+			expectedSubject: "Side change",
+		},
+		{
+			operation:       "revert",
+			date:            "2025-01-21 10:00:00",
+			expectedParents: 1,
+			expectedAuthor:  "Test User",
+			expectedSubject: `Revert "Main change"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			repoDir := setupConflict(t, tt.operation)
+			defer os.RemoveAll(repoDir)
+
+			cmd := exec.Command(getBinaryPath(t), tt.date)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(string(output), "Concluded the "+tt.operation) {
+				t.Errorf("Expected concluded operation in output, got: %s", output)
+			}
+
+			isoDate := strings.Replace(tt.date, " ", "T", 1)
+			log := runGit(t, repoDir, nil, "log", "-1", "--format=%an|%aI|%cI|%P|%s")
+			fields := strings.Split(log, "|")
+			if fields[0] != tt.expectedAuthor {
+				t.Errorf("Expected author %q, got %q", tt.expectedAuthor, fields[0])
+			}
+			if !strings.HasPrefix(fields[1], isoDate) || !strings.HasPrefix(fields[2], isoDate) {
+				t.Errorf("Expected author and committer dates %s, got %s and %s", isoDate, fields[1], fields[2])
+			}
+			if parents := len(strings.Fields(fields[3])); parents != tt.expectedParents {
+				t.Errorf("Expected %d parents, got %d", tt.expectedParents, parents)
+			}
+			if fields[4] != tt.expectedSubject {
+				t.Errorf("Expected prepared subject %q, got %q", tt.expectedSubject, fields[4])
+			}
+
+			message := runGit(t, repoDir, nil, "log", "-1", "--format=%B")
+			if strings.Contains(message, "# Conflicts") {
+				t.Errorf("Expected comment lines to be removed, got:\n%s", message)
+			}
+
+			for _, head := range []string{"MERGE_HEAD", "CHERRY_PICK_HEAD", "REVERT_HEAD"} {
+				if _, err := os.Stat(filepath.Join(repoDir, ".git", head)); err == nil {
+					t.Errorf("Expected %s to be removed", head)
+				}
+			}
+		})
+	}
+}
+
+// TestConcludeEmptyResolution tests that a cherry-pick or revert whose
+// resolution drops every change is still concluded, like cherry-pick --continue.
+func TestConcludeEmptyResolution(t *testing.T) {
+	tests := []struct {
+		operation string
+		date      string
+	}{
+		{operation: "cherry-pick", date: "2025-01-16 10:00:00"},
+		{operation: "revert", date: "2025-01-21 10:00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			repoDir := setupConflict(t, tt.operation)
+			defer os.RemoveAll(repoDir)
+			runGit(t, repoDir, nil, "checkout", "HEAD", "--", "conflict.txt")
+			before := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+			cmd := exec.Command(getBinaryPath(t), tt.date)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+
+			if parent := runGit(t, repoDir, nil, "rev-parse", "HEAD^"); parent != before {
+				t.Errorf("Expected an empty commit on top of %s, got parent %s", before, parent)
+			}
+			if diff := runGit(t, repoDir, nil, "diff", "--name-only", "HEAD^", "HEAD"); diff != "" {
+				t.Errorf("Expected the commit to record no changes, got: %s", diff)
+			}
+		})
+	}
+}
+
+// TestConcludeMergeChronology tests that the date must follow the commit being merged.
+func TestConcludeMergeChronology(t *testing.T) {
+	repoDir := setupConflict(t, "merge")
+	defer os.RemoveAll(repoDir)
+
+	// After main, but before the side commit being merged
+	cmd := exec.Command(getBinaryPath(t), "2025-01-20 10:00:00")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+	if !strings.Contains(string(output), "Chronology violation") || !strings.Contains(string(output), "MERGE_HEAD") {
+		t.Errorf("Expected chronology violation against MERGE_HEAD, got: %s", output)
+	}
+}
+
+// TestOperationRefusals tests options and states that cannot conclude an operation.
+func TestOperationRefusals(t *testing.T) {
+	t.Run("amend during merge", func(t *testing.T) {
+		repoDir := setupConflict(t, "merge")
+		defer os.RemoveAll(repoDir)
+
+		cmd := exec.Command(getBinaryPath(t), "--amend", "2025-02-02 10:00:00")
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		if err == nil || !strings.Contains(string(output), "Conflicting options") {
+			t.Errorf("Expected conflicting options error, got: %v\n%s", err, output)
+		}
+	})
+
+	t.Run("paths during cherry-pick", func(t *testing.T) {
+		repoDir := setupConflict(t, "cherry-pick")
+		defer os.RemoveAll(repoDir)
+
+		cmd := exec.Command(getBinaryPath(t), "2025-01-16 10:00:00", "Pick", "--", "conflict.txt")
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		if err == nil || !strings.Contains(string(output), "Cannot commit selected paths during a cherry-pick") {
+			t.Errorf("Expected partial commit error, got: %v\n%s", err, output)
+		}
+	})
+
+	t.Run("rebase in progress", func(t *testing.T) {
+		repoDir := setupConflict(t, "cherry-pick")
+		defer os.RemoveAll(repoDir)
+		runGit(t, repoDir, nil, "cherry-pick", "--abort")
+
+		cmd := exec.Command("git", "rebase", "side")
+		cmd.Dir = repoDir
+		if output, err := cmd.CombinedOutput(); err == nil {
+			t.Fatalf("Expected git rebase to stop on a conflict, got: %s", output)
+		}
+		runGit(t, repoDir, nil, "add", "conflict.txt")
+
+		cmd = exec.Command(getBinaryPath(t), "2025-02-03 10:00:00", "Rebased")
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		if err == nil || !strings.Contains(string(output), "Cannot commit during a rebase") {
+			t.Errorf("Expected rebase in progress error, got: %v\n%s", err, output)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupPathspecRepo creates a repository with two committed files, both modified
+// afterwards, with only b.txt staged and c.txt left untracked.
+func setupPathspecRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := setupTestRepo(t)
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	writeFile("a.txt", "a1")
+	writeFile("b.txt", "b1")
+	runGit(t, repoDir, nil, "add", "a.txt", "b.txt")
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-01T10:00:00", "GIT_COMMITTER_DATE=2025-01-01T10:00:00"},
+		"commit", "-m", "First")
+
+	writeFile("a.txt", "a2")
+	writeFile("b.txt", "b2")
+	writeFile("c.txt", "c1")
+	runGit(t, repoDir, nil, "add", "b.txt")
+
+	return repoDir
+}
+
+// TestPathspecCommit tests that paths after -- are committed without touching the rest of the index.
+func TestPathspecCommit(t *testing.T) {
+	repoDir := setupPathspecRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	cmd := exec.Command(getBinaryPath(t), "2025-02-05 20:19:19", "Only a and c", "--", "a.txt", "c.txt")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	committed := runGit(t, repoDir, nil, "show", "--name-only", "--format=", "HEAD")
+	if committed != "a.txt\nc.txt" {
+		t.Errorf("Expected a.txt and c.txt to be committed, got: %q", committed)
+	}
+
+	// b.txt must still be staged, and nothing else
+	staged := runGit(t, repoDir, nil, "diff", "--cached", "--name-only")
+	if staged != "b.txt" {
+		t.Errorf("Expected only b.txt to remain staged, got: %q", staged)
+	}
+
+	if date := runGit(t, repoDir, nil, "log", "-1", "--format=%aI"); !strings.HasPrefix(date, "2025-02-05T20:19:19") {
+		t.Errorf("Expected commit date 2025-02-05T20:19:19, got: %s", date)
+	}
+}
+
+// TestAllFlagCommit tests that --all commits modified tracked files but not untracked ones.
+func TestAllFlagCommit(t *testing.T) {
+	repoDir := setupPathspecRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	cmd := exec.Command(getBinaryPath(t), "--all", "2025-02-05 20:19:19", "All tracked changes")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	committed := runGit(t, repoDir, nil, "show", "--name-only", "--format=", "HEAD")
+	if committed != "a.txt\nb.txt" {
+		t.Errorf("Expected a.txt and b.txt to be committed, got: %q", committed)
+	}
+}
+
+// TestPathspecErrors tests argument grammar and staging errors for pathspecs.
+func TestPathspecErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "all with paths",
+			args:          []string{"--all", "2025-02-05 20:19:19", "Message", "--", "a.txt"},
+			expectedError: "Conflicting options",
+		},
+		{
+			name:          "paths without separator",
+			args:          []string{"2025-02-05 20:19:19", "Message", "a.txt"},
+			expectedError: "Missing required arguments",
+		},
+		{
+			name:          "missing message before separator",
+			args:          []string{"2025-02-05 20:19:19", "--", "a.txt"},
+			expectedError: "Missing required arguments",
+		},
+		{
+			name:          "unknown path",
+			args:          []string{"2025-02-05 20:19:19", "Message", "--", "missing.txt"},
+			expectedError: "Cannot stage the given paths",
+		},
+		{
+			name:          "unchanged path",
+			args:          []string{"2025-02-05 20:19:19", "Message", "--", "untouched.txt"},
+			expectedError: "Nothing staged to commit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupPathspecRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			if err := os.WriteFile(filepath.Join(repoDir, "untouched.txt"), []byte("same"), 0644); err != nil {
+				t.Fatalf("Failed to write untouched.txt: %v", err)
+			}
+			runGit(t, repoDir, nil, "add", "untouched.txt")
+			runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-02T10:00:00", "GIT_COMMITTER_DATE=2025-01-02T10:00:00"},
+				"commit", "-m", "Untouched", "--", "untouched.txt")
+
+			cmd := exec.Command(getBinaryPath(t), tt.args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+
+			if err == nil {
+				t.Fatalf("Expected error, but command succeeded. Output: %s", output)
+			}
+			if !strings.Contains(string(output), tt.expectedError) {
+				t.Errorf("Expected error message to contain %q, got: %s", tt.expectedError, output)
+			}
+
+			// The index must be left as it was
+			if staged := runGit(t, repoDir, nil, "diff", "--cached", "--name-only"); staged != "b.txt" {
+				t.Errorf("Expected index to be untouched with only b.txt staged, got: %q", staged)
+			}
+		})
+	}
+}
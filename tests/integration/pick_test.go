@@ -0,0 +1,275 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupPickRepo creates a main branch with one commit and a scratch branch
+// with three more, 30 and 90 minutes apart, each adding a file.
+func setupPickRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := setupTestRepo(t)
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+	commitDatedFile(t, repoDir, "base.txt", "2025-01-01T10:00:00")
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "scratch")
+	commitDatedFile(t, repoDir, "one.txt", "2025-03-01T10:00:00")
+	commitDatedFile(t, repoDir, "two.txt", "2025-03-01T10:30:00")
+	commitDatedFile(t, repoDir, "three.txt", "2025-03-01T12:00:00")
+	runGit(t, repoDir, nil, "checkout", "-q", "main")
+
+	return repoDir
+}
+
+// TestPickSchedules tests the dates given to picked commits.
+func TestPickSchedules(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "default interval",
+			args:     []string{"2025-02-01 09:00:00", "main..scratch"},
+			expected: []string{"2025-02-01T09:00:00", "2025-02-01T09:01:00", "2025-02-01T09:02:00"},
+		},
+		{
+			name:     "custom interval",
+			args:     []string{"--interval=15m", "2025-02-01 09:00:00", "main..scratch"},
+			expected: []string{"2025-02-01T09:00:00", "2025-02-01T09:15:00", "2025-02-01T09:30:00"},
+		},
+		{
+			name:     "preserved gaps",
+			args:     []string{"--preserve-gaps", "2025-02-01 09:00:00", "main..scratch"},
+			expected: []string{"2025-02-01T09:00:00", "2025-02-01T09:30:00", "2025-02-01T11:00:00"},
+		},
+		{
+			name: "explicit dates",
+			args: []string{"2025-02-01 09:00:00", "scratch~2", "scratch~1",
+				"2025-02-03 14:00:00", "scratch"},
+			expected: []string{"2025-02-01T09:00:00", "2025-02-01T09:01:00", "2025-02-03T14:00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupPickRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			output, err := runGitcommit(t, repoDir, append([]string{"pick"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if strings.Count(output, "✓ Picked") != 3 {
+				t.Errorf("Expected three picked commits, got: %s", output)
+			}
+
+			log := strings.Split(runGit(t, repoDir, nil, "log", "-3", "--reverse", "--format=%an|%aI|%cI|%s"), "\n")
+			subjects := []string{"Add one.txt", "Add two.txt", "Add three.txt"}
+			for i, line := range log {
+				fields := strings.Split(line, "|")
+				if fields[0] != "Original Author" || fields[3] != subjects[i] {
+					t.Errorf("Commit %d: expected original author and subject, got %q", i+1, line)
+				}
+				if !strings.HasPrefix(fields[1], tt.expected[i]) || !strings.HasPrefix(fields[2], tt.expected[i]) {
+					t.Errorf("Commit %d: expected dates %s, got %q", i+1, tt.expected[i], line)
+				}
+			}
+		})
+	}
+}
+
+// TestPickRecordOrigin tests that -x appends the original commit to the message.
+func TestPickRecordOrigin(t *testing.T) {
+	repoDir := setupPickRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	original := runGit(t, repoDir, nil, "rev-parse", "scratch")
+	if output, err := runGitcommit(t, repoDir, "pick", "-x", "2025-02-01 09:00:00", "scratch"); err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	message := runGit(t, repoDir, nil, "log", "-1", "--format=%B")
+	if !strings.HasSuffix(message, "(cherry picked from commit "+original+")") {
+		t.Errorf("Expected origin line, got: %q", message)
+	}
+}
+
+// TestPickDryRun tests that --dry-run shows the plan without picking.
+func TestPickDryRun(t *testing.T) {
+	repoDir := setupPickRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	output, err := runGitcommit(t, repoDir, "pick", "--dry-run", "--interval=1h", "2025-02-01 09:00:00", "main..scratch")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Would pick 3 commit(s)") || !strings.Contains(output, "Add three.txt") ||
+		!strings.Contains(output, "Sat 1 Feb 2025 11:00:00") {
+		t.Errorf("Expected the planned dates, got: %s", output)
+	}
+	if count := runGit(t, repoDir, nil, "rev-list", "--count", "HEAD"); count != "1" {
+		t.Errorf("Expected nothing to be picked, got %s commits", count)
+	}
+}
+
+// TestPickChronology tests that every planned date is checked before picking.
+func TestPickChronology(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "before HEAD",
+			args:     []string{"2024-12-01 09:00:00", "main..scratch"},
+			expected: "Chronology violation",
+		},
+		{
+			name:     "explicit date going back",
+			args:     []string{"2025-02-03 09:00:00", "scratch~2", "2025-02-02 09:00:00", "scratch~1"},
+			expected: "Commit 2 (",
+		},
+		{
+			name:     "interval running past the next date",
+			args:     []string{"--interval=2h", "2025-02-01 09:00:00", "scratch~2", "scratch~1", "2025-02-01 10:00:00", "scratch"},
+			expected: "Commit 3 (",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupPickRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			output, err := runGitcommit(t, repoDir, append([]string{"pick"}, tt.args...)...)
+			if err == nil {
+				t.Fatalf("Expected command to fail, got: %s", output)
+			}
+			if !strings.Contains(output, "Chronology violation") || !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+			if count := runGit(t, repoDir, nil, "rev-list", "--count", "HEAD"); count != "1" {
+				t.Errorf("Expected nothing to be picked, got %s commits", count)
+			}
+		})
+	}
+}
+
+// TestPickConflict tests that a conflict stops the pick with commands to resume it.
+func TestPickConflict(t *testing.T) {
+	repoDir := setupPickRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	if err := os.WriteFile(filepath.Join(repoDir, "two.txt"), []byte("main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "two.txt")
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-02T10:00:00", "GIT_COMMITTER_DATE=2025-01-02T10:00:00"},
+		"commit", "-m", "Add two.txt on main")
+
+	output, err := runGitcommit(t, repoDir, "pick", "--interval=1h", "2025-02-01 09:00:00", "main..scratch")
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "Pick stopped on conflicts") || !strings.Contains(output, "two.txt") ||
+		!strings.Contains(output, `--author "Original Author <original@example.com>"`) ||
+		!strings.Contains(output, `gitcommit pick "2025-02-01 11:00:00"`) {
+		t.Errorf("Expected conflict error with resume commands, got: %s", output)
+	}
+	if count := runGit(t, repoDir, nil, "rev-list", "--count", "HEAD"); count != "3" {
+		t.Errorf("Expected the first commit to be picked, got %s commits", count)
+	}
+
+	// Resolve and follow the hint
+	if err := os.WriteFile(filepath.Join(repoDir, "two.txt"), []byte("resolved\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "two.txt")
+	messageFile := filepath.Join(runGit(t, repoDir, nil, "rev-parse", "--absolute-git-dir"), "GITCOMMIT_PICKMSG")
+	cmd := exec.Command(getBinaryPath(t), "--author", "Original Author <original@example.com>",
+		"-F", messageFile, "2025-02-01 10:00:00")
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Committing the resolved pick failed: %v\nOutput: %s", err, output)
+	}
+	if output, err := runGitcommit(t, repoDir, "pick", "2025-02-01 11:00:00", "scratch"); err != nil {
+		t.Fatalf("Resuming the pick failed: %v\nOutput: %s", err, output)
+	}
+
+	log := runGit(t, repoDir, nil, "log", "-2", "--reverse", "--format=%aI %s")
+	if !strings.Contains(log, "2025-02-01T10:00:00") || !strings.Contains(log, "Add two.txt") ||
+		!strings.Contains(log, "2025-02-01T11:00:00") {
+		t.Errorf("Expected the resumed commits with planned dates, got: %s", log)
+	}
+}
+
+// TestPickConflictFirst tests that a pick stopping on its first commit,
+// having picked nothing, is not recorded in the journal.
+func TestPickConflictFirst(t *testing.T) {
+	repoDir := setupPickRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	if err := os.WriteFile(filepath.Join(repoDir, "two.txt"), []byte("main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "two.txt")
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-02T10:00:00", "GIT_COMMITTER_DATE=2025-01-02T10:00:00"},
+		"commit", "-m", "Add two.txt on main")
+
+	output, err := runGitcommit(t, repoDir, "pick", "2025-02-01 09:00:00", "scratch~1")
+	if err == nil || !strings.Contains(output, "Pick stopped on conflicts") {
+		t.Fatalf("Expected the pick to stop on conflicts, got: %v %s", err, output)
+	}
+	for _, entry := range readJournal(t, repoDir) {
+		if entry.Operation == "pick" {
+			t.Errorf("Expected no pick in the journal, got: %+v", entry)
+		}
+	}
+}
+
+// TestPickErrors tests argument and option validation.
+func TestPickErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "missing revision", args: []string{"2025-02-01 09:00:00"}, expected: "Wrong number of arguments"},
+		{name: "revision first", args: []string{"scratch", "2025-02-01 09:00:00"}, expected: "Invalid date format"},
+		{
+			name:     "date without revision",
+			args:     []string{"2025-02-01 09:00:00", "scratch", "2025-02-02 09:00:00"},
+			expected: "is not followed by any revision",
+		},
+		{name: "unknown revision", args: []string{"2025-02-01 09:00:00", "nope"}, expected: "nope"},
+		{
+			name:     "interval and preserved gaps",
+			args:     []string{"--interval=5m", "--preserve-gaps", "2025-02-01 09:00:00", "scratch"},
+			expected: "Conflicting options",
+		},
+		{
+			name:     "non-positive interval",
+			args:     []string{"--interval=0s", "2025-02-01 09:00:00", "scratch"},
+			expected: "Invalid interval",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupPickRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			output, err := runGitcommit(t, repoDir, append([]string{"pick"}, tt.args...)...)
+			if err == nil {
+				t.Fatalf("Expected command to fail, got: %s", output)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProvenance tests recording provenance as a note or trailers and reading it back.
+func TestProvenance(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		config         string
+		expectedSource string
+	}{
+		{
+			name:           "note",
+			args:           []string{"--provenance=note"},
+			expectedSource: "note (refs/notes/gitcommit)",
+		},
+		{
+			name:           "trailer",
+			args:           []string{"--provenance=trailer"},
+			expectedSource: "message trailers",
+		},
+		{
+			name:           "config key",
+			config:         "note",
+			expectedSource: "note (refs/notes/gitcommit)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTestRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			if tt.config != "" {
+				runGit(t, repoDir, nil, "config", "gitcommit.provenance", tt.config)
+			}
+
+			args := append([]string{"--allow-empty"}, tt.args...)
+			args = append(args, "2025-02-01 10:00:00", "Backdated work")
+			cmd := exec.Command(getBinaryPath(t), args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(string(output), "Provenance: recorded") {
+				t.Errorf("Expected provenance in success message, got: %s", output)
+			}
+
+			cmd = exec.Command(getBinaryPath(t), "provenance", "HEAD")
+			cmd.Dir = repoDir
+			output, err = cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("provenance failed: %v\nOutput: %s", err, output)
+			}
+
+			for _, expected := range []string{
+				"Recorded in:    " + tt.expectedSource,
+				"Created at:     " + time.Now().Format("2006-01-02"),
+				"Input date:     2025-02-01 10:00:00",
+				"Author date:    2025-02-01T10:00:00",
+			} {
+				if !strings.Contains(string(output), expected) {
+					t.Errorf("Expected %q in output, got: %s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+// TestProvenanceTrailers tests the trailers written in trailer mode.
+func TestProvenanceTrailers(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	cmd := exec.Command(getBinaryPath(t), "--allow-empty", "--provenance=trailer", "--signoff",
+		"2025-02-01 10:00:00", "Backdated work")
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	message := runGit(t, repoDir, nil, "log", "-1", "--format=%B")
+	lines := strings.Split(message, "\n")
+	if len(lines) != 6 ||
+		!strings.HasPrefix(lines[2], "Gitcommit-Created-At: ") ||
+		!strings.HasPrefix(lines[3], "Gitcommit-Version: ") ||
+		lines[4] != "Gitcommit-Input-Date: 2025-02-01 10:00:00" ||
+		!strings.HasPrefix(lines[5], "Signed-off-by: ") {
+		t.Errorf("Unexpected provenance trailers:\n%s", message)
+	}
+}
+
+// TestProvenanceMissing tests reading provenance from a commit that has none.
+func TestProvenanceMissing(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+	runGit(t, repoDir, nil, "commit", "--allow-empty", "-m", "Plain commit")
+
+	cmd := exec.Command(getBinaryPath(t), "provenance")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+	if !strings.Contains(string(output), "No provenance recorded") {
+		t.Errorf("Expected no provenance error, got: %s", output)
+	}
+}
+
+// TestInvalidProvenanceMode tests that unknown modes are rejected.
+func TestInvalidProvenanceMode(t *testing.T) {
+	output, err := exec.Command(getBinaryPath(t), "--provenance=log", "2025-02-01 10:00:00", "Work").CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+	if !strings.Contains(string(output), "Invalid provenance mode") {
+		t.Errorf("Expected invalid provenance mode error, got: %s", output)
+	}
+}
+
+// TestProvenanceFollowsRewrite tests that the provenance note of a commit
+// is carried over to the commit a redate writes in its place.
+func TestProvenanceFollowsRewrite(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	output, err := runGitcommit(t, repoDir, "--allow-empty", "--provenance=note", "2025-01-05 10:00:00", "Backdated work")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	output, err = runGitcommit(t, repoDir, "redate", "HEAD~1", "--start", "2025-01-06 10:00:00")
+	if err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+
+	output, err = runGitcommit(t, repoDir, "provenance", "HEAD")
+	if err != nil {
+		t.Fatalf("provenance failed: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{
+		"Recorded in:    note (refs/notes/gitcommit)",
+		"Input date:     2025-01-05 10:00:00",
+		"Author date:    2025-01-06T10:00:00",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+}
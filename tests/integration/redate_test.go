@@ -0,0 +1,300 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupRedateRepo creates a main branch with four commits, one a day from
+// 2025-01-01 10:00, each adding a file, authored by "Original Author".
+func setupRedateRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := setupTestRepo(t)
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+	commitDatedFile(t, repoDir, "base.txt", "2025-01-01T10:00:00")
+	commitDatedFile(t, repoDir, "one.txt", "2025-01-02T10:00:00")
+	commitDatedFile(t, repoDir, "two.txt", "2025-01-03T10:00:00")
+	commitDatedFile(t, repoDir, "three.txt", "2025-01-04T10:00:00")
+	return repoDir
+}
+
+// commitDatedFile commits a new file authored by "Original Author" at date.
+func commitDatedFile(t *testing.T, repoDir, name, date string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repoDir, name), []byte(name+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", name)
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date,
+		"GIT_AUTHOR_NAME=Original Author", "GIT_AUTHOR_EMAIL=original@example.com"},
+		"commit", "-m", "Add "+name)
+}
+
+// historyShape returns the trees, identities and subjects of the history of
+// HEAD, which a rewrite of dates must keep.
+func historyShape(t *testing.T, repoDir string) string {
+	t.Helper()
+	return runGit(t, repoDir, nil, "log", "--format=%T|%an <%ae>|%cn <%ce>|%s", "--topo-order")
+}
+
+// TestRedateSchedules tests the dates given by each source of dates.
+func TestRedateSchedules(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string // author|committer dates, oldest first, of the last three commits
+	}{
+		{
+			name: "interval",
+			args: []string{"HEAD~3", "--start", "2025-02-01 09:00:00", "--interval=1h"},
+			expected: []string{
+				"2025-02-01T09:00:00|2025-02-01T09:00:00",
+				"2025-02-01T10:00:00|2025-02-01T10:00:00",
+				"2025-02-01T11:00:00|2025-02-01T11:00:00",
+			},
+		},
+		{
+			name: "preserved gaps",
+			args: []string{"--start", "2025-02-01 09:00:00", "--preserve-gaps", "main~3..main"},
+			expected: []string{
+				"2025-02-01T09:00:00|2025-02-01T09:00:00",
+				"2025-02-02T09:00:00|2025-02-02T09:00:00",
+				"2025-02-03T09:00:00|2025-02-03T09:00:00",
+			},
+		},
+		{
+			name: "explicit author date",
+			args: []string{"HEAD~3..", "--set", "HEAD~1=2025-01-03 18:00:00", "--dates=author"},
+			expected: []string{
+				"2025-01-02T10:00:00|2025-01-02T10:00:00",
+				"2025-01-03T18:00:00|2025-01-03T10:00:00",
+				"2025-01-04T10:00:00|2025-01-04T10:00:00",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupRedateRepo(t)
+			defer os.RemoveAll(repoDir)
+			shape := historyShape(t, repoDir)
+			oldHead := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+			output, err := runGitcommit(t, repoDir, append([]string{"redate"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(output, "✓ Rewrote") {
+				t.Errorf("Expected success message, got: %s", output)
+			}
+
+			dates := strings.Split(runGit(t, repoDir, nil, "log", "-3", "--reverse", "--format=%aI|%cI"), "\n")
+			for i, line := range dates {
+				author, committer, _ := strings.Cut(line, "|")
+				expectedAuthor, expectedCommitter, _ := strings.Cut(tt.expected[i], "|")
+				if !strings.HasPrefix(author, expectedAuthor) || !strings.HasPrefix(committer, expectedCommitter) {
+					t.Errorf("Commit %d: expected %s, got %s", i+1, tt.expected[i], line)
+				}
+			}
+
+			// Only the dates change
+			newShape := historyShape(t, repoDir)
+			if newShape != shape {
+				t.Errorf("Expected trees, identities and subjects to be kept\nbefore:\n%s\nafter:\n%s", shape, newShape)
+			}
+			if base := runGit(t, repoDir, nil, "log", "-1", "--format=%aI", "HEAD~3"); !strings.HasPrefix(base, "2025-01-01T10:00:00") {
+				t.Errorf("Expected the base commit to be kept, got %s", base)
+			}
+
+			// The branch moved atomically with a reflog entry, leaving the working tree alone
+			if reflog := runGit(t, repoDir, nil, "reflog", "-1", "--format=%gs", "main"); !strings.HasPrefix(reflog, "gitcommit redate: ") {
+				t.Errorf("Expected a reflog entry, got %q", reflog)
+			}
+			if previous := runGit(t, repoDir, nil, "rev-parse", "main@{1}"); previous != oldHead {
+				t.Errorf("Expected main@{1} to be the old tip %s, got %s", oldHead, previous)
+			}
+			if status := runGit(t, repoDir, nil, "status", "--porcelain"); status != "" {
+				t.Errorf("Expected a clean working tree, got %q", status)
+			}
+		})
+	}
+}
+
+// TestRedatePlanFile tests dates read from a plan file.
+func TestRedatePlanFile(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	plan := filepath.Join(t.TempDir(), "dates.txt")
+	content := "# Dates for the release\n\nHEAD~2 2025-01-02 20:00:00\nHEAD 2025-01-05 08:00:00\n"
+	if err := os.WriteFile(plan, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	if output, err := runGitcommit(t, repoDir, "redate", "HEAD~3", "--plan", plan); err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	dates := runGit(t, repoDir, nil, "log", "-3", "--reverse", "--format=%aI")
+	for _, expected := range []string{"2025-01-02T20:00:00", "2025-01-03T10:00:00", "2025-01-05T08:00:00"} {
+		if !strings.Contains(dates, expected) {
+			t.Errorf("Expected %s in:\n%s", expected, dates)
+		}
+	}
+}
+
+// TestRedateKeepsMerges tests that merge commits keep both parents.
+func TestRedateKeepsMerges(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "side", "HEAD~2")
+	commitDatedFile(t, repoDir, "side.txt", "2025-01-05T10:00:00")
+	runGit(t, repoDir, nil, "checkout", "-q", "main")
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-06T10:00:00", "GIT_COMMITTER_DATE=2025-01-06T10:00:00"},
+		"merge", "--no-ff", "-q", "-m", "Merge side", "side")
+	shape := historyShape(t, repoDir)
+
+	output, err := runGitcommit(t, repoDir, "redate", "HEAD~4", "--start", "2025-03-01 09:00:00", "--interval=1h")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	if merges := runGit(t, repoDir, nil, "rev-list", "--merges", "--count", "HEAD"); merges != "1" {
+		t.Errorf("Expected the merge to be kept, got %s merges", merges)
+	}
+	if parents := strings.Fields(runGit(t, repoDir, nil, "log", "-1", "--format=%P")); len(parents) != 2 {
+		t.Fatalf("Expected two parents, got %v", parents)
+	}
+	if side := runGit(t, repoDir, nil, "log", "-1", "--format=%aI %s", "HEAD^2"); !strings.Contains(side, "Add side.txt") ||
+		strings.HasPrefix(side, "2025-01-05") {
+		t.Errorf("Expected the merged side commit to be redated, got %s", side)
+	}
+	if newShape := historyShape(t, repoDir); newShape != shape {
+		t.Errorf("Expected trees, identities and subjects to be kept\nbefore:\n%s\nafter:\n%s", shape, newShape)
+	}
+}
+
+// TestRedateDryRun tests that --dry-run shows the dates without rewriting.
+func TestRedateDryRun(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	output, err := runGitcommit(t, repoDir, "redate", "--dry-run", "HEAD~3", "--start", "2025-02-01 09:00:00", "--interval=1h")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "dry run, nothing rewritten") || !strings.Contains(output, "Sat 1 Feb 2025 11:00:00") ||
+		!strings.Contains(output, "Add three.txt") {
+		t.Errorf("Expected the planned dates, got: %s", output)
+	}
+	if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+		t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+	}
+}
+
+// TestRedateErrors tests that invalid requests leave history untouched.
+func TestRedateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, repoDir string)
+		args     []string
+		expected string
+	}{
+		{
+			name:     "after the next commit",
+			args:     []string{"HEAD~3", "--set", "HEAD~1=2025-01-05 10:00:00"},
+			expected: "Chronology violation",
+		},
+		{
+			name:     "before the base",
+			args:     []string{"HEAD~3", "--start", "2024-12-31 10:00:00"},
+			expected: "Its parent",
+		},
+		{name: "no dates", args: []string{"HEAD~3"}, expected: "--start, --plan or --set"},
+		{
+			name:     "two sources",
+			args:     []string{"HEAD~3", "--start", "2025-02-01 09:00:00", "--set", "HEAD=2025-02-01 09:00:00"},
+			expected: "Conflicting options",
+		},
+		{
+			name:     "interval without start",
+			args:     []string{"HEAD~3", "--interval=1h", "--set", "HEAD=2025-02-01 09:00:00"},
+			expected: "it requires --start",
+		},
+		{
+			name:     "commit outside the range",
+			args:     []string{"HEAD~1", "--set", "HEAD~2=2025-02-01 09:00:00"},
+			expected: "is not in the range",
+		},
+		{
+			name:     "invalid date",
+			args:     []string{"HEAD~1", "--set", "HEAD=yesterday"},
+			expected: "invalid date",
+		},
+		{
+			name:     "range ending at a tag",
+			setup:    func(t *testing.T, repoDir string) { runGit(t, repoDir, nil, "tag", "v1") },
+			args:     []string{"HEAD~3..v1", "--start", "2025-02-01 09:00:00"},
+			expected: "must end at HEAD or at a local branch",
+		},
+		{
+			name:     "empty range",
+			args:     []string{"HEAD..", "--start", "2025-02-01 09:00:00"},
+			expected: "contains no commits",
+		},
+		{
+			name:     "invalid dates selection",
+			args:     []string{"HEAD~3", "--start", "2025-02-01 09:00:00", "--dates=tagger"},
+			expected: "Invalid dates selection",
+		},
+		{
+			name: "published commits",
+			setup: func(t *testing.T, repoDir string) {
+				runGit(t, repoDir, nil, "update-ref", "refs/remotes/origin/main", "HEAD~1")
+			},
+			args:     []string{"HEAD~3", "--start", "2025-02-01 09:00:00"},
+			expected: "Refusing to rewrite published commits",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupRedateRepo(t)
+			defer os.RemoveAll(repoDir)
+			if tt.setup != nil {
+				tt.setup(t, repoDir)
+			}
+			head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+			output, err := runGitcommit(t, repoDir, append([]string{"redate"}, tt.args...)...)
+			if err == nil {
+				t.Fatalf("Expected command to fail, got: %s", output)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+			if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+				t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+			}
+		})
+	}
+}
+
+// TestRedateForcePublished tests that --force rewrites published commits.
+func TestRedateForcePublished(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	runGit(t, repoDir, nil, "update-ref", "refs/remotes/origin/main", "HEAD")
+
+	output, err := runGitcommit(t, repoDir, "redate", "HEAD~1", "--set", "HEAD=2025-01-04 12:00:00", "--force")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	if date := runGit(t, repoDir, nil, "log", "-1", "--format=%aI"); !strings.HasPrefix(date, "2025-01-04T12:00:00") {
+		t.Errorf("Expected the new date, got %s", date)
+	}
+}
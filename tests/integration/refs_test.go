@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestRewriteMovesTags tests that tags on rewritten commits follow them,
+// annotated tags keeping their tagger date and message.
+func TestRewriteMovesTags(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	runGit(t, repoDir, nil, "tag", "light", "HEAD~1")
+	runGit(t, repoDir, []string{"GIT_COMMITTER_DATE=2025-01-05T12:00:00+0100"},
+		"tag", "-a", "annotated", "-m", "Release one\n\nWith notes.", "HEAD~1")
+	runGit(t, repoDir, nil, "tag", "base", "HEAD~3")
+	oldBase := runGit(t, repoDir, nil, "rev-parse", "base")
+	tagger := "%(taggername) %(taggerdate:raw)|%(contents)"
+	oldAnnotated := runGit(t, repoDir, nil, "for-each-ref", "--format="+tagger, "refs/tags/annotated")
+
+	output, err := runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-02-01 09:00:00")
+	if err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{"Updated tag annotated", "Updated tag light"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in: %s", expected, output)
+		}
+	}
+
+	rewritten := runGit(t, repoDir, nil, "rev-parse", "HEAD~1")
+	if light := runGit(t, repoDir, nil, "rev-parse", "light"); light != rewritten {
+		t.Errorf("Expected light to move to %s, got %s", rewritten, light)
+	}
+	if annotated := runGit(t, repoDir, nil, "rev-parse", "annotated^{commit}"); annotated != rewritten {
+		t.Errorf("Expected annotated to move to %s, got %s", rewritten, annotated)
+	}
+	if kind := runGit(t, repoDir, nil, "cat-file", "-t", "annotated"); kind != "tag" {
+		t.Errorf("Expected annotated to stay an annotated tag, got a %s", kind)
+	}
+	if newAnnotated := runGit(t, repoDir, nil, "for-each-ref", "--format="+tagger, "refs/tags/annotated"); newAnnotated != oldAnnotated {
+		t.Errorf("Expected the tagger, date and message kept:\n%s\ngot:\n%s", oldAnnotated, newAnnotated)
+	}
+	if base := runGit(t, repoDir, nil, "rev-parse", "base"); base != oldBase {
+		t.Errorf("Expected the tag outside the range to stay on %s, got %s", oldBase, base)
+	}
+
+	// Undo brings the tags back with the branch
+	if output, err := runGitcommit(t, repoDir, "undo"); err != nil {
+		t.Fatalf("undo failed: %v\nOutput: %s", err, output)
+	}
+	if light := runGit(t, repoDir, nil, "rev-parse", "light"); light != runGit(t, repoDir, nil, "rev-parse", "HEAD~1") {
+		t.Errorf("Expected undo to restore light to HEAD~1, got %s", light)
+	}
+}
+
+// TestRewriteUpdateBranches tests that other branches sharing the rewritten
+// commits only follow with --update-branches, keeping their own dates.
+func TestRewriteUpdateBranches(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	runGit(t, repoDir, nil, "branch", "copy")
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "feature", "HEAD~1")
+	commitDatedFile(t, repoDir, "feature.txt", "2025-03-01T10:00:00")
+	runGit(t, repoDir, nil, "checkout", "-q", "main")
+	oldFeature := runGit(t, repoDir, nil, "rev-parse", "feature")
+	oldCopy := runGit(t, repoDir, nil, "rev-parse", "copy")
+
+	if output, err := runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-02-01 09:00:00"); err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	if feature, copied := runGit(t, repoDir, nil, "rev-parse", "feature"), runGit(t, repoDir, nil, "rev-parse", "copy"); feature != oldFeature || copied != oldCopy {
+		t.Errorf("Expected the branches untouched without --update-branches, got feature %s, copy %s", feature, copied)
+	}
+	if output, err := runGitcommit(t, repoDir, "undo"); err != nil {
+		t.Fatalf("undo failed: %v\nOutput: %s", err, output)
+	}
+
+	// A branch whose commits would come before their rewritten parent is refused
+	output, err := runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-03-05 09:00:00", "--update-branches")
+	if err == nil || !strings.Contains(output, "Chronology violation on branch feature") {
+		t.Fatalf("Expected a chronology violation on feature, got: %v %s", err, output)
+	}
+	if head := runGit(t, repoDir, nil, "rev-parse", "main"); head != oldCopy {
+		t.Errorf("Expected main untouched after the error, got %s", head)
+	}
+
+	output, err = runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-02-01 09:00:00", "--update-branches")
+	if err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{"Updated branch copy", "Updated branch feature"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in: %s", expected, output)
+		}
+	}
+
+	if copied, head := runGit(t, repoDir, nil, "rev-parse", "copy"), runGit(t, repoDir, nil, "rev-parse", "main"); copied != head {
+		t.Errorf("Expected copy to move with main to %s, got %s", head, copied)
+	}
+	if parent, expected := runGit(t, repoDir, nil, "rev-parse", "feature~1"), runGit(t, repoDir, nil, "rev-parse", "main~1"); parent != expected {
+		t.Errorf("Expected feature rebuilt on the rewritten %s, got %s", expected, parent)
+	}
+	if dates := runGit(t, repoDir, nil, "log", "-1", "--format=%ad|%cd", "--date=format:%Y-%m-%dT%H:%M:%S", "feature"); dates != "2025-03-01T10:00:00|2025-03-01T10:00:00" {
+		t.Errorf("Expected the feature commit to keep its dates, got %s", dates)
+	}
+	if shape, expected := runGit(t, repoDir, nil, "log", "-1", "--format=%T|%an|%s", "feature"), runGit(t, repoDir, nil, "log", "-1", "--format=%T|%an|%s", oldFeature); shape != expected {
+		t.Errorf("Expected the feature commit to keep its tree, author and message, got %s", shape)
+	}
+}
@@ -0,0 +1,178 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestShift tests that every commit of the range moves by the same offset.
+func TestShift(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string // author|committer dates, oldest first, of the last three commits
+	}{
+		{
+			name: "backward",
+			args: []string{"HEAD~3", "-2h"},
+			expected: []string{
+				"2025-01-02T08:00:00|2025-01-02T08:00:00",
+				"2025-01-03T08:00:00|2025-01-03T08:00:00",
+				"2025-01-04T08:00:00|2025-01-04T08:00:00",
+			},
+		},
+		{
+			name: "forward",
+			args: []string{"+1h30m", "main~3..main"},
+			expected: []string{
+				"2025-01-02T11:30:00|2025-01-02T11:30:00",
+				"2025-01-03T11:30:00|2025-01-03T11:30:00",
+				"2025-01-04T11:30:00|2025-01-04T11:30:00",
+			},
+		},
+		{
+			name: "documented form with flags after the offset",
+			args: []string{"HEAD~3", "--", "-24h", "--dates=committer"},
+			expected: []string{
+				"2025-01-02T10:00:00|2025-01-01T10:00:00",
+				"2025-01-03T10:00:00|2025-01-02T10:00:00",
+				"2025-01-04T10:00:00|2025-01-03T10:00:00",
+			},
+		},
+		{
+			name: "committer dates only",
+			args: []string{"HEAD~3..", "--dates=committer", "--", "-24h"},
+			expected: []string{
+				"2025-01-02T10:00:00|2025-01-01T10:00:00",
+				"2025-01-03T10:00:00|2025-01-02T10:00:00",
+				"2025-01-04T10:00:00|2025-01-03T10:00:00",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupRedateRepo(t)
+			defer os.RemoveAll(repoDir)
+			shape := historyShape(t, repoDir)
+			zones := runGit(t, repoDir, nil, "log", "--format=%ad", "--date=format:%z")
+
+			output, err := runGitcommit(t, repoDir, append([]string{"shift"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(output, "✓ Rewrote 3 commit(s) on main with gitcommit shift") {
+				t.Errorf("Expected success message, got: %s", output)
+			}
+
+			dates := strings.Split(runGit(t, repoDir, nil, "log", "-3", "--reverse", "--format=%aI|%cI"), "\n")
+			for i, line := range dates {
+				author, committer, _ := strings.Cut(line, "|")
+				expectedAuthor, expectedCommitter, _ := strings.Cut(tt.expected[i], "|")
+				if !strings.HasPrefix(author, expectedAuthor) || !strings.HasPrefix(committer, expectedCommitter) {
+					t.Errorf("Commit %d: expected %s, got %s", i+1, tt.expected[i], line)
+				}
+			}
+
+			// Only the instants change: trees, identities, subjects and zone offsets stay
+			if newShape := historyShape(t, repoDir); newShape != shape {
+				t.Errorf("Expected trees, identities and subjects to be kept\nbefore:\n%s\nafter:\n%s", shape, newShape)
+			}
+			if newZones := runGit(t, repoDir, nil, "log", "--format=%ad", "--date=format:%z"); newZones != zones {
+				t.Errorf("Expected zone offsets to be kept, got %s", newZones)
+			}
+			if reflog := runGit(t, repoDir, nil, "reflog", "-1", "--format=%gs", "main"); !strings.HasPrefix(reflog, "gitcommit shift: ") {
+				t.Errorf("Expected a reflog entry, got %q", reflog)
+			}
+		})
+	}
+}
+
+// TestShiftDryRun tests that --dry-run shows the before and after dates without rewriting.
+func TestShiftDryRun(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	output, err := runGitcommit(t, repoDir, "shift", "HEAD~3", "-2h", "--dry-run")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{"dry run, nothing rewritten", "Sat 4 Jan 2025 10:00:00", "Sat 4 Jan 2025 08:00:00", "Add three.txt"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in: %s", expected, output)
+		}
+	}
+	if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+		t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+	}
+}
+
+// TestShiftErrors tests that invalid shifts leave history untouched.
+func TestShiftErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "before the parent", args: []string{"HEAD~3", "-24h"}, expected: "largest backward shift"},
+		{name: "far before the parent", args: []string{"HEAD~3", "-72h"}, expected: "outside the range"},
+		{name: "invalid offset", args: []string{"HEAD~3", "+2 hours"}, expected: "Invalid offset"},
+		{name: "missing offset", args: []string{"HEAD~3"}, expected: "shift"},
+		{name: "invalid dates selection", args: []string{"HEAD~3", "+1h", "--dates=tagger"}, expected: "Invalid dates selection"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupRedateRepo(t)
+			defer os.RemoveAll(repoDir)
+			head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+			output, err := runGitcommit(t, repoDir, append([]string{"shift"}, tt.args...)...)
+			if err == nil {
+				t.Fatalf("Expected command to fail, got: %s", output)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+			if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+				t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+			}
+		})
+	}
+}
+
+// TestShiftKeepsRecordedCommits tests that identities git accepts without
+// "@" and the encoding header of a commit survive a rewrite.
+func TestShiftKeepsRecordedCommits(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	dated := []string{"GIT_AUTHOR_DATE=2025-01-01T10:00:00", "GIT_COMMITTER_DATE=2025-01-01T10:00:00"}
+	runGit(t, repoDir, dated, "commit", "--allow-empty", "-m", "Base")
+
+	messageFile := filepath.Join(t.TempDir(), "message")
+	if err := os.WriteFile(messageFile, []byte("Caf\xe9\n"), 0644); err != nil {
+		t.Fatalf("Failed to write message: %v", err)
+	}
+	runGit(t, repoDir, append(dated, "GIT_AUTHOR_NAME=T", "GIT_AUTHOR_EMAIL=build"),
+		"-c", "i18n.commitEncoding=ISO-8859-1", "commit", "--allow-empty", "-F", messageFile)
+
+	output, err := runGitcommit(t, repoDir, "shift", "HEAD~1", "+1h")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	raw := runGit(t, repoDir, nil, "cat-file", "commit", "HEAD")
+	if !strings.Contains(raw, "\nauthor T <build> ") {
+		t.Errorf("Expected the author to be kept, got:\n%s", raw)
+	}
+	if !strings.Contains(raw, "\nencoding ISO-8859-1\n") || !strings.HasSuffix(raw, "\n\nCaf\xe9") {
+		t.Errorf("Expected the encoding and message bytes to be kept, got:\n%q", raw)
+	}
+	if date := runGit(t, repoDir, nil, "log", "-1", "--format=%aI"); !strings.HasPrefix(date, "2025-01-01T11:00:00") {
+		t.Errorf("Expected the commit to be shifted, got %s", date)
+	}
+}
@@ -0,0 +1,374 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupSigningRepo creates a repository configured for SSH signing with a
+// throwaway key trusted through gpg.ssh.allowedSignersFile, and a staged file.
+// Returns the repository and the path of the public key.
+func setupSigningRepo(t *testing.T) (string, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	repoDir := setupTestRepo(t)
+	keyDir := t.TempDir()
+	key := filepath.Join(keyDir, "signing_key")
+
+	cmd := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "test", "-f", key)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen failed: %v\nOutput: %s", err, output)
+	}
+
+	publicKey, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatalf("Failed to read public key: %v", err)
+	}
+	allowedSigners := filepath.Join(keyDir, "allowed_signers")
+	if err := os.WriteFile(allowedSigners, []byte("test@example.com "+string(publicKey)), 0644); err != nil {
+		t.Fatalf("Failed to write allowed signers: %v", err)
+	}
+
+	runGit(t, repoDir, nil, "config", "gpg.format", "ssh")
+	runGit(t, repoDir, nil, "config", "gpg.ssh.allowedSignersFile", allowedSigners)
+
+	if err := os.WriteFile(filepath.Join(repoDir, "signed.txt"), []byte("signed"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "signed.txt")
+
+	return repoDir, key + ".pub"
+}
+
+// TestSigning tests -S, -S<keyid>, commit.gpgsign and --no-gpg-sign.
+func TestSigning(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     map[string]string
+		args       func(key string) []string
+		wantSigned bool
+	}{
+		{
+			name:       "-S with user.signingkey",
+			config:     map[string]string{"user.signingkey": ""},
+			args:       func(string) []string { return []string{"-S"} },
+			wantSigned: true,
+		},
+		{
+			name:       "-S with attached key",
+			args:       func(key string) []string { return []string{"-S" + key} },
+			wantSigned: true,
+		},
+		{
+			name:       "--gpg-sign with key",
+			args:       func(key string) []string { return []string{"--gpg-sign=" + key} },
+			wantSigned: true,
+		},
+		{
+			name:       "commit.gpgsign",
+			config:     map[string]string{"user.signingkey": "", "commit.gpgsign": "true"},
+			args:       func(string) []string { return nil },
+			wantSigned: true,
+		},
+		{
+			name:       "--no-gpg-sign overrides commit.gpgsign",
+			config:     map[string]string{"user.signingkey": "", "commit.gpgsign": "true"},
+			args:       func(string) []string { return []string{"--no-gpg-sign"} },
+			wantSigned: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir, key := setupSigningRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			for name, value := range tt.config {
+				if name == "user.signingkey" {
+					value = key
+				}
+				runGit(t, repoDir, nil, "config", name, value)
+			}
+
+			args := append(tt.args(key), "2025-02-01 10:00:00", "Signed work")
+			cmd := exec.Command(getBinaryPath(t), args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+
+			status := runGit(t, repoDir, nil, "log", "-1", "--format=%G?")
+			if tt.wantSigned {
+				if status != "G" {
+					t.Errorf("Expected a good signature, got status %q", status)
+				}
+				if !strings.Contains(string(output), "Signature: good signature from test@example.com") {
+					t.Errorf("Expected signature in success message, got: %s", output)
+				}
+			} else {
+				if status != "N" {
+					t.Errorf("Expected no signature, got status %q", status)
+				}
+				if strings.Contains(string(output), "Signature:") {
+					t.Errorf("Expected no signature in success message, got: %s", output)
+				}
+			}
+		})
+	}
+}
+
+// TestSigningFailure tests that a missing signing key is reported before anything is committed.
+func TestSigningFailure(t *testing.T) {
+	repoDir, _ := setupSigningRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	cmd := exec.Command(getBinaryPath(t), "-S", "2025-02-01 10:00:00", "Signed work")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+
+	if !strings.Contains(string(output), "Commit could not be signed") || !strings.Contains(string(output), "user.signingkey") {
+		t.Errorf("Expected signing failure with hint, got: %s", output)
+	}
+	if out, _ := exec.Command("git", "-C", repoDir, "rev-parse", "--verify", "--quiet", "HEAD").Output(); len(out) > 0 {
+		t.Error("Expected no commit to be created")
+	}
+}
+
+// TestSigningUnverifiable tests that a signature git cannot check is committed with a warning.
+func TestSigningUnverifiable(t *testing.T) {
+	repoDir, key := setupSigningRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	runGit(t, repoDir, nil, "config", "--unset", "gpg.ssh.allowedSignersFile")
+
+	cmd := exec.Command(getBinaryPath(t), "-S"+key, "2025-02-01 10:00:00", "Signed work")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(string(output), "could not be verified") || !strings.Contains(string(output), "allowedSignersFile") {
+		t.Errorf("Expected verification warning, got: %s", output)
+	}
+}
+
+// TestConflictingSignOptions tests that -S and --no-gpg-sign cannot be combined.
+func TestConflictingSignOptions(t *testing.T) {
+	cmd := exec.Command(getBinaryPath(t), "-S", "--no-gpg-sign", "2025-02-01 10:00:00", "Signed work")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected command to fail, got: %s", output)
+	}
+	if !strings.Contains(string(output), "Conflicting options") {
+		t.Errorf("Expected conflicting options error, got: %s", output)
+	}
+}
+
+// TestRewriteResign tests that a rewrite reports the signatures it drops,
+// and signs the rewritten commits again with --resign.
+func TestRewriteResign(t *testing.T) {
+	repoDir, key := setupSigningRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	runGit(t, repoDir, nil, "config", "user.signingkey", key)
+	runGit(t, repoDir, nil, "config", "commit.gpgsign", "true")
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-01T10:00:00", "GIT_COMMITTER_DATE=2025-01-01T10:00:00"},
+		"commit", "-m", "Add signed.txt")
+	commitDatedFile(t, repoDir, "one.txt", "2025-01-02T10:00:00")
+	commitDatedFile(t, repoDir, "two.txt", "2025-01-03T10:00:00")
+	signed := runGit(t, repoDir, nil, "rev-parse", "HEAD~1", "HEAD")
+
+	output, err := runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-02-01 09:00:00", "--dry-run")
+	if err != nil || !strings.Contains(output, "It would drop the signatures of 2 commit(s)") {
+		t.Errorf("Expected the dry run to list the signatures dropped, got: %v %s", err, output)
+	}
+
+	output, err = runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-02-01 09:00:00")
+	if err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Signatures dropped from 2 commit(s)") {
+		t.Errorf("Expected the signatures dropped to be reported, got: %s", output)
+	}
+	for _, hash := range strings.Fields(signed) {
+		if !strings.Contains(output, hash[:7]) {
+			t.Errorf("Expected %s among the commits that lost their signature: %s", hash[:7], output)
+		}
+	}
+	if status := runGit(t, repoDir, nil, "log", "-2", "--format=%G?"); status != "N\nN" {
+		t.Errorf("Expected the rewritten commits unsigned, got %q", status)
+	}
+
+	if output, err := runGitcommit(t, repoDir, "undo"); err != nil {
+		t.Fatalf("undo failed: %v\nOutput: %s", err, output)
+	}
+	output, err = runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-02-01 09:00:00", "--resign")
+	if err != nil {
+		t.Fatalf("redate --resign failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Signed 2 rewritten commit(s) with your ssh key") || strings.Contains(output, "dropped") {
+		t.Errorf("Expected the commits signed again, got: %s", output)
+	}
+	if status := runGit(t, repoDir, nil, "log", "-2", "--format=%G?"); status != "G\nG" {
+		t.Errorf("Expected good signatures on the rewritten commits, got %q", status)
+	}
+	if dates := runGit(t, repoDir, nil, "log", "-1", "--format=%ad", "--date=format:%Y-%m-%dT%H:%M:%S", "HEAD~1"); dates != "2025-02-01T09:00:00" {
+		t.Errorf("Expected the new dates kept when signing, got %s", dates)
+	}
+
+	// A key that cannot sign moves nothing
+	head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+	runGit(t, repoDir, nil, "config", "user.signingkey", filepath.Join(t.TempDir(), "missing.pub"))
+	output, err = runGitcommit(t, repoDir, "redate", "HEAD~2", "--start", "2025-03-01 09:00:00", "--resign")
+	if err == nil || !strings.Contains(output, "Rewritten commit could not be signed") {
+		t.Errorf("Expected a signing error, got: %v %s", err, output)
+	}
+	if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+		t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+	}
+}
+
+// setupSignedTagsRepo creates a repository whose main branch merges the
+// signed tag side-v1 of a side branch, recorded in a mergetag header, and
+// whose merge carries the signed tag v1.0.
+func setupSignedTagsRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir, key := setupSigningRepo(t)
+	runGit(t, repoDir, nil, "config", "user.signingkey", key)
+	dated := func(date string) []string {
+		return []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}
+	}
+
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+	runGit(t, repoDir, dated("2025-01-01T10:00:00"), "commit", "-m", "Add signed.txt")
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "side")
+	commitDatedFile(t, repoDir, "side.txt", "2025-01-02T10:00:00")
+	runGit(t, repoDir, dated("2025-01-02T11:00:00"), "tag", "-s", "-m", "Side release", "side-v1")
+	runGit(t, repoDir, nil, "checkout", "-q", "main")
+	commitDatedFile(t, repoDir, "main.txt", "2025-01-03T10:00:00")
+	runGit(t, repoDir, dated("2025-01-04T10:00:00"), "merge", "--no-ff", "-m", "Merge side-v1", "side-v1")
+	runGit(t, repoDir, dated("2025-01-04T11:00:00"), "tag", "-s", "-m", "Release", "v1.0")
+
+	if raw := runGit(t, repoDir, nil, "cat-file", "commit", "HEAD"); !strings.Contains(raw, "\nmergetag object ") {
+		t.Fatalf("Expected the merge to record a mergetag header, got:\n%s", raw)
+	}
+	return repoDir
+}
+
+// TestRewriteSignedTags tests that the signatures of recreated tags and of
+// voided mergetag headers are reported, and that --resign signs tags again.
+func TestRewriteSignedTags(t *testing.T) {
+	repoDir := setupSignedTagsRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	output, err := runGitcommit(t, repoDir, "shift", "main~2..main", "+1h", "--dry-run")
+	if err != nil {
+		t.Fatalf("dry run failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "It would drop the signatures of 2 tag(s): side-v1, v1.0") ||
+		!strings.Contains(output, "It would drop the merged tag signatures of 1 merge(s): side-v1 in ") {
+		t.Errorf("Expected the dry run to list the tag and mergetag signatures, got: %s", output)
+	}
+
+	output, err = runGitcommit(t, repoDir, "shift", "main~2..main", "+1h")
+	if err != nil {
+		t.Fatalf("shift failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Signatures dropped from 2 tag(s) that were signed: side-v1, v1.0") ||
+		!strings.Contains(output, "Merged tag signatures dropped from 1 merge(s): side-v1 in ") {
+		t.Errorf("Expected the dropped signatures to be reported, got: %s", output)
+	}
+	if raw := runGit(t, repoDir, nil, "cat-file", "commit", "HEAD"); strings.Contains(raw, "mergetag") {
+		t.Errorf("Expected the voided mergetag header to be dropped, got:\n%s", raw)
+	}
+
+	if output, err := runGitcommit(t, repoDir, "undo"); err != nil {
+		t.Fatalf("undo failed: %v\nOutput: %s", err, output)
+	}
+	output, err = runGitcommit(t, repoDir, "shift", "main~2..main", "+1h", "--resign")
+	if err != nil {
+		t.Fatalf("shift --resign failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "and 2 recreated tag(s) with your ssh key") ||
+		strings.Contains(output, "that were signed") {
+		t.Errorf("Expected the tag to be signed again, got: %s", output)
+	}
+	if !strings.Contains(output, "Merged tag signatures dropped from 1 merge(s)") {
+		t.Errorf("Expected the mergetag signature to be reported, got: %s", output)
+	}
+	runGit(t, repoDir, nil, "tag", "-v", "side-v1", "v1.0")
+	tag := runGit(t, repoDir, nil, "for-each-ref", "--format=%(taggerdate:iso-strict)|%(contents:subject)|%(*objectname)", "refs/tags/v1.0")
+	if expected := "2025-01-04T11:00:00+00:00|Release|" + runGit(t, repoDir, nil, "rev-parse", "HEAD"); tag != expected {
+		t.Errorf("Expected the re-signed tag %q, got %q", expected, tag)
+	}
+}
+
+// TestRewriteKeepsMergeTag tests that a mergetag header is kept when the
+// tagged parent is not rewritten.
+func TestRewriteKeepsMergeTag(t *testing.T) {
+	repoDir := setupSignedTagsRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	output, err := runGitcommit(t, repoDir, "redate", "main~1..main", "--set", "main=2025-01-05 10:00:00")
+	if err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "Merged tag signatures dropped") {
+		t.Errorf("Expected no mergetag signature to be dropped, got: %s", output)
+	}
+	if raw := runGit(t, repoDir, nil, "cat-file", "commit", "HEAD"); !strings.Contains(raw, "\nmergetag object ") {
+		t.Errorf("Expected the mergetag header to be kept, got:\n%s", raw)
+	}
+}
+
+// TestResignTagSHA256 tests that --resign signs tags in a SHA-256
+// repository, with a key set in the worktree configuration only.
+func TestResignTagSHA256(t *testing.T) {
+	repoDir, key := setupSigningRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	// Start over as a SHA-256 repository with the same configuration
+	config := runGit(t, repoDir, nil, "config", "--local", "--list")
+	if err := os.RemoveAll(filepath.Join(repoDir, ".git")); err != nil {
+		t.Fatalf("Failed to remove the repository: %v", err)
+	}
+	runGit(t, repoDir, nil, "init", "-q", "--object-format=sha256")
+	for _, line := range strings.Split(config, "\n") {
+		name, value, _ := strings.Cut(line, "=")
+		if strings.HasPrefix(name, "user.") || strings.HasPrefix(name, "gpg.") {
+			runGit(t, repoDir, nil, "config", name, value)
+		}
+	}
+	runGit(t, repoDir, nil, "config", "extensions.worktreeConfig", "true")
+	runGit(t, repoDir, nil, "config", "--worktree", "user.signingkey", key)
+
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+	commitDatedFile(t, repoDir, "one.txt", "2025-01-01T10:00:00")
+	commitDatedFile(t, repoDir, "two.txt", "2025-01-02T10:00:00")
+	runGit(t, repoDir, []string{"GIT_COMMITTER_DATE=2025-01-02T11:00:00"}, "tag", "-s", "-m", "Release", "v1.0")
+
+	output, err := runGitcommit(t, repoDir, "shift", "main~1..main", "+1h", "--resign")
+	if err != nil {
+		t.Fatalf("shift --resign failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "and 1 recreated tag(s) with your ssh key") {
+		t.Errorf("Expected the tag to be signed again, got: %s", output)
+	}
+	runGit(t, repoDir, nil, "tag", "-v", "v1.0")
+	if tagged, head := runGit(t, repoDir, nil, "rev-parse", "v1.0^{commit}"), runGit(t, repoDir, nil, "rev-parse", "HEAD"); tagged != head {
+		t.Errorf("Expected v1.0 on the rewritten %s, got %s", head, tagged)
+	}
+}
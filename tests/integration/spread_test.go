@@ -0,0 +1,171 @@
+package integration
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestSpreadDistributions tests the dates given by each distribution and policy.
+func TestSpreadDistributions(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   [][]string
+		args     []string
+		expected []string // author dates, oldest first, of the last three commits
+	}{
+		{
+			name:     "even",
+			args:     []string{"HEAD~3", "--from", "2025-01-06 00:00:00", "--to", "2025-01-06 12:00:00"},
+			expected: []string{"2025-01-06T00:00:00", "2025-01-06T06:00:00", "2025-01-06T12:00:00"},
+		},
+		{
+			name:   "working hours around a holiday",
+			config: [][]string{{"--add", "gitcommit.holiday", "2025-01-08"}},
+			args: []string{"HEAD~3", "--from", "2025-01-06 00:00:00", "--to", "2025-01-12 23:00:00",
+				"--distribution=working-hours"},
+			expected: []string{"2025-01-06T09:00:00", "2025-01-07T16:59:59", "2025-01-10T16:59:59"},
+		},
+		{
+			name:     "configured working hours",
+			config:   [][]string{{"gitcommit.workingHours", "10:00-12:00"}},
+			args:     []string{"HEAD~3", "--from", "2025-01-06 00:00:00", "--to", "2025-01-06 23:59:59"},
+			expected: []string{"2025-01-06T10:00:00", "2025-01-06T10:59:59", "2025-01-06T11:59:59"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupRedateRepo(t)
+			defer os.RemoveAll(repoDir)
+			for _, config := range tt.config {
+				runGit(t, repoDir, nil, append([]string{"config"}, config...)...)
+			}
+			shape := historyShape(t, repoDir)
+
+			output, err := runGitcommit(t, repoDir, append([]string{"spread"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(output, "✓ Rewrote 3 commit(s) on main with gitcommit spread") {
+				t.Errorf("Expected success message, got: %s", output)
+			}
+
+			dates := strings.Split(runGit(t, repoDir, nil, "log", "-3", "--reverse", "--format=%aI|%cI"), "\n")
+			for i, line := range dates {
+				author, committer, _ := strings.Cut(line, "|")
+				if !strings.HasPrefix(author, tt.expected[i]) || committer != author {
+					t.Errorf("Commit %d: expected %s, got %s", i+1, tt.expected[i], line)
+				}
+			}
+			if newShape := historyShape(t, repoDir); newShape != shape {
+				t.Errorf("Expected trees, identities and subjects to be kept\nbefore:\n%s\nafter:\n%s", shape, newShape)
+			}
+		})
+	}
+}
+
+// TestSpreadRandom tests that a seed draws the same increasing dates again.
+func TestSpreadRandom(t *testing.T) {
+	args := []string{"HEAD~3", "--from", "2025-01-06 00:00:00", "--to", "2025-01-12 23:59:59",
+		"--distribution=random", "--seed=7"}
+
+	var runs []string
+	for range 2 {
+		repoDir := setupRedateRepo(t)
+		defer os.RemoveAll(repoDir)
+
+		output, err := runGitcommit(t, repoDir, append([]string{"spread"}, args...)...)
+		if err != nil {
+			t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+		}
+		if !strings.Contains(output, "Random seed: 7") {
+			t.Errorf("Expected the seed to be shown, got: %s", output)
+		}
+
+		dates := runGit(t, repoDir, nil, "log", "-3", "--reverse", "--format=%at")
+		runs = append(runs, dates)
+		if ordered := runGit(t, repoDir, nil, "log", "-3", "--reverse", "--format=%aI"); !strings.HasPrefix(ordered, "2025-01-") {
+			t.Errorf("Expected dates within the window, got:\n%s", ordered)
+		}
+		stamps := strings.Split(dates, "\n")
+		for i := 1; i < len(stamps); i++ {
+			if stamps[i] <= stamps[i-1] {
+				t.Errorf("Expected strictly increasing dates, got %v", stamps)
+			}
+		}
+	}
+	if runs[0] != runs[1] {
+		t.Errorf("Expected the same dates for the same seed, got:\n%s\nand:\n%s", runs[0], runs[1])
+	}
+}
+
+// TestSpreadErrors tests that invalid spreads leave history untouched.
+func TestSpreadErrors(t *testing.T) {
+	window := []string{"--from", "2025-01-06 00:00:00", "--to", "2025-01-12 23:59:59"}
+
+	tests := []struct {
+		name     string
+		config   []string
+		args     []string
+		expected string
+	}{
+		{name: "missing window", args: []string{"HEAD~3", "--from", "2025-01-06 00:00:00"}, expected: "--from and --to"},
+		{
+			name:     "window too small",
+			args:     []string{"HEAD~3", "--from", "2025-01-06 10:00:00", "--to", "2025-01-06 10:00:01"},
+			expected: "Window too small",
+		},
+		{
+			name:     "weekend only",
+			args:     []string{"HEAD~3", "--from", "2025-01-11 00:00:00", "--to", "2025-01-12 23:59:59", "--distribution=working-hours"},
+			expected: "Window too small",
+		},
+		{
+			name:     "reversed window",
+			args:     []string{"HEAD~3", "--from", "2025-01-12 00:00:00", "--to", "2025-01-06 00:00:00"},
+			expected: "before it starts",
+		},
+		{
+			name:     "before the base",
+			args:     []string{"HEAD~3", "--from", "2024-12-30 00:00:00", "--to", "2025-01-12 00:00:00"},
+			expected: "Its parent",
+		},
+		{name: "invalid distribution", args: append([]string{"HEAD~3", "--distribution=weekly"}, window...), expected: "Invalid distribution"},
+		{name: "seed without random", args: append([]string{"HEAD~3", "--seed=7"}, window...), expected: "Conflicting options"},
+		{
+			name:     "invalid working hours",
+			config:   []string{"gitcommit.workingHours", "9-5"},
+			args:     append([]string{"HEAD~3"}, window...),
+			expected: "gitcommit.workingHours is invalid",
+		},
+		{
+			name:     "invalid holiday",
+			config:   []string{"gitcommit.holiday", "Christmas"},
+			args:     append([]string{"HEAD~3"}, window...),
+			expected: "gitcommit.holiday is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupRedateRepo(t)
+			defer os.RemoveAll(repoDir)
+			if tt.config != nil {
+				runGit(t, repoDir, nil, append([]string{"config"}, tt.config...)...)
+			}
+			head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+			output, err := runGitcommit(t, repoDir, append([]string{"spread"}, tt.args...)...)
+			if err == nil {
+				t.Fatalf("Expected command to fail, got: %s", output)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+			if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+				t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// setupTagRepo creates a repository with two commits, committed on
+// 2025-01-01 and 2025-02-01 at 10:00.
+func setupTagRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := setupTestRepo(t)
+	for _, date := range []string{"2025-01-01T10:00:00", "2025-02-01T10:00:00"} {
+		runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date},
+			"commit", "--allow-empty", "-m", "Commit of "+date)
+	}
+	return repoDir
+}
+
+// TestTag tests that annotated tags get the given tagger date and message.
+func TestTag(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		tagName         string
+		expectedTarget  string
+		expectedDate    string
+		expectedMessage string
+	}{
+		{
+			name:            "HEAD",
+			args:            []string{"2025-02-03 17:00:00", "v1.0.0", "-m", "Release 1.0.0"},
+			tagName:         "v1.0.0",
+			expectedTarget:  "HEAD",
+			expectedDate:    "2025-02-03T17:00:00",
+			expectedMessage: "Release 1.0.0",
+		},
+		{
+			name:            "older commit",
+			args:            []string{"-m", "Beta", "-m", "First public build", "2025-01-05 12:00:00", "v0.9.0", "HEAD~1"},
+			tagName:         "v0.9.0",
+			expectedTarget:  "HEAD~1",
+			expectedDate:    "2025-01-05T12:00:00",
+			expectedMessage: "Beta\n\nFirst public build",
+		},
+		{
+			name:            "same date as the commit",
+			args:            []string{"-m", "Release", "2025-02-01 10:00:00", "v1.0.0"},
+			tagName:         "v1.0.0",
+			expectedTarget:  "HEAD",
+			expectedDate:    "2025-02-01T10:00:00",
+			expectedMessage: "Release",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTagRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			output, err := runGitcommit(t, repoDir, append([]string{"tag"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(output, "✓ Tag") {
+				t.Errorf("Expected success message, got: %s", output)
+			}
+
+			name := tt.tagName
+			fields := strings.Split(runGit(t, repoDir, nil, "for-each-ref",
+				"--format=%(objecttype)|%(taggerdate:iso-strict)|%(*objectname)", "refs/tags/"+name), "|")
+			if len(fields) != 3 || fields[0] != "tag" {
+				t.Fatalf("Expected annotated tag %s, got %v", name, fields)
+			}
+			if !strings.HasPrefix(fields[1], tt.expectedDate) {
+				t.Errorf("Expected tagger date %s, got %s", tt.expectedDate, fields[1])
+			}
+			if target := runGit(t, repoDir, nil, "rev-parse", tt.expectedTarget); fields[2] != target {
+				t.Errorf("Expected tag on %s, got %s", target, fields[2])
+			}
+			if message := runGit(t, repoDir, nil, "tag", "-l", "--format=%(contents)", name); message != tt.expectedMessage {
+				t.Errorf("Expected message %q, got %q", tt.expectedMessage, message)
+			}
+		})
+	}
+}
+
+// TestTagSigned tests that -u signs the tag with the given key.
+func TestTagSigned(t *testing.T) {
+	repoDir, key := setupSigningRepo(t)
+	defer os.RemoveAll(repoDir)
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-01T10:00:00", "GIT_COMMITTER_DATE=2025-01-01T10:00:00"},
+		"commit", "-m", "Signed file")
+
+	if output, err := runGitcommit(t, repoDir, "tag", "-u", key, "-m", "Signed release", "2025-01-02 10:00:00", "v1.0.0"); err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	cmd := exec.Command("git", "verify-tag", "v1.0.0")
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("Expected a valid signature, got: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestTagErrors tests validation of tag dates, names and messages.
+func TestTagErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, repoDir string)
+		args     []string
+		expected string
+	}{
+		{
+			name:     "before the tagged commit",
+			args:     []string{"-m", "Release", "2025-01-15 10:00:00", "v1.0.0"},
+			expected: "Chronology violation",
+		},
+		{
+			name:     "missing message",
+			args:     []string{"2025-02-03 17:00:00", "v1.0.0"},
+			expected: "Tag message required",
+		},
+		{
+			name:     "invalid name",
+			args:     []string{"-m", "Release", "2025-02-03 17:00:00", "v1..0"},
+			expected: "Invalid tag name",
+		},
+		{
+			name:     "unknown revision",
+			args:     []string{"-m", "Release", "2025-02-03 17:00:00", "v1.0.0", "nope"},
+			expected: "nope",
+		},
+		{
+			name: "existing tag",
+			setup: func(t *testing.T, repoDir string) {
+				runGit(t, repoDir, nil, "tag", "v1.0.0")
+			},
+			args:     []string{"-m", "Release", "2025-02-03 17:00:00", "v1.0.0"},
+			expected: "Tag already exists",
+		},
+		{
+			name:     "too many arguments",
+			args:     []string{"-m", "Release", "2025-02-03 17:00:00", "v1.0.0", "HEAD", "extra"},
+			expected: "Wrong number of arguments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTagRepo(t)
+			defer os.RemoveAll(repoDir)
+			if tt.setup != nil {
+				tt.setup(t, repoDir)
+			}
+
+			output, err := runGitcommit(t, repoDir, append([]string{"tag"}, tt.args...)...)
+			if err == nil {
+				t.Fatalf("Expected command to fail, got: %s", output)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+		})
+	}
+}
+
+// TestTagForce tests that -f replaces an existing tag.
+func TestTagForce(t *testing.T) {
+	repoDir := setupTagRepo(t)
+	defer os.RemoveAll(repoDir)
+	runGit(t, repoDir, nil, "tag", "v1.0.0", "HEAD~1")
+
+	if output, err := runGitcommit(t, repoDir, "tag", "-f", "-m", "Release", "2025-02-03 17:00:00", "v1.0.0"); err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	if target, head := runGit(t, repoDir, nil, "rev-parse", "v1.0.0^{commit}"), runGit(t, repoDir, nil, "rev-parse", "HEAD"); target != head {
+		t.Errorf("Expected the tag to move to HEAD, got %s", target)
+	}
+}
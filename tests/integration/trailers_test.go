@@ -0,0 +1,123 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestTrailers tests --signoff, --co-author and --trailer, including de-duplication.
+func TestTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "signoff",
+			args:     []string{"--signoff", "2025-02-01 10:00:00", "Add feature"},
+			expected: "Add feature\n\nSigned-off-by: Test User <test@example.com>",
+		},
+		{
+			name: "co-authors, trailers and signoff in order",
+			args: []string{
+				"--co-author", "Jane Doe <jane@example.com>",
+				"--co-author", "John Roe <john@example.com>",
+				"--trailer", "Refs=#123",
+				"-s",
+				"2025-02-01 10:00:00", "Add feature",
+			},
+			expected: "Add feature\n\n" +
+				"Co-authored-by: Jane Doe <jane@example.com>\n" +
+				"Co-authored-by: John Roe <john@example.com>\n" +
+				"Refs: #123\n" +
+				"Signed-off-by: Test User <test@example.com>",
+		},
+		{
+			name: "joins the existing trailer block without duplicates",
+			args: []string{
+				"--co-author", "Jane Doe <jane@example.com>",
+				"--trailer", "Refs: #123",
+				"--trailer", "Refs=#123",
+				"2025-02-01 10:00:00", "Add feature\n\nBody.\n\nCo-authored-by: Jane Doe <jane@example.com>",
+			},
+			expected: "Add feature\n\nBody.\n\n" +
+				"Co-authored-by: Jane Doe <jane@example.com>\n" +
+				"Refs: #123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTestRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			cmd := exec.Command(getBinaryPath(t), append([]string{"--allow-empty"}, tt.args...)...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+
+			if message := runGit(t, repoDir, nil, "log", "-1", "--format=%B"); message != tt.expected {
+				t.Errorf("Expected message:\n%s\ngot:\n%s", tt.expected, message)
+			}
+		})
+	}
+}
+
+// TestAmendAddsTrailersToHeadMessage tests that trailers are added to the kept message when amending.
+func TestAmendAddsTrailersToHeadMessage(t *testing.T) {
+	repoDir := setupAmendRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	cmd := exec.Command(getBinaryPath(t), "--amend", "--signoff", "2025-03-02 10:00:00")
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+
+	expected := "Last commit\n\nSigned-off-by: Test User <test@example.com>"
+	if message := runGit(t, repoDir, nil, "log", "-1", "--format=%B"); message != expected {
+		t.Errorf("Expected message:\n%s\ngot:\n%s", expected, message)
+	}
+}
+
+// TestInvalidTrailers tests that malformed co-authors and trailers are rejected.
+func TestInvalidTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "co-author without email",
+			args:     []string{"--co-author", "Jane Doe"},
+			expected: "Invalid identity",
+		},
+		{
+			name:     "trailer without separator",
+			args:     []string{"--trailer", "Refs"},
+			expected: "Invalid trailer",
+		},
+		{
+			name:     "trailer key with spaces",
+			args:     []string{"--trailer", "Bug fix=#1"},
+			expected: "Invalid trailer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append(tt.args, "2025-02-01 10:00:00", "Add feature")
+			output, err := exec.Command(getBinaryPath(t), args...).CombinedOutput()
+			if err == nil {
+				t.Fatalf("Expected command to fail, got: %s", output)
+			}
+			if !strings.Contains(string(output), tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupZonesRepo creates a main branch whose commits were recorded with
+// mixed zone offsets: three by "Original Author" and the last by "Other Author".
+func setupZonesRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := setupTestRepo(t)
+	runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+	commitDatedFile(t, repoDir, "base.txt", "2025-01-01T10:00:00+0000")
+	commitDatedFile(t, repoDir, "winter.txt", "2025-01-02T10:00:00+0200")
+	commitDatedFile(t, repoDir, "summer.txt", "2025-07-02T10:00:00+0000")
+
+	if err := os.WriteFile(filepath.Join(repoDir, "other.txt"), []byte("other\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "other.txt")
+	runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-07-04T10:00:00+0200", "GIT_COMMITTER_DATE=2025-07-04T10:00:00+0200",
+		"GIT_AUTHOR_NAME=Other Author", "GIT_AUTHOR_EMAIL=Other@Example.com"},
+		"commit", "-m", "Add other.txt")
+	return repoDir
+}
+
+// TestTZNormalize tests that offsets change while instants are kept.
+func TestTZNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		zoneMap  string
+		config   bool // name the map in git config rather than with --map
+		args     []string
+		expected string // author and committer offsets of the last three commits, newest first
+	}{
+		{
+			name:     "location follows daylight saving",
+			args:     []string{"HEAD~3", "--to", "Europe/Paris"},
+			expected: "+0200 +0200\n+0200 +0200\n+0100 +0100",
+		},
+		{
+			name:     "fixed offset for author dates only",
+			args:     []string{"HEAD~3", "--to", "+0000", "--dates=author"},
+			expected: "+0000 +0200\n+0000 +0000\n+0000 +0200",
+		},
+		{
+			name:     "per-person map with a fallback",
+			zoneMap:  "# Team zones\nother@example.com -0500\n\nTest User <test@example.com> UTC\n",
+			args:     []string{"HEAD~3", "--to", "+0100"},
+			expected: "-0500 +0000\n+0100 +0000\n+0100 +0000",
+		},
+		{
+			name:     "map from git config",
+			zoneMap:  "original@example.com +0300\n",
+			config:   true,
+			args:     []string{"HEAD~3"},
+			expected: "+0200 +0200\n+0300 +0000\n+0300 +0200",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupZonesRepo(t)
+			defer os.RemoveAll(repoDir)
+			shape := historyShape(t, repoDir)
+			instants := runGit(t, repoDir, nil, "log", "--format=%at %ct")
+
+			args := tt.args
+			if tt.zoneMap != "" {
+				path := filepath.Join(t.TempDir(), "zones.txt")
+				if err := os.WriteFile(path, []byte(tt.zoneMap), 0644); err != nil {
+					t.Fatalf("Failed to write zone map: %v", err)
+				}
+				if tt.config {
+					runGit(t, repoDir, nil, "config", "gitcommit.zoneMap", path)
+				} else {
+					args = append(args, "--map", path)
+				}
+			}
+
+			output, err := runGitcommit(t, repoDir, append([]string{"tz-normalize"}, args...)...)
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(output, "✓ Rewrote") {
+				t.Errorf("Expected success message, got: %s", output)
+			}
+
+			if offsets := runGit(t, repoDir, nil, "log", "-3", "--format=%ad %cd", "--date=format:%z"); offsets != tt.expected {
+				t.Errorf("Expected offsets:\n%s\ngot:\n%s", tt.expected, offsets)
+			}
+			if newInstants := runGit(t, repoDir, nil, "log", "--format=%at %ct"); newInstants != instants {
+				t.Errorf("Expected instants to be kept\nbefore:\n%s\nafter:\n%s", instants, newInstants)
+			}
+			if newShape := historyShape(t, repoDir); newShape != shape {
+				t.Errorf("Expected trees, identities and subjects to be kept\nbefore:\n%s\nafter:\n%s", shape, newShape)
+			}
+		})
+	}
+}
+
+// TestTZNormalizeReport tests that --dry-run lists the commits that would change.
+func TestTZNormalizeReport(t *testing.T) {
+	repoDir := setupZonesRepo(t)
+	defer os.RemoveAll(repoDir)
+	head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	output, err := runGitcommit(t, repoDir, "tz-normalize", "HEAD~3", "--to", "Europe/Paris", "--dry-run")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{"would change 2 of 3 commit(s)", "+0200 → +0100", "+0000 → +0200", "Add summer.txt"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "Add other.txt") {
+		t.Errorf("Expected the unchanged commit to be left out, got: %s", output)
+	}
+	if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+		t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+	}
+
+	// Nothing left to change
+	if output, err := runGitcommit(t, repoDir, "tz-normalize", "HEAD~1", "--to", "+0200", "--dry-run"); err != nil ||
+		!strings.Contains(output, "already have the requested zone offsets") {
+		t.Errorf("Expected nothing to change, got: %v %s", err, output)
+	}
+}
+
+// TestTZNormalizeErrors tests that invalid zones and maps leave history untouched.
+func TestTZNormalizeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		zoneMap  string
+		args     []string
+		expected string
+	}{
+		{name: "no zone", args: []string{"HEAD~3"}, expected: "Give the zone with --to"},
+		{name: "unknown location", args: []string{"HEAD~3", "--to", "Mars/Olympus"}, expected: "Invalid zone"},
+		{name: "invalid offset", args: []string{"HEAD~3", "--to", "+25"}, expected: "Invalid zone"},
+		{name: "zone missing from map", zoneMap: "other@example.com\n", args: []string{"HEAD~3"}, expected: "zones.txt:1"},
+		{name: "invalid zone in map", zoneMap: "\nother@example.com Paris\n", args: []string{"HEAD~3"}, expected: "zones.txt:2"},
+		{name: "missing map", args: []string{"HEAD~3", "--map", "missing.txt"}, expected: "Cannot read the zone map"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupZonesRepo(t)
+			defer os.RemoveAll(repoDir)
+			head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+			args := tt.args
+			if tt.zoneMap != "" {
+				path := filepath.Join(t.TempDir(), "zones.txt")
+				if err := os.WriteFile(path, []byte(tt.zoneMap), 0644); err != nil {
+					t.Fatalf("Failed to write zone map: %v", err)
+				}
+				args = append(args, "--map", path)
+			}
+
+			output, err := runGitcommit(t, repoDir, append([]string{"tz-normalize"}, args...)...)
+			if err == nil {
+				t.Fatalf("Expected command to fail, got: %s", output)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q, got: %s", tt.expected, output)
+			}
+			if after := runGit(t, repoDir, nil, "rev-parse", "HEAD"); after != head {
+				t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+			}
+		})
+	}
+}
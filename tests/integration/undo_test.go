@@ -0,0 +1,267 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// backupIDs returns the IDs listed by gitcommit undo --list, newest first.
+func backupIDs(t *testing.T, repoDir string) []string {
+	t.Helper()
+	output, err := runGitcommit(t, repoDir, "undo", "--list")
+	if err != nil {
+		t.Fatalf("undo --list failed: %v\nOutput: %s", err, output)
+	}
+
+	var ids []string
+	for _, line := range strings.Split(output, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && strings.HasPrefix(line, "  2") {
+			ids = append(ids, fields[0])
+		}
+	}
+	return ids
+}
+
+// TestUndoRewrite tests that undo restores a rewritten branch, and that a
+// second undo reverts the first.
+func TestUndoRewrite(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	oldHead := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	if output, err := runGitcommit(t, repoDir, "redate", "HEAD~3", "--start", "2025-02-01 09:00:00"); err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	newHead := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	output, err := runGitcommit(t, repoDir, "undo")
+	if err != nil {
+		t.Fatalf("undo failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "✓ Restored backup") || !strings.Contains(output, "gitcommit redate: HEAD~3") {
+		t.Errorf("Expected the restored backup to be named, got: %s", output)
+	}
+	if head := runGit(t, repoDir, nil, "rev-parse", "HEAD"); head != oldHead {
+		t.Errorf("Expected HEAD back at %s, got %s", oldHead, head)
+	}
+	if status := runGit(t, repoDir, nil, "status", "--porcelain"); status != "" {
+		t.Errorf("Expected a clean working tree, got:\n%s", status)
+	}
+
+	if output, err := runGitcommit(t, repoDir, "undo"); err != nil {
+		t.Fatalf("second undo failed: %v\nOutput: %s", err, output)
+	}
+	if head := runGit(t, repoDir, nil, "rev-parse", "HEAD"); head != newHead {
+		t.Errorf("Expected the second undo to restore %s, got %s", newHead, head)
+	}
+}
+
+// TestUndoCommitAndTag tests undoing a new commit, softly, and a new tag,
+// which restoring deletes.
+func TestUndoCommitAndTag(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	oldHead := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	if err := os.WriteFile(filepath.Join(repoDir, "four.txt"), []byte("four\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "four.txt")
+	if output, err := runGitcommit(t, repoDir, "2025-01-05 10:00:00", "Add four.txt"); err != nil {
+		t.Fatalf("commit failed: %v\nOutput: %s", err, output)
+	}
+	if output, err := runGitcommit(t, repoDir, "tag", "2025-01-05 11:00:00", "v1.0", "-m", "Release"); err != nil {
+		t.Fatalf("tag failed: %v\nOutput: %s", err, output)
+	}
+
+	// The latest backup is the tag's
+	if output, err := runGitcommit(t, repoDir, "undo"); err != nil || !strings.Contains(output, "(none)") {
+		t.Fatalf("Expected the tag to be deleted, got: %v %s", err, output)
+	}
+	if tags := runGit(t, repoDir, nil, "tag", "--list"); tags != "" {
+		t.Errorf("Expected no tags, got %q", tags)
+	}
+
+	// The commit's backup is now the third newest, after the undo's own
+	ids := backupIDs(t, repoDir)
+	if len(ids) < 3 {
+		t.Fatalf("Expected at least 3 backups, got %v", ids)
+	}
+	if output, err := runGitcommit(t, repoDir, "undo", "--soft", ids[2]); err != nil {
+		t.Fatalf("undo --soft failed: %v\nOutput: %s", err, output)
+	}
+	if head := runGit(t, repoDir, nil, "rev-parse", "HEAD"); head != oldHead {
+		t.Errorf("Expected HEAD back at %s, got %s", oldHead, head)
+	}
+	if status := runGit(t, repoDir, nil, "status", "--porcelain"); status != "A  four.txt" {
+		t.Errorf("Expected the undone commit's file to stay staged, got %q", status)
+	}
+}
+
+// TestUndoAfterFailedCommit tests that a commit rejected by a hook leaves no
+// backup, so that undo restores the last commit that was made.
+func TestUndoAfterFailedCommit(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	for i, message := range []string{"one", "two"} {
+		date := fmt.Sprintf("2025-01-0%d 10:00:00", i+1)
+		if output, err := runGitcommit(t, repoDir, "--allow-empty", date, message); err != nil {
+			t.Fatalf("commit %s failed: %v\nOutput: %s", message, err, output)
+		}
+	}
+	backups := len(backupIDs(t, repoDir))
+
+	hook := filepath.Join(repoDir, ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatalf("Failed to install hook: %v", err)
+	}
+	if output, err := runGitcommit(t, repoDir, "--allow-empty", "2025-01-03 10:00:00", "three"); err == nil {
+		t.Fatalf("Expected the hook to reject the commit, got: %s", output)
+	}
+	if ids := backupIDs(t, repoDir); len(ids) != backups {
+		t.Errorf("Expected the failed commit to leave no backup, got %d backups instead of %d", len(ids), backups)
+	}
+
+	if output, err := runGitcommit(t, repoDir, "undo"); err != nil {
+		t.Fatalf("undo failed: %v\nOutput: %s", err, output)
+	}
+	if subject := runGit(t, repoDir, nil, "log", "-1", "--format=%s"); subject != "one" {
+		t.Errorf("Expected undo to remove the commit two, HEAD is now %q", subject)
+	}
+}
+
+// TestUndoAllOrNothing tests that when the checked-out branch cannot be
+// restored, the other refs of the backup are left where they were too.
+func TestUndoAllOrNothing(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	runGit(t, repoDir, nil, "branch", "archive", "HEAD~1")
+
+	output, err := runGitcommit(t, repoDir, "redate", "HEAD~3", "--start", "2025-02-01 09:00:00", "--update-branches")
+	if err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	archive := runGit(t, repoDir, nil, "rev-parse", "archive")
+	commitDatedFile(t, repoDir, "four.txt", "2025-03-01T10:00:00")
+	if err := os.WriteFile(filepath.Join(repoDir, "four.txt"), []byte("local\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	before := backupIDs(t, repoDir)
+
+	output, err = runGitcommit(t, repoDir, "undo")
+	if err == nil || !strings.Contains(output, "Cannot restore refs/heads/main") {
+		t.Fatalf("Expected undo to refuse overwriting local changes, got: %v %s", err, output)
+	}
+	if after := runGit(t, repoDir, nil, "rev-parse", "archive"); after != archive {
+		t.Errorf("Expected branch archive to stay at %s, got %s", archive, after)
+	}
+	if after := backupIDs(t, repoDir); len(after) != len(before) {
+		t.Errorf("Expected no backup for the failed undo, got %d backups instead of %d", len(after), len(before))
+	}
+}
+
+// TestUndoReasonWithoutReflog tests that backups keep their reason when
+// the reflogs are gone, as they are with core.logAllRefUpdates=false.
+func TestUndoReasonWithoutReflog(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+	oldHead := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	if output, err := runGitcommit(t, repoDir, "redate", "HEAD~3", "--start", "2025-02-01 09:00:00"); err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	if err := os.RemoveAll(filepath.Join(repoDir, ".git", "logs")); err != nil {
+		t.Fatalf("Failed to remove the reflogs: %v", err)
+	}
+
+	output, err := runGitcommit(t, repoDir, "undo", "--list")
+	if err != nil || !strings.Contains(output, "gitcommit redate: HEAD~3") {
+		t.Errorf("Expected the reason in the backup list, got: %v %s", err, output)
+	}
+	if output, err := runGitcommit(t, repoDir, "undo"); err != nil {
+		t.Fatalf("undo failed: %v\nOutput: %s", err, output)
+	}
+	if head := runGit(t, repoDir, nil, "rev-parse", "HEAD"); head != oldHead {
+		t.Errorf("Expected HEAD back at %s, got %s", oldHead, head)
+	}
+}
+
+// TestUndoPrune tests that backups beyond gitcommit.backupMaxCount are pruned.
+func TestUndoPrune(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	for _, start := range []string{"2025-02-01 09:00:00", "2025-03-01 09:00:00", "2025-04-01 09:00:00"} {
+		if output, err := runGitcommit(t, repoDir, "redate", "HEAD~3", "--start", start); err != nil {
+			t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+		}
+	}
+	if ids := backupIDs(t, repoDir); len(ids) != 3 {
+		t.Fatalf("Expected 3 backups, got %v", ids)
+	}
+
+	runGit(t, repoDir, nil, "config", "gitcommit.backupMaxCount", "2")
+	output, err := runGitcommit(t, repoDir, "undo", "--prune")
+	if err != nil || !strings.Contains(output, "Pruned 1 backup(s)") {
+		t.Fatalf("Expected one backup pruned, got: %v %s", err, output)
+	}
+	if ids := backupIDs(t, repoDir); len(ids) != 2 {
+		t.Errorf("Expected 2 backups left, got %v", ids)
+	}
+
+	// Pruning also follows each new backup
+	runGit(t, repoDir, nil, "config", "gitcommit.backupMaxCount", "1")
+	if output, err := runGitcommit(t, repoDir, "redate", "HEAD~3", "--start", "2025-05-01 09:00:00"); err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	if ids := backupIDs(t, repoDir); len(ids) != 1 {
+		t.Errorf("Expected only the newest backup, got %v", ids)
+	}
+}
+
+// TestUndoErrors tests that undo refuses what it cannot restore safely.
+func TestUndoErrors(t *testing.T) {
+	repoDir := setupRedateRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	if output, err := runGitcommit(t, repoDir, "undo"); err == nil || !strings.Contains(output, "Nothing to undo") {
+		t.Errorf("Expected no backup to undo, got: %v %s", err, output)
+	}
+
+	if output, err := runGitcommit(t, repoDir, "redate", "HEAD~3", "--start", "2025-02-01 09:00:00"); err != nil {
+		t.Fatalf("redate failed: %v\nOutput: %s", err, output)
+	}
+	head := runGit(t, repoDir, nil, "rev-parse", "HEAD")
+
+	if output, err := runGitcommit(t, repoDir, "undo", "19990101"); err == nil || !strings.Contains(output, "Unknown backup") {
+		t.Errorf("Expected an unknown backup, got: %v %s", err, output)
+	}
+	if output, err := runGitcommit(t, repoDir, "undo", "--list", "--prune"); err == nil {
+		t.Errorf("Expected --list and --prune to conflict, got: %s", output)
+	}
+
+	// Local changes to files the restored commit changes are not overwritten
+	if err := os.WriteFile(filepath.Join(repoDir, "four.txt"), []byte("four\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	runGit(t, repoDir, nil, "add", "four.txt")
+	if output, err := runGitcommit(t, repoDir, "2025-05-01 10:00:00", "Add four.txt"); err != nil {
+		t.Fatalf("commit failed: %v\nOutput: %s", err, output)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "four.txt"), []byte("local\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if output, err := runGitcommit(t, repoDir, "undo"); err == nil || !strings.Contains(output, "Cannot restore refs/heads/main") {
+		t.Errorf("Expected undo to refuse overwriting local changes, got: %v %s", err, output)
+	}
+	if content, _ := os.ReadFile(filepath.Join(repoDir, "four.txt")); string(content) != "local\n" {
+		t.Errorf("Expected the local change to be kept, got %q", content)
+	}
+	if after := runGit(t, repoDir, nil, "rev-parse", "HEAD~1"); after != head {
+		t.Errorf("Expected HEAD to stay on the new commit after %s, got %s", head, after)
+	}
+}
@@ -173,3 +173,237 @@ func TestNoRepositoryError(t *testing.T) {
 		t.Errorf("Expected error message to contain repository error, got: %s", outputStr)
 	}
 }
+
+// TestChronologyBasis tests that --chronology-basis selects which date of the last commit is checked.
+func TestChronologyBasis(t *testing.T) {
+	tests := []struct {
+		name        string
+		basisFlag   []string
+		expectError bool
+	}{
+		{name: "default basis is max", basisFlag: nil, expectError: true},
+		{name: "max basis", basisFlag: []string{"--chronology-basis=max"}, expectError: true},
+		{name: "committer basis", basisFlag: []string{"--chronology-basis=committer"}, expectError: true},
+		{name: "author basis", basisFlag: []string{"--chronology-basis=author"}, expectError: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTestRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			// Simulate a rebased commit: authored in January, committed in March
+			runGit(t, repoDir, []string{
+				"GIT_AUTHOR_DATE=2025-01-10T09:00:00",
+				"GIT_COMMITTER_DATE=2025-03-01T18:30:00",
+			}, "commit", "--allow-empty", "-m", "Rebased commit")
+
+			testFile := filepath.Join(repoDir, "test.txt")
+			if err := os.WriteFile(testFile, []byte("content"), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			runGit(t, repoDir, nil, "add", "test.txt")
+
+			// Between the author and committer dates of the last commit
+			args := append(tt.basisFlag, "2025-02-01 12:00:00", "Between both dates")
+			cmd := exec.Command(getBinaryPath(t), args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			outputStr := string(output)
+
+			if !tt.expectError {
+				if err != nil {
+					t.Fatalf("Expected success, got: %v\nOutput: %s", err, outputStr)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected chronology violation, but command succeeded")
+			}
+			for _, expected := range []string{"Chronology violation", "Last author date:", "Last committer date:"} {
+				if !strings.Contains(outputStr, expected) {
+					t.Errorf("Expected error message to contain %q, got: %s", expected, outputStr)
+				}
+			}
+		})
+	}
+}
+
+// TestInvalidChronologyBasis tests that unknown --chronology-basis values are rejected.
+func TestInvalidChronologyBasis(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	cmd := exec.Command(getBinaryPath(t), "--chronology-basis=newest", "2025-02-05 20:19:19", "Test")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Fatalf("Expected error for invalid chronology basis, but command succeeded")
+	}
+	if !strings.Contains(string(output), "Invalid chronology basis") {
+		t.Errorf("Expected error message to contain %q, got: %s", "Invalid chronology basis", output)
+	}
+}
+
+// TestChronologyAgainstRefs tests that --against and --against-all-refs raise the chronology floor.
+func TestChronologyAgainstRefs(t *testing.T) {
+	tests := []struct {
+		name          string
+		flags         []string
+		expectError   bool
+		expectedError []string
+	}{
+		{
+			name:        "only HEAD by default",
+			flags:       nil,
+			expectError: false,
+		},
+		{
+			name:          "against main",
+			flags:         []string{"--against", "main"},
+			expectError:   true,
+			expectedError: []string{"Chronology violation", "Floor set by:        main"},
+		},
+		{
+			name:          "against all refs",
+			flags:         []string{"--against-all-refs"},
+			expectError:   true,
+			expectedError: []string{"Chronology violation", "refs/heads/main"},
+		},
+		{
+			name:          "unknown ref",
+			flags:         []string{"--against", "no-such-branch"},
+			expectError:   true,
+			expectedError: []string{"Unknown ref", "no-such-branch"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupTestRepo(t)
+			defer os.RemoveAll(repoDir)
+
+			// main moves ahead of the feature branch
+			runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+			runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-01-01T10:00:00", "GIT_COMMITTER_DATE=2025-01-01T10:00:00"},
+				"commit", "--allow-empty", "-m", "Base")
+			runGit(t, repoDir, nil, "branch", "feature")
+			runGit(t, repoDir, []string{"GIT_AUTHOR_DATE=2025-03-01T10:00:00", "GIT_COMMITTER_DATE=2025-03-01T10:00:00"},
+				"commit", "--allow-empty", "-m", "Newer work on main")
+			runGit(t, repoDir, nil, "checkout", "-q", "feature")
+
+			testFile := filepath.Join(repoDir, "feature.txt")
+			if err := os.WriteFile(testFile, []byte("feature"), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			runGit(t, repoDir, nil, "add", "feature.txt")
+
+			// After the feature branch tip, but before main
+			args := append(tt.flags, "2025-02-01 12:00:00", "Feature commit")
+			cmd := exec.Command(getBinaryPath(t), args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			outputStr := string(output)
+
+			if !tt.expectError {
+				if err != nil {
+					t.Fatalf("Expected success, got: %v\nOutput: %s", err, outputStr)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected error, but command succeeded")
+			}
+			for _, expected := range tt.expectedError {
+				if !strings.Contains(outputStr, expected) {
+					t.Errorf("Expected error message to contain %q, got: %s", expected, outputStr)
+				}
+			}
+		})
+	}
+}
+
+// TestChronologyMergeTopology tests that every side of a merge counts towards the chronology floor.
+func TestChronologyMergeTopology(t *testing.T) {
+	dated := func(date string) []string {
+		return []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}
+	}
+
+	// setupSides creates a main branch and a side branch whose tip is newer than main.
+	setupSides := func(t *testing.T) string {
+		t.Helper()
+		repoDir := setupTestRepo(t)
+		runGit(t, repoDir, nil, "checkout", "-q", "-b", "main")
+		runGit(t, repoDir, dated("2025-01-01T10:00:00"), "commit", "--allow-empty", "-m", "Base")
+		runGit(t, repoDir, nil, "checkout", "-q", "-b", "side")
+		if err := os.WriteFile(filepath.Join(repoDir, "side.txt"), []byte("side"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		runGit(t, repoDir, nil, "add", "side.txt")
+		runGit(t, repoDir, dated("2025-03-01T10:00:00"), "commit", "-m", "Newer side work")
+		runGit(t, repoDir, nil, "checkout", "-q", "main")
+		return repoDir
+	}
+
+	tests := []struct {
+		name          string
+		inProgress    bool // leave the merge uncommitted so MERGE_HEAD is present
+		flags         []string
+		expectError   bool
+		expectedFloor string
+	}{
+		{name: "merged parent counts by default", flags: nil, expectError: true, expectedFloor: "HEAD^2"},
+		{name: "depth zero checks HEAD only", flags: []string{"--chronology-depth=0"}, expectError: false},
+		{name: "MERGE_HEAD counts while merging", inProgress: true, flags: nil, expectError: true, expectedFloor: "MERGE_HEAD"},
+		{
+			name:          "MERGE_HEAD counts at depth zero",
+			inProgress:    true,
+			flags:         []string{"--chronology-depth=0"},
+			expectError:   true,
+			expectedFloor: "MERGE_HEAD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := setupSides(t)
+			defer os.RemoveAll(repoDir)
+
+			if tt.inProgress {
+				runGit(t, repoDir, dated("2025-02-01T10:00:00"), "merge", "--no-ff", "--no-commit", "side")
+			} else {
+				// A merge commit dated before the tip it merges
+				runGit(t, repoDir, dated("2025-02-01T10:00:00"), "merge", "--no-ff", "-m", "Merge side", "side")
+				if err := os.WriteFile(filepath.Join(repoDir, "main.txt"), []byte("main"), 0644); err != nil {
+					t.Fatalf("Failed to create test file: %v", err)
+				}
+				runGit(t, repoDir, nil, "add", "main.txt")
+			}
+
+			args := append(tt.flags, "2025-02-15 12:00:00", "After the merge commit")
+			cmd := exec.Command(getBinaryPath(t), args...)
+			cmd.Dir = repoDir
+			output, err := cmd.CombinedOutput()
+			outputStr := string(output)
+
+			if !tt.expectError {
+				if err != nil {
+					t.Fatalf("Expected success, got: %v\nOutput: %s", err, outputStr)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected chronology violation, but command succeeded")
+			}
+			for _, expected := range []string{"Chronology violation", "Floor set by:        " + tt.expectedFloor} {
+				if !strings.Contains(outputStr, expected) {
+					t.Errorf("Expected error message to contain %q, got: %s", expected, outputStr)
+				}
+			}
+		})
+	}
+}